@@ -0,0 +1,118 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	kAdvisorHeadroom = 2 // 推荐的序列号位数需要能够容纳峰值的倍数
+)
+
+var (
+	ErrNoSamples = errors.New("snowflake: no sequence samples, use WithSequenceSampler and generate ids for a while before asking for advice")
+)
+
+// kAdvisorUnits AdviseLayout 比较的时间单位
+var kAdvisorUnits = []time.Duration{time.Millisecond, 10 * time.Millisecond, 100 * time.Millisecond, time.Second}
+
+// UnitAdvice 使用某个时间单位时需要的序列号位数以及时间戳部分能够使用的时长
+type UnitAdvice struct {
+	Unit         time.Duration `json:"unit"`
+	Peak         int64         `json:"peak"`          // 每个时间单位生成的 id 数量的峰值，10ms 和 100ms 为按照每毫秒和每秒的峰值估算的上限
+	SequenceBits uint8         `json:"sequence_bits"` // 按照峰值的 2 倍需要的序列号位数
+	TimeBits     uint8         `json:"time_bits"`
+	Years        float64       `json:"years"` // 时间戳部分从时间偏移量开始能够使用的年数
+}
+
+// LayoutAdvice AdviseLayout 的结果
+type LayoutAdvice struct {
+	Seconds            int          `json:"seconds"`              // 统计的秒数
+	PeakPerMillisecond int64        `json:"peak_per_millisecond"` // 单个毫秒生成的 id 数量的峰值
+	PeakPerSecond      int64        `json:"peak_per_second"`      // 每秒生成的 id 数量的峰值
+	Current            Layout       `json:"current"`
+	CurrentYears       float64      `json:"current_years"` // 时间戳部分能够使用的年数
+	Recommended        Layout       `json:"recommended"`   // 按照峰值的 2 倍调整序列号位数，其余的位数用于时间戳
+	RecommendedYears   float64      `json:"recommended_years"`
+	Units              []UnitAdvice `json:"units"` // 使用更粗的时间单位时的估算，生成器的时间单位固定为毫秒，仅作为设计新布局时的参考
+}
+
+// AdviseLayout 按照 SequenceSampler 统计的生成速度，给出序列号位数的建议，以及使用更粗的时间单位时时间戳部分能够使用的时长。
+//
+// 统计的时长需要覆盖业务的高峰，否则推荐的序列号位数可能不足。修改布局之后生成的 id 与之前的 id 不兼容，需要同时修改时间偏移量或者布局版本。
+func AdviseLayout(report SequenceReport, current Layout) LayoutAdvice {
+	var advice = LayoutAdvice{}
+	advice.Seconds = report.Seconds
+	advice.PeakPerMillisecond = report.MaxSequence.Max
+	advice.PeakPerSecond = report.IDs.Max
+	advice.Current = current
+	advice.CurrentYears = yearsOf(current.Time(), time.Millisecond)
+	advice.Recommended = current
+	advice.Recommended.Order = append([]Part(nil), current.Order...)
+
+	// 不属于序列号部分的位数，如 WithProcessSlots 划分出的进程槽位
+	var reserved uint8
+	if report.Capacity > 0 {
+		if bits := bitsFor(report.Capacity - 1); bits < current.Sequence {
+			reserved = current.Sequence - bits
+		}
+	}
+	var fixed = current.Time() + current.Sequence - reserved
+	for _, unit := range kAdvisorUnits {
+		var u = UnitAdvice{Unit: unit}
+		switch unit {
+		case time.Millisecond:
+			u.Peak = advice.PeakPerMillisecond
+		case time.Second:
+			u.Peak = advice.PeakPerSecond
+		default:
+			u.Peak = advice.PeakPerMillisecond * int64(unit/time.Millisecond)
+			if u.Peak > advice.PeakPerSecond {
+				u.Peak = advice.PeakPerSecond
+			}
+		}
+		u.SequenceBits = bitsFor(u.Peak * kAdvisorHeadroom)
+		if u.SequenceBits < fixed {
+			u.TimeBits = fixed - u.SequenceBits
+		}
+		u.Years = yearsOf(u.TimeBits, unit)
+		advice.Units = append(advice.Units, u)
+	}
+
+	advice.Recommended.Sequence = advice.Units[0].SequenceBits + reserved
+	if !advice.Recommended.valid() {
+		advice.Recommended = current
+	}
+	advice.RecommendedYears = yearsOf(advice.Recommended.Time(), time.Millisecond)
+	return advice
+}
+
+// AdviseLayout 使用通过 WithSequenceSampler 设置的 SequenceSampler 统计的结果给出布局的建议，没有统计结果时返回 ErrNoSamples
+func (this *SnowFlake) AdviseLayout() (LayoutAdvice, error) {
+	if this.sampler == nil {
+		return LayoutAdvice{}, ErrNoSamples
+	}
+	var report = this.sampler.Report()
+	if report.Seconds == 0 {
+		return LayoutAdvice{}, ErrNoSamples
+	}
+	return AdviseLayout(report, this.layout), nil
+}
+
+// bitsFor 获取能够表示 n 的最少位数，至少为 1
+func bitsFor(n int64) uint8 {
+	var bits uint8 = 1
+	for bits < 63 && n >= 1<<bits {
+		bits++
+	}
+	return bits
+}
+
+// yearsOf 获取 bits 位的时间戳按照 unit 能够表示的年数
+func yearsOf(bits uint8, unit time.Duration) float64 {
+	if bits == 0 {
+		return 0
+	}
+	return math.Ldexp(unit.Seconds(), int(bits)) / (365.25 * 24 * 3600)
+}
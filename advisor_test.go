@@ -0,0 +1,62 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestAdviseLayout(t *testing.T) {
+	var sampler = NewSequenceSampler(0)
+	var s, _ = New(WithTimeOffset(testEpoch), WithSequenceSampler(sampler))
+	if _, err := s.AdviseLayout(); !errors.Is(err, ErrNoSamples) {
+		t.Fatalf("AdviseLayout without samples: %v", err)
+	}
+
+	var now = s.anchor.Truncate(time.Second)
+	s.clock = func() time.Time { return now }
+	for ms := 0; ms < 3; ms++ {
+		for i := 0; i < 10; i++ {
+			s.Next()
+		}
+		now = now.Add(time.Millisecond)
+	}
+	now = now.Add(time.Second)
+	s.Next()
+
+	var advice, err = s.AdviseLayout()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if advice.PeakPerMillisecond != 10 || advice.PeakPerSecond != 30 {
+		t.Fatalf("peaks = %d/ms %d/s", advice.PeakPerMillisecond, advice.PeakPerSecond)
+	}
+	// 峰值的 2 倍为 20，需要 5 位序列号，剩余的 7 位用于时间戳
+	if advice.Recommended.Sequence != 5 || advice.Recommended.Time() != DefaultLayout.Time()+7 {
+		t.Fatalf("recommended = %+v", advice.Recommended)
+	}
+	if advice.CurrentYears < 69 || advice.CurrentYears > 70 || advice.RecommendedYears != advice.CurrentYears*128 {
+		t.Fatalf("years = %v, current %v", advice.RecommendedYears, advice.CurrentYears)
+	}
+
+	var units = map[time.Duration]UnitAdvice{}
+	for _, u := range advice.Units {
+		units[u.Unit] = u
+	}
+	if u := units[10*time.Millisecond]; u.Peak != 30 || u.SequenceBits != 6 || u.TimeBits != 47 {
+		t.Fatalf("10ms = %+v", u)
+	}
+	if u := units[time.Second]; u.TimeBits != 47 || u.Years != math.Ldexp(1, 47)/(365.25*24*3600) {
+		t.Fatalf("1s = %+v", u)
+	}
+}
+
+func TestAdviseLayout_ProcessSlots(t *testing.T) {
+	// 进程槽位占用的 2 位不属于序列号，推荐的布局需要保留
+	var report = SequenceReport{Seconds: 1, Capacity: 1 << 10, IDs: Percentiles{Max: 100}, MaxSequence: Percentiles{Max: 1}}
+	var advice = AdviseLayout(report, DefaultLayout)
+	if advice.Recommended.Sequence != 4 {
+		t.Fatalf("recommended sequence = %d", advice.Recommended.Sequence)
+	}
+}
@@ -0,0 +1,48 @@
+package snowflake
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	granularityOnce sync.Once
+	granularity     time.Duration
+)
+
+// WithBorrowAhead 设置当前毫秒的序列号用完时，可以提前使用的未来时间戳的范围，在此范围内不等待时钟前进。
+//
+// 时钟的精度较低时（如 Windows 默认的定时器精度约为 15.6ms），时间戳每隔一段时间才会变化，序列号会在两次变化之间提前用完，
+// 提前使用之后的时间戳会比时钟快，时钟追上之前不会按照时钟回拨处理。默认使用创建第一个生成器时测量的时钟精度，
+// 在 Linux 等时钟精度为纳秒的平台上为 0，设置为 0 时禁用。
+func WithBorrowAhead(d time.Duration) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		if d < 0 {
+			d = 0
+		}
+		s.ahead = int64(d / time.Millisecond)
+		return nil
+	})
+}
+
+// clockGranularity 获取时钟的精度，只在第一次调用时测量
+func clockGranularity() time.Duration {
+	granularityOnce.Do(func() {
+		granularity = measureGranularity()
+	})
+	return granularity
+}
+
+// borrowAhead 当前毫秒的序列号用完时提前使用下一毫秒，只要不超过 WithBorrowAhead 设置的范围，调用方需要持有 mu
+func (this *SnowFlake) borrowAhead(mill int64) (int64, bool) {
+	if this.ahead <= 0 || this.millisecond+1-mill > this.ahead {
+		return 0, false
+	}
+	this.borrowedUntil = this.millisecond + 1
+	return this.millisecond + 1, true
+}
+
+// heldAhead 检查时钟落后于上一次的时间戳是否是因为提前使用了时间戳，而不是时钟回拨，调用方需要持有 mu
+func (this *SnowFlake) heldAhead(mill int64) bool {
+	return this.millisecond <= this.borrowedUntil && this.millisecond-mill <= this.ahead
+}
@@ -0,0 +1,60 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithBorrowAhead(t *testing.T) {
+	// 模拟 Windows 的时钟，读数每 16ms 变化一次
+	var s, _ = New(WithBorrowAhead(16*time.Millisecond), WithMaxWait(5*time.Millisecond))
+	var now = time.Now().Add(time.Millisecond)
+	s.clock = func() time.Time { return now }
+
+	var first = s.Next()
+	var last = first
+	for i := 1; i < 17*int(kMaxSequence+1); i++ {
+		var id, err = s.NextID()
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if id <= last {
+			t.Fatalf("%d: id %d after %d", i, id, last)
+		}
+		last = id
+	}
+	if d := s.TimeOf(last).Sub(s.TimeOf(first)); d != 16*time.Millisecond {
+		t.Fatalf("borrowed %v ahead", d)
+	}
+	// 超过提前使用的范围之后需要等待时钟前进
+	if _, err := s.NextID(); !errors.Is(err, ErrWaitTimeout) {
+		t.Fatalf("NextID() error = %v, want %v", err, ErrWaitTimeout)
+	}
+
+	// 时钟追上之前不是时钟回拨
+	now = now.Add(5 * time.Millisecond)
+	if id := s.Next(); s.TimeOf(id).Sub(s.TimeOf(first)) != 17*time.Millisecond {
+		t.Fatalf("id %s after the clock moved", s.Explain(id))
+	}
+	if stats := s.Stats(); stats.Rollbacks != 0 || stats.ClockHolds != 0 {
+		t.Fatalf("stats = %+v", stats)
+	}
+
+	// 提前使用的时间戳之外的回退仍然是时钟回拨
+	now = now.Add(-50 * time.Millisecond)
+	s.Next()
+	if stats := s.Stats(); stats.Rollbacks != 1 {
+		t.Fatalf("stats after moving back = %+v", stats)
+	}
+}
+
+func TestClockGranularity(t *testing.T) {
+	var s, _ = New()
+	if s.ahead != int64(clockGranularity()/time.Millisecond) {
+		t.Fatalf("ahead = %d, granularity = %v", s.ahead, clockGranularity())
+	}
+	if s, _ = New(WithBorrowAhead(0)); s.ahead != 0 {
+		t.Fatalf("ahead = %d after WithBorrowAhead(0)", s.ahead)
+	}
+}
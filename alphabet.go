@@ -0,0 +1,84 @@
+package snowflake
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidAlphabet = errors.New("snowflake: alphabet needs 2 to 256 unique characters")
+	ErrInvalidEncoded  = errors.New("snowflake: invalid encoded id")
+)
+
+// Encoding 使用自定义的字母表对 id 进行编码，与 encoding/base32 类似，用于与使用了其它字母表的旧系统互通，
+// 如 base58 可以使用 NewEncoding("123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz")。
+//
+// 编码结果是 id 在 len(alphabet) 进制下的表示，第一个字符表示 0，不会补齐长度。可以在多个 goroutine 中同时使用。
+type Encoding struct {
+	alphabet string
+	index    [256]int16
+	base     uint64
+	name     string // 用于 ParseError 的编码名称
+	invalid  error
+}
+
+// NewEncoding 使用 alphabet 创建 Encoding，alphabet 中的每个字节为一个字符，不能重复
+func NewEncoding(alphabet string) (*Encoding, error) {
+	if len(alphabet) < 2 || len(alphabet) > 256 {
+		return nil, ErrInvalidAlphabet
+	}
+	var e = &Encoding{}
+	e.alphabet = alphabet
+	e.base = uint64(len(alphabet))
+	e.name = "encoded"
+	e.invalid = ErrInvalidEncoded
+	for i := range e.index {
+		e.index[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		if e.index[alphabet[i]] >= 0 {
+			return nil, ErrInvalidAlphabet
+		}
+		e.index[alphabet[i]] = int16(i)
+	}
+	return e, nil
+}
+
+// Alphabet 获取 Encoding 使用的字母表
+func (this *Encoding) Alphabet() string {
+	return this.alphabet
+}
+
+// Encode 获取 id 的编码，id 需要大于等于 0
+func (this *Encoding) Encode(s int64) string {
+	if s == 0 {
+		return this.alphabet[:1]
+	}
+	var b [64]byte
+	var i = len(b)
+	var n = uint64(s)
+	for n > 0 {
+		i--
+		b[i] = this.alphabet[n%this.base]
+		n /= this.base
+	}
+	return string(b[i:])
+}
+
+// Parse 解析 Encode 生成的 id，返回的错误为 *ParseError，可以通过 errors.Is 判断是否为 ErrInvalidEncoded
+func (this *Encoding) Parse(s string) (int64, error) {
+	if len(s) == 0 {
+		return 0, parseError(this.name, this.invalid, s, ErrBadLength, -1)
+	}
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		var v = this.index[s[i]]
+		if v < 0 {
+			return 0, parseError(this.name, this.invalid, s, ErrBadCharacter, i)
+		}
+		if n > (1<<63-1-uint64(v))/this.base {
+			return 0, parseError(this.name, this.invalid, s, ErrOutOfRange, -1)
+		}
+		n = n*this.base + uint64(v)
+	}
+	return int64(n), nil
+}
@@ -0,0 +1,49 @@
+package snowflake
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestEncoding(t *testing.T) {
+	var base58, err = NewEncoding("123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var binary, _ = NewEncoding("01")
+	var base62, _ = NewEncoding(kBase62Alphabet)
+
+	var ids = []int64{0, 1, 57, 58, 1465371247374594656, 1<<63 - 1, Next()}
+	for _, id := range ids {
+		for _, e := range []*Encoding{base58, binary, base62} {
+			var s = e.Encode(id)
+			var got, err = e.Parse(s)
+			if err != nil || got != id {
+				t.Fatalf("Parse(%q) = %d, %v, want %d", s, got, err, id)
+			}
+		}
+		if s := binary.Encode(id); s != strconv.FormatInt(id, 2) {
+			t.Fatalf("Encode(%d) = %q, want %q", id, s, strconv.FormatInt(id, 2))
+		}
+		if s := base62.Encode(id); s != Base62(id) {
+			t.Fatalf("Encode(%d) = %q, want %q", id, s, Base62(id))
+		}
+	}
+	if s := base58.Encode(58); s != "21" {
+		t.Fatalf("Encode(58) = %q", s)
+	}
+
+	var bad = []string{"", "0", "I", "l1", "zzzzzzzzzzzzz"}
+	for _, s := range bad {
+		if _, err = base58.Parse(s); !errors.Is(err, ErrInvalidEncoded) {
+			t.Fatalf("Parse(%q) error = %v, want %v", s, err, ErrInvalidEncoded)
+		}
+	}
+
+	for _, alphabet := range []string{"", "a", "abca"} {
+		if _, err = NewEncoding(alphabet); err != ErrInvalidAlphabet {
+			t.Fatalf("NewEncoding(%q) error = %v, want %v", alphabet, err, ErrInvalidAlphabet)
+		}
+	}
+}
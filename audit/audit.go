@@ -0,0 +1,221 @@
+// Package audit 异步记录生成的 id，用于需要证明 id 在何时、由哪个服务生成的合规场景。
+//
+// Writer 在后台批量地将 Record 写入 Sink，Sink 可以是文件、Kafka（参考 contrib/sfkafka）或者自定义的函数。
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+)
+
+var (
+	ErrClosed = errors.New("audit: writer is closed")
+)
+
+// Record 一条生成 id 的记录
+type Record struct {
+	ID    int64     `json:"id"`
+	Time  time.Time `json:"time"`            // 生成 id 的时间
+	Label string    `json:"label,omitempty"` // 调用方的标识，如服务名称或者业务类型
+}
+
+// Sink 保存记录，同一时间只会有一个 goroutine 调用 Write
+type Sink interface {
+	Write(records []Record) error
+}
+
+// SinkFunc 使用函数实现 Sink
+type SinkFunc func(records []Record) error
+
+func (f SinkFunc) Write(records []Record) error {
+	return f(records)
+}
+
+// FileSink 将记录以 JSON Lines 的格式追加到文件中
+type FileSink struct {
+	file *os.File
+}
+
+// NewFileSink 打开 path 指定的文件，文件不存在时创建
+func NewFileSink(path string) (*FileSink, error) {
+	var file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{file: file}, nil
+}
+
+func (this *FileSink) Write(records []Record) error {
+	var buf []byte
+	for _, r := range records {
+		var data, err = json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, data...)
+		buf = append(buf, '\n')
+	}
+	if _, err := this.file.Write(buf); err != nil {
+		return err
+	}
+	return this.file.Sync()
+}
+
+func (this *FileSink) Close() error {
+	return this.file.Close()
+}
+
+type Option func(w *Writer)
+
+// WithBufferSize 设置等待写入的记录数量的上限，默认为 4096，达到上限之后 Record 会阻塞，不会丢弃记录
+func WithBufferSize(size int) Option {
+	return func(w *Writer) {
+		if size > 0 {
+			w.bufferSize = size
+		}
+	}
+}
+
+// WithBatchSize 设置每次写入 Sink 的记录数量的上限，默认为 256
+func WithBatchSize(size int) Option {
+	return func(w *Writer) {
+		if size > 0 {
+			w.batchSize = size
+		}
+	}
+}
+
+// WithFlushInterval 设置记录数量没有达到 WithBatchSize 时，最长等待多久写入 Sink，默认为 1 秒
+func WithFlushInterval(d time.Duration) Option {
+	return func(w *Writer) {
+		if d > 0 {
+			w.interval = d
+		}
+	}
+}
+
+// WithErrorHandler 设置写入 Sink 失败时的回调函数，写入失败的记录会传给回调函数
+func WithErrorHandler(handler func(records []Record, err error)) Option {
+	return func(w *Writer) {
+		w.onError = handler
+	}
+}
+
+// Writer 异步地将记录写入 Sink，可以在多个 goroutine 中同时使用
+type Writer struct {
+	sink       Sink
+	bufferSize int
+	batchSize  int
+	interval   time.Duration
+	onError    func(records []Record, err error)
+
+	mu      sync.RWMutex
+	closed  bool
+	records chan Record
+	done    chan struct{}
+}
+
+func NewWriter(sink Sink, opts ...Option) *Writer {
+	var w = &Writer{}
+	w.sink = sink
+	w.bufferSize = 4096
+	w.batchSize = 256
+	w.interval = time.Second
+	for _, opt := range opts {
+		if opt != nil {
+			opt(w)
+		}
+	}
+	w.records = make(chan Record, w.bufferSize)
+	w.done = make(chan struct{})
+	go w.run()
+	return w
+}
+
+// Record 记录生成的 id，生成时间为当前时间
+func (this *Writer) Record(id int64, label string) error {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	if this.closed {
+		return ErrClosed
+	}
+	this.records <- Record{ID: id, Time: time.Now(), Label: label}
+	return nil
+}
+
+// Close 写入所有等待写入的记录，Sink 实现了 io.Closer 时会关闭 Sink
+func (this *Writer) Close() error {
+	this.mu.Lock()
+	if this.closed {
+		this.mu.Unlock()
+		return nil
+	}
+	this.closed = true
+	close(this.records)
+	this.mu.Unlock()
+
+	<-this.done
+	if closer, ok := this.sink.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (this *Writer) run() {
+	defer close(this.done)
+
+	var ticker = time.NewTicker(this.interval)
+	defer ticker.Stop()
+
+	var batch = make([]Record, 0, this.batchSize)
+	for {
+		select {
+		case r, ok := <-this.records:
+			if !ok {
+				this.flush(batch)
+				return
+			}
+			batch = append(batch, r)
+			if len(batch) >= this.batchSize {
+				batch = this.flush(batch)
+			}
+		case <-ticker.C:
+			batch = this.flush(batch)
+		}
+	}
+}
+
+func (this *Writer) flush(batch []Record) []Record {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := this.sink.Write(batch); err != nil && this.onError != nil {
+		this.onError(append([]Record(nil), batch...), err)
+	}
+	return batch[:0]
+}
+
+type generator struct {
+	snowflake.Generator
+	writer *Writer
+	label  string
+}
+
+// Wrap 记录 g 生成的所有 id，label 为调用方的标识，记录失败时返回 id 和错误
+func Wrap(g snowflake.Generator, writer *Writer, label string) snowflake.Generator {
+	return &generator{Generator: g, writer: writer, label: label}
+}
+
+func (this *generator) NextID() (int64, error) {
+	var id, err = this.Generator.NextID()
+	if err != nil {
+		return id, err
+	}
+	return id, this.writer.Record(id, this.label)
+}
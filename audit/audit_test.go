@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+)
+
+func TestWriter_FileSink(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "audit.log")
+	var sink, err = NewFileSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var w = NewWriter(sink, WithBatchSize(10))
+
+	var s, _ = snowflake.New()
+	var g = Wrap(s, w, "orders")
+	var ids = make(map[int64]bool)
+	for i := 0; i < 95; i++ {
+		var id, err = g.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[id] = true
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = g.NextID(); err != ErrClosed {
+		t.Fatalf("NextID() error = %v, want %v", err, ErrClosed)
+	}
+
+	var file, _ = os.Open(path)
+	defer file.Close()
+	var scanner = bufio.NewScanner(file)
+	var count int
+	for scanner.Scan() {
+		var r Record
+		if err = json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatal(err)
+		}
+		if !ids[r.ID] || r.Label != "orders" || time.Since(r.Time) > time.Minute {
+			t.Fatalf("unexpected record %+v", r)
+		}
+		count++
+	}
+	if count != 95 {
+		t.Fatalf("file has %d records, want 95", count)
+	}
+}
+
+func TestWriter_FlushInterval(t *testing.T) {
+	var mu sync.Mutex
+	var written []Record
+	var w = NewWriter(SinkFunc(func(records []Record) error {
+		mu.Lock()
+		defer mu.Unlock()
+		written = append(written, records...)
+		return nil
+	}), WithFlushInterval(10*time.Millisecond))
+	defer w.Close()
+
+	w.Record(1, "a")
+	for i := 0; i < 100; i++ {
+		mu.Lock()
+		var n = len(written)
+		mu.Unlock()
+		if n == 1 {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("record is not flushed after the interval")
+}
+
+func TestWriter_ErrorHandler(t *testing.T) {
+	var failed []Record
+	var w = NewWriter(SinkFunc(func(records []Record) error {
+		return errors.New("unavailable")
+	}), WithErrorHandler(func(records []Record, err error) {
+		failed = append(failed, records...)
+	}))
+	w.Record(1, "a")
+	w.Record(2, "a")
+	w.Close()
+	if len(failed) != 2 || failed[1].ID != 2 {
+		t.Fatalf("failed records = %+v", failed)
+	}
+}
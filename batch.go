@@ -0,0 +1,82 @@
+package snowflake
+
+import (
+	"errors"
+	"reflect"
+)
+
+var (
+	ErrInvalidBatch = errors.New("snowflake: batch must be a slice of structs or struct pointers")
+	ErrInvalidField = errors.New("snowflake: batch field must be an exported int64 field")
+)
+
+// Fill 为 ids 的每一个元素生成新的 id，整个过程只获取一次锁，适用于批量插入之前一次性分配主键。
+//
+// 返回错误时 ids 中只有部分元素被赋值，不应该再使用。
+func (this *SnowFlake) Fill(ids []int64) error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	var err error
+	for i := range ids {
+		if ids[i], err = this.nextLocked(0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AssignIDs 为 slice 中每一个元素名称为 field 的字段生成新的 id，已经有值（不为 0）的字段会被保留，如：
+//
+//	var orders = []*Order{{Name: "a"}, {Name: "b"}}
+//	s.AssignIDs(orders, "ID")
+//
+// slice 可以是结构体或者结构体指针的切片，字段的类型需要是 int64 或者底层类型为 int64 的类型。
+func (this *SnowFlake) AssignIDs(slice interface{}, field string) error {
+	var rv = reflect.ValueOf(slice)
+	if rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice {
+		return ErrInvalidBatch
+	}
+
+	var fields = make([]reflect.Value, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		var elem = reflect.Indirect(rv.Index(i))
+		if elem.Kind() != reflect.Struct {
+			return ErrInvalidBatch
+		}
+		var f = elem.FieldByName(field)
+		if !f.IsValid() || !f.CanSet() || f.Kind() != reflect.Int64 {
+			return ErrInvalidField
+		}
+		if f.Int() == 0 {
+			fields = append(fields, f)
+		}
+	}
+
+	var ids = make([]int64, len(fields))
+	if err := this.Fill(ids); err != nil {
+		return err
+	}
+	for i, f := range fields {
+		f.SetInt(ids[i])
+	}
+	return nil
+}
+
+// PrependIDs 为 rows 的每一行生成新的 id 并插入到第一列，返回生成的 id。
+//
+// 用于 COPY、LOAD DATA 等无法通过 RETURNING 获取主键的批量写入方式，主键在写入之前就已经确定，
+// 返回的 id 与 rows 一一对应，可以直接用于写入关联的数据。
+func (this *SnowFlake) PrependIDs(rows [][]interface{}) ([]int64, error) {
+	var ids = make([]int64, len(rows))
+	if err := this.Fill(ids); err != nil {
+		return nil, err
+	}
+	for i, row := range rows {
+		rows[i] = append([]interface{}{ids[i]}, row...)
+	}
+	return ids, nil
+}
@@ -0,0 +1,68 @@
+package snowflake
+
+import (
+	"testing"
+)
+
+func TestSnowFlake_Fill(t *testing.T) {
+	var s, _ = New()
+	var ids = make([]int64, 10000)
+	if err := s.Fill(ids); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("ids[%d] = %d is not greater than ids[%d] = %d", i, ids[i], i-1, ids[i-1])
+		}
+	}
+	if stats := s.Stats(); stats.Generated != 10000 {
+		t.Fatalf("Generated = %d, want 10000", stats.Generated)
+	}
+}
+
+func TestSnowFlake_AssignIDs(t *testing.T) {
+	type orderID int64
+	type order struct {
+		ID   orderID
+		Name string
+		note int64
+	}
+
+	var s, _ = New()
+	var orders = []*order{{Name: "a"}, {ID: 7, Name: "b"}, {Name: "c"}}
+	if err := s.AssignIDs(orders, "ID"); err != nil {
+		t.Fatal(err)
+	}
+	if orders[0].ID <= 0 || orders[1].ID != 7 || orders[2].ID <= orders[0].ID {
+		t.Fatalf("IDs = %d, %d, %d", orders[0].ID, orders[1].ID, orders[2].ID)
+	}
+
+	var values = []order{{Name: "a"}}
+	if err := s.AssignIDs(&values, "ID"); err != nil || values[0].ID <= 0 {
+		t.Fatalf("AssignIDs() = %v, ID = %d", err, values[0].ID)
+	}
+
+	if err := s.AssignIDs(values, "Name"); err != ErrInvalidField {
+		t.Fatalf("AssignIDs() error = %v, want %v", err, ErrInvalidField)
+	}
+	if err := s.AssignIDs(values, "note"); err != ErrInvalidField {
+		t.Fatalf("AssignIDs() error = %v, want %v", err, ErrInvalidField)
+	}
+	if err := s.AssignIDs([]int64{0}, "ID"); err != ErrInvalidBatch {
+		t.Fatalf("AssignIDs() error = %v, want %v", err, ErrInvalidBatch)
+	}
+}
+
+func TestSnowFlake_PrependIDs(t *testing.T) {
+	var s, _ = New()
+	var rows = [][]interface{}{{"a", 1}, {"b", 2}}
+	var ids, err = s.PrependIDs(rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, row := range rows {
+		if len(row) != 3 || row[0] != ids[i] {
+			t.Fatalf("rows[%d] = %v, want id %d in the first column", i, row, ids[i])
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package snowflake
+
+import (
+	"errors"
+)
+
+var (
+	ErrBlockNotSupported = errors.New("snowflake: blocks need the sequence in the lowest bits of the layout and no sequence jitter")
+)
+
+// Block 一段连续的 id，包含 [Start, Start+Count) 范围内的 id
+type Block struct {
+	Start int64 `json:"start"`
+	Count int64 `json:"count"`
+}
+
+// NextBlock 生成一段连续的 id，count 为期望的数量。
+//
+// 同一段 id 使用的是同一毫秒内连续的序列号，所以实际的数量可能会小于 count，不会超过当前毫秒剩余的序列号数量。
+func (this *SnowFlake) NextBlock(count int64) (Block, error) {
+	if count <= 0 {
+		return Block{}, nil
+	}
+	if this.bits.sequence.shift != 0 || this.stride != 1 {
+		return Block{}, ErrBlockNotSupported
+	}
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	var millisecond, err = this.tick()
+	if err != nil {
+		return Block{}, err
+	}
+
+	var first, limit int64
+	if this.millisecond == millisecond {
+		first = this.sequence + 1
+		limit = this.bits.sequence.max + 1
+		if this.sequenceStart > this.sequence {
+			limit = this.sequenceStart
+		}
+		if remain := first + this.sequenceCapacity() - this.sequenceUsed(); remain < limit {
+			limit = remain
+		}
+	}
+	if first >= limit {
+		// 当前毫秒没有剩余的序列号
+		if this.millisecond == millisecond {
+			this.stats.SequenceWaits++
+			if millisecond, err = this.getNextMillisecond(); err != nil {
+				return Block{}, err
+			}
+		}
+		this.resetSequence()
+		first = this.sequence
+		limit = this.bits.sequence.max + 1
+		if first+this.sequenceCapacity() < limit {
+			limit = first + this.sequenceCapacity()
+		}
+	}
+
+	if err = this.checkEpoch(millisecond); err != nil {
+		return Block{}, err
+	}
+
+	if count > limit-first {
+		count = limit - first
+	}
+	this.sequence = first + count - 1
+	this.millisecond = millisecond
+	this.stats.Generated += count
+	if this.sampler != nil {
+		this.sampler.observe(millisecond, count, this.sequenceUsed(), this.sequenceCapacity())
+	}
+
+	var id = this.compose(millisecond, 0, first)
+	return Block{Start: id, Count: count}, nil
+}
@@ -0,0 +1,33 @@
+package snowflake
+
+import (
+	"testing"
+)
+
+func TestSnowFlake_NextBlock(t *testing.T) {
+	var tests = map[string][]Option{
+		"default":               nil,
+		"random sequence start": {WithRandomSequenceStart()},
+	}
+	for name, opts := range tests {
+		var s, _ = New(opts...)
+		var seen = make(map[int64]struct{})
+		for i := 0; i < 200; i++ {
+			var block, err = s.NextBlock(1000)
+			if err != nil || block.Count <= 0 || block.Count > 1000 {
+				t.Fatalf("%s: NextBlock() = %+v, %v", name, block, err)
+			}
+			for id := block.Start; id < block.Start+block.Count; id++ {
+				if _, ok := seen[id]; ok {
+					t.Fatalf("%s: duplicate id %d", name, id)
+				}
+				seen[id] = struct{}{}
+			}
+			var id = s.Next()
+			if _, ok := seen[id]; ok {
+				t.Fatalf("%s: duplicate id %d", name, id)
+			}
+			seen[id] = struct{}{}
+		}
+	}
+}
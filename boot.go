@@ -0,0 +1,62 @@
+package snowflake
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidBootCounter = errors.New("snowflake: invalid boot counter file")
+)
+
+// WithBootCounter 在 id 中加入启动次数，bits 为启动次数占用的位数，这部分位数会从时间戳中扣除。
+//
+// 启动次数保存在 path 指定的文件中，每次创建生成器时加 1，超过 bits 能表示的范围之后从 0 开始。
+// 进程崩溃之后立即重启，并且时钟有误差时，重启之后生成的 id 与之前的 id 的启动次数不同，不会重复。
+//
+// 同一个文件不能被多个进程同时使用，可以配合 WithExclusiveMachineLock 使用。
+func WithBootCounter(path string, bits uint8) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		var l = s.layout
+		l.Boot = bits
+		if err := WithLayout(l).Apply(s); err != nil {
+			return err
+		}
+
+		var boot, err = incrementBootCounter(path)
+		if err != nil {
+			return err
+		}
+		s.boot = boot & s.bits.boot.max
+		return nil
+	})
+}
+
+// incrementBootCounter 将文件中的启动次数加 1，返回新的启动次数，文件不存在时从 1 开始
+func incrementBootCounter(path string) (int64, error) {
+	var boot int64
+	var data, err = os.ReadFile(path)
+	switch {
+	case err == nil:
+		if boot, err = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err != nil || boot < 0 {
+			return 0, ErrInvalidBootCounter
+		}
+	case !os.IsNotExist(err):
+		return 0, err
+	}
+	boot++
+
+	// 先写入临时文件再重命名，避免写入过程中崩溃导致文件损坏
+	var tmp = filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err = os.WriteFile(tmp, []byte(strconv.FormatInt(boot, 10)+"\n"), 0644); err != nil {
+		return 0, err
+	}
+	if err = os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return 0, err
+	}
+	return boot, nil
+}
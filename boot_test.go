@@ -0,0 +1,32 @@
+package snowflake
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithBootCounter(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "boot")
+
+	for want := int64(1); want <= 5; want++ {
+		var s, err = New(WithTimeOffset(testEpoch), WithBootCounter(path, 2), WithMachine(3))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var id = s.Next()
+		var p = s.Decode(id)
+		if p.Boot != want%4 || p.Machine != 3 {
+			t.Fatalf("Decode(%d) = %+v, want boot %d", id, p, want%4)
+		}
+		assertRecent(t, s, id)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "5\n" {
+		t.Fatalf("boot counter file = %q, want 5", data)
+	}
+
+	os.WriteFile(path, []byte("x"), 0644)
+	if _, err := New(WithTimeOffset(testEpoch), WithBootCounter(path, 2)); err != ErrInvalidBootCounter {
+		t.Fatalf("New() error = %v, want %v", err, ErrInvalidBootCounter)
+	}
+}
@@ -0,0 +1,151 @@
+package snowflake
+
+import (
+	"errors"
+	"strconv"
+)
+
+var (
+	ErrInvalidCheckDigit = errors.New("snowflake: invalid check digit")
+	ErrNegativeID        = errors.New("snowflake: id can't be less than 0")
+)
+
+var dammTable = [10][10]byte{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+// damm 计算十进制字符串的 Damm 校验值，字符串包含校验位时结果为 0
+func damm(s string) (byte, bool) {
+	var interim byte
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, false
+		}
+		interim = dammTable[interim][s[i]-'0']
+	}
+	return interim, true
+}
+
+// luhn 使用 Luhn mod N 算法计算校验值，index 用于获取字符在字母表中的位置
+//
+// withCheck 为 true 表示 s 的最后一位是校验位，此时合法的字符串结果为 0
+func luhn(s string, alphabet string, index func(c byte) int, withCheck bool) (int, bool) {
+	var n = len(alphabet)
+	var factor = 2
+	if withCheck {
+		factor = 1
+	}
+	var sum = 0
+	for i := len(s) - 1; i >= 0; i-- {
+		var cp = index(s[i])
+		if cp < 0 {
+			return 0, false
+		}
+		var addend = factor * cp
+		sum += addend/n + addend%n
+		factor = 3 - factor
+	}
+	return (n - sum%n) % n, true
+}
+
+func decimalIndex(c byte) int {
+	if c < '0' || c > '9' {
+		return -1
+	}
+	return int(c - '0')
+}
+
+func base62IndexOf(c byte) int {
+	return int(base62Index[c])
+}
+
+// DammDecimal 获取 id 的十进制字符串，并在末尾追加一位 Damm 校验位，id 不能小于 0
+func DammDecimal(s int64) (string, error) {
+	if s < 0 {
+		return "", ErrNegativeID
+	}
+	var str = strconv.FormatInt(s, 10)
+	var check, _ = damm(str)
+	return str + string('0'+check), nil
+}
+
+// ParseDammDecimal 解析由 DammDecimal 生成的字符串，会校验末尾的 Damm 校验位
+func ParseDammDecimal(s string) (int64, error) {
+	if !canonicalBody(s) {
+		return 0, ErrInvalidCheckDigit
+	}
+	if check, ok := damm(s); !ok || check != 0 {
+		return 0, ErrInvalidCheckDigit
+	}
+	return parseDecimalBody(s)
+}
+
+// LuhnDecimal 获取 id 的十进制字符串，并在末尾追加一位 Luhn 校验位，id 不能小于 0
+func LuhnDecimal(s int64) (string, error) {
+	if s < 0 {
+		return "", ErrNegativeID
+	}
+	var str = strconv.FormatInt(s, 10)
+	var check, _ = luhn(str, "0123456789", decimalIndex, false)
+	return str + string(byte('0'+check)), nil
+}
+
+// ParseLuhnDecimal 解析由 LuhnDecimal 生成的字符串，会校验末尾的 Luhn 校验位
+func ParseLuhnDecimal(s string) (int64, error) {
+	if !canonicalBody(s) {
+		return 0, ErrInvalidCheckDigit
+	}
+	if check, ok := luhn(s, "0123456789", decimalIndex, true); !ok || check != 0 {
+		return 0, ErrInvalidCheckDigit
+	}
+	return parseDecimalBody(s)
+}
+
+// LuhnBase62 获取 id 的 base62 编码，并在末尾追加一位 Luhn mod 62 校验位，id 不能小于 0
+//
+// Damm 算法依赖特定阶数的全反对称拟群，这里只为 base62 提供 Luhn mod N 校验
+func LuhnBase62(s int64) (string, error) {
+	if s < 0 {
+		return "", ErrNegativeID
+	}
+	var str = Base62(s)
+	var check, _ = luhn(str, kBase62Alphabet, base62IndexOf, false)
+	return str + kBase62Alphabet[check:check+1], nil
+}
+
+// ParseLuhnBase62 解析由 LuhnBase62 生成的字符串，会校验末尾的 Luhn mod 62 校验位
+func ParseLuhnBase62(s string) (int64, error) {
+	if !canonicalBody(s) {
+		return 0, ErrInvalidCheckDigit
+	}
+	if check, ok := luhn(s, kBase62Alphabet, base62IndexOf, true); !ok || check != 0 {
+		return 0, ErrInvalidCheckDigit
+	}
+	var id, err = ParseBase62(s[:len(s)-1])
+	if err != nil {
+		return 0, ErrInvalidCheckDigit
+	}
+	return id, nil
+}
+
+// canonicalBody 判断带校验位的字符串是否至少包含一位数据和一位校验位，并且数据部分没有多余的前导 0
+func canonicalBody(s string) bool {
+	return len(s) >= 2 && (s[0] != '0' || len(s) == 2)
+}
+
+func parseDecimalBody(s string) (int64, error) {
+	var id, err = strconv.ParseInt(s[:len(s)-1], 10, 64)
+	if err != nil {
+		return 0, ErrInvalidCheckDigit
+	}
+	return id, nil
+}
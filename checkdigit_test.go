@@ -0,0 +1,110 @@
+package snowflake
+
+import (
+	"testing"
+)
+
+func TestDammDecimal(t *testing.T) {
+	if got, _ := DammDecimal(572); got != "5724" {
+		t.Fatalf("DammDecimal(572) = %q, want %q", got, "5724")
+	}
+
+	var id = Next()
+	var s, _ = DammDecimal(id)
+	if got, err := ParseDammDecimal(s); err != nil || got != id {
+		t.Fatalf("ParseDammDecimal(%q) = %d, %v, want %d", s, got, err, id)
+	}
+
+	var typo = []byte(s)
+	typo[3] = '0' + (typo[3]-'0'+1)%10
+	if _, err := ParseDammDecimal(string(typo)); err != ErrInvalidCheckDigit {
+		t.Fatalf("ParseDammDecimal(%q) error = %v, want %v", typo, err, ErrInvalidCheckDigit)
+	}
+}
+
+func TestLuhnDecimal(t *testing.T) {
+	if got, _ := LuhnDecimal(7992739871); got != "79927398713" {
+		t.Fatalf("LuhnDecimal(7992739871) = %q, want %q", got, "79927398713")
+	}
+
+	var id = Next()
+	var s, _ = LuhnDecimal(id)
+	if got, err := ParseLuhnDecimal(s); err != nil || got != id {
+		t.Fatalf("ParseLuhnDecimal(%q) = %d, %v, want %d", s, got, err, id)
+	}
+
+	var typo = []byte(s)
+	typo[3], typo[4] = typo[4], typo[3]
+	if typo[3] != typo[4] {
+		if _, err := ParseLuhnDecimal(string(typo)); err != ErrInvalidCheckDigit {
+			t.Fatalf("ParseLuhnDecimal(%q) error = %v, want %v", typo, err, ErrInvalidCheckDigit)
+		}
+	}
+}
+
+func TestLuhnBase62(t *testing.T) {
+	var id = Next()
+	var s, _ = LuhnBase62(id)
+	if got, err := ParseLuhnBase62(s); err != nil || got != id {
+		t.Fatalf("ParseLuhnBase62(%q) = %d, %v, want %d", s, got, err, id)
+	}
+
+	var typo = []byte(s)
+	if typo[0] == 'a' {
+		typo[0] = 'b'
+	} else {
+		typo[0] = 'a'
+	}
+	if _, err := ParseLuhnBase62(string(typo)); err != ErrInvalidCheckDigit {
+		t.Fatalf("ParseLuhnBase62(%q) error = %v, want %v", typo, err, ErrInvalidCheckDigit)
+	}
+}
+
+func TestCheckDigit_Invalid(t *testing.T) {
+	var encoders = map[string]func(int64) (string, error){
+		"DammDecimal": DammDecimal,
+		"LuhnDecimal": LuhnDecimal,
+		"LuhnBase62":  LuhnBase62,
+	}
+	for name, encode := range encoders {
+		if _, err := encode(-1); err != ErrNegativeID {
+			t.Fatalf("%s(-1) error = %v, want %v", name, err, ErrNegativeID)
+		}
+	}
+
+	var zero, _ = DammDecimal(0)
+	if got, err := ParseDammDecimal(zero); err != nil || got != 0 {
+		t.Fatalf("ParseDammDecimal(%q) = %d, %v, want 0", zero, got, err)
+	}
+
+	var parsers = map[string]func(string) (int64, error){
+		"ParseDammDecimal": ParseDammDecimal,
+		"ParseLuhnDecimal": ParseLuhnDecimal,
+		"ParseLuhnBase62":  ParseLuhnBase62,
+	}
+	// 9223372036854775808 超出了 int64 的范围，校验位是正确的
+	var overflow = "9223372036854775808"
+	var dammCheck, _ = damm(overflow)
+	var luhnCheck, _ = luhn(overflow, "0123456789", decimalIndex, false)
+
+	var bad = map[string][]string{
+		"ParseDammDecimal": {"05724", overflow + string('0'+dammCheck)},
+		"ParseLuhnDecimal": {"079927398713", overflow + string(byte('0'+luhnCheck))},
+		"ParseLuhnBase62":  {"0" + mustLuhnBase62(t, 61), "zzzzzzzzzzzzz"},
+	}
+	for name, parse := range parsers {
+		for _, s := range bad[name] {
+			if _, err := parse(s); err != ErrInvalidCheckDigit {
+				t.Fatalf("%s(%q) error = %v, want %v", name, s, err, ErrInvalidCheckDigit)
+			}
+		}
+	}
+}
+
+func mustLuhnBase62(t *testing.T, id int64) string {
+	var s, err = LuhnBase62(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
@@ -0,0 +1,169 @@
+package client
+
+//go:generate go test -run TestGeneratedClient -update .
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/smartwalle/snowflake/server"
+)
+
+const (
+	kDefaultRetries = 2
+	kDefaultBackoff = 50 * time.Millisecond
+)
+
+// StatusError id 服务返回了非 200 的状态码
+type StatusError struct {
+	Status  int    // http 状态码
+	Message string // id 服务返回的错误信息
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("snowflake: request failed with status %d: %s", e.Status, e.Message)
+}
+
+// Temporary 是否为可以重试的错误，id 服务在无法生成 id 时（如时钟回拨）会返回 503
+func (e *StatusError) Temporary() bool {
+	return e.Status >= http.StatusInternalServerError
+}
+
+type ClientOption func(c *Client)
+
+// WithRetries 设置请求失败后的重试次数，默认为 2，只有网络错误和 5xx 状态码会重试
+func WithRetries(n int) ClientOption {
+	return func(c *Client) {
+		if n >= 0 {
+			c.retries = n
+		}
+	}
+}
+
+// WithRetryBackoff 设置第一次重试前等待的时长，之后每次重试翻倍，默认为 50ms
+func WithRetryBackoff(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d >= 0 {
+			c.backoff = d
+		}
+	}
+}
+
+// WithTimeout 设置每次请求的超时时长，默认为 5s
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		if d > 0 {
+			c.timeout = d
+		}
+	}
+}
+
+// WithTransport 设置发起请求使用的 http.RoundTripper
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		if transport != nil {
+			c.client = &http.Client{Transport: transport}
+		}
+	}
+}
+
+// WithBearerToken 设置请求 id 服务时使用的 bearer token，用于 id 服务设置了 server.WithAuth 的情况，
+// 使用 mTLS 时可以通过 WithTransport 设置包含客户端证书的 http.Transport
+func WithBearerToken(token string) ClientOption {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// Client id 服务的客户端，每次调用都会请求 id 服务，需要在本地缓存 id 时使用 LeaseClient。
+//
+// 与接口对应的方法在 api.go 中，由 server.Routes 生成。
+type Client struct {
+	endpoint string
+	client   *http.Client
+	retries  int
+	backoff  time.Duration
+	timeout  time.Duration
+	token    string
+}
+
+// NewClient 创建 Client，endpoint 为 id 服务的地址，如：http://127.0.0.1:8080
+func NewClient(endpoint string, opts ...ClientOption) *Client {
+	var c = &Client{}
+	c.endpoint = strings.TrimRight(endpoint, "/")
+	c.client = &http.Client{}
+	c.retries = kDefaultRetries
+	c.backoff = kDefaultBackoff
+	c.timeout = kDefaultTimeout
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// NextID 获取一个 id，实现了 snowflake.Generator 接口
+func (this *Client) NextID() (int64, error) {
+	return this.Next(context.Background())
+}
+
+func (this *Client) do(ctx context.Context, method, path string, query url.Values, out interface{}) error {
+	var backoff = this.backoff
+	for attempt := 0; ; attempt++ {
+		var err = this.attempt(ctx, method, path, query, out)
+		if err == nil || attempt >= this.retries || !retryable(err) {
+			return err
+		}
+
+		var timer = time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
+
+func (this *Client) attempt(ctx context.Context, method, path string, query url.Values, out interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, this.timeout)
+	defer cancel()
+
+	var target = this.endpoint + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	var req, err = http.NewRequestWithContext(ctx, method, target, nil)
+	if err != nil {
+		return err
+	}
+	if this.token != "" {
+		req.Header.Set("Authorization", "Bearer "+this.token)
+	}
+	rsp, err := this.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		var e server.Error
+		json.NewDecoder(rsp.Body).Decode(&e)
+		return &StatusError{Status: rsp.StatusCode, Message: e.Error}
+	}
+	return json.NewDecoder(rsp.Body).Decode(out)
+}
+
+// retryable 网络错误和 5xx 状态码可以重试，调用方取消的请求不再重试
+func retryable(err error) bool {
+	if e, ok := err.(*StatusError); ok {
+		return e.Temporary()
+	}
+	_, ok := err.(*url.Error)
+	return ok
+}
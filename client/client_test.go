@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+	"github.com/smartwalle/snowflake/server"
+)
+
+func TestClient(t *testing.T) {
+	var generator, _ = snowflake.New(snowflake.WithDataCenter(3), snowflake.WithMachine(7))
+	var srv = httptest.NewServer(server.New(generator))
+	defer srv.Close()
+
+	var c = NewClient(srv.URL)
+	var ctx = context.Background()
+
+	var id, err = c.Next(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts, err := c.Decode(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := generator.Decode(id); parts.ID != id || parts.DataCenter != 3 || parts.Machine != 7 || !parts.Time.Equal(want.Time) {
+		t.Fatalf("Decode(%d) = %+v, want %+v", id, parts, want)
+	}
+
+	ids, err := c.Batch(ctx, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 100 || ids[0] <= id {
+		t.Fatalf("Batch(100) = %d ids starting at %d", len(ids), ids[0])
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("ids %d, %d are not increasing", ids[i-1], ids[i])
+		}
+	}
+
+	var e *StatusError
+	if _, err = c.Batch(ctx, 1001); !errors.As(err, &e) || e.Status != http.StatusBadRequest {
+		t.Fatalf("Batch(1001) error = %v, want status 400", err)
+	}
+
+	lease, err := c.Lease(ctx, 10)
+	if err != nil || lease.Count != 10 || lease.Start <= ids[len(ids)-1] {
+		t.Fatalf("Lease(10) = %+v, %v", lease, err)
+	}
+	if health, err := c.Health(ctx); err != nil || health.DataCenter != 3 || health.Machine != 7 {
+		t.Fatalf("Health() = %+v, %v", health, err)
+	}
+}
+
+func TestClient_Routes(t *testing.T) {
+	// 服务端的每一个接口都有对应的方法
+	var typ = reflect.TypeOf(&Client{})
+	for _, route := range server.Routes() {
+		if _, ok := typ.MethodByName(route.Name); !ok {
+			t.Fatalf("Client has no method for %s %s", route.Method, route.Path)
+		}
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	var generator, _ = snowflake.New()
+	var handler = server.New(generator)
+	var calls int32
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	var c = NewClient(srv.URL, WithRetries(2), WithRetryBackoff(time.Millisecond))
+	if _, err := c.NextID(); err != nil {
+		t.Fatalf("NextID() error = %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); n != 3 {
+		t.Fatalf("server received %d requests, want 3", n)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	c = NewClient(srv.URL, WithRetries(1), WithRetryBackoff(time.Millisecond))
+	var e *StatusError
+	if _, err := c.NextID(); !errors.As(err, &e) || e.Status != http.StatusServiceUnavailable {
+		t.Fatalf("NextID() error = %v, want status 503", err)
+	}
+}
+
+func TestClient_Timeout(t *testing.T) {
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	var c = NewClient(srv.URL, WithRetries(0), WithTimeout(20*time.Millisecond))
+	if _, err := c.Next(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Next() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestClient_BearerToken(t *testing.T) {
+	var generator, _ = snowflake.New()
+	var srv = httptest.NewServer(server.New(generator, server.WithAuth(server.StaticTokens(map[string]string{"secret": "order"}))))
+	defer srv.Close()
+
+	if _, err := NewClient(srv.URL, WithBearerToken("secret")).NextID(); err != nil {
+		t.Fatalf("NextID() error = %v", err)
+	}
+	var e *StatusError
+	if _, err := NewClient(srv.URL).NextID(); !errors.As(err, &e) || e.Status != http.StatusUnauthorized {
+		t.Fatalf("NextID() error = %v, want status 401", err)
+	}
+}
@@ -0,0 +1,180 @@
+package client
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+)
+
+const (
+	kDefaultFallbackThreshold = 10 * time.Second
+	kDefaultProbeInterval     = time.Second
+)
+
+type FallbackOption func(f *Fallback)
+
+// WithFallbackThreshold 设置 id 服务持续不可用多久之后切换到本地生成器，默认为 10 秒，在此之前会返回 id 服务的错误
+func WithFallbackThreshold(d time.Duration) FallbackOption {
+	return func(f *Fallback) {
+		if d >= 0 {
+			f.threshold = d
+		}
+	}
+}
+
+// WithProbeInterval 设置降级期间尝试 id 服务的间隔，默认为 1 秒，id 服务恢复之后会切换回 id 服务
+func WithProbeInterval(d time.Duration) FallbackOption {
+	return func(f *Fallback) {
+		if d > 0 {
+			f.probeInterval = d
+		}
+	}
+}
+
+// WithFallbackLogger 设置记录降级和恢复的日志，默认为 slog.Default()，为 nil 时不记录日志
+func WithFallbackLogger(logger *slog.Logger) FallbackOption {
+	return func(f *Fallback) {
+		f.logger = logger
+	}
+}
+
+// WithFallbackHook 设置进入降级（degraded 为 true）和恢复（degraded 为 false）时的回调函数，可以用于上报监控指标
+func WithFallbackHook(hook func(degraded bool)) FallbackOption {
+	return func(f *Fallback) {
+		f.hook = hook
+	}
+}
+
+// FallbackStats Fallback 的统计信息
+type FallbackStats struct {
+	Degraded      bool      // 当前是否处于降级状态
+	DegradedSince time.Time // 进入降级状态的时间
+	Degradations  int64     // 进入降级状态的次数
+	RemoteErrors  int64     // 请求 id 服务失败的次数
+	RemoteIDs     int64     // 从 id 服务获取的 id 数量
+	LocalIDs      int64     // 由本地生成器生成的 id 数量
+}
+
+// Fallback 优先从 id 服务获取 id，id 服务持续不可用超过一定时长之后切换到本地生成器（降级），id 服务恢复之后自动切换回来。
+//
+// 本地生成器需要使用为降级预留的机器标识，不能与任何 id 服务以及其它客户端的本地生成器重复，如：
+//
+//	// 数据中心 31 预留给降级使用，每个客户端分配其中一个机器标识
+//	var local, _ = snowflake.New(snowflake.WithDataCenter(31), snowflake.WithMachine(clientIndex))
+//	var generator = client.NewFallback(client.NewClient("http://127.0.0.1:8080"), local)
+//
+// 降级期间生成的 id 与 id 服务生成的 id 之间不保证有序，只保证不重复。
+type Fallback struct {
+	remote        snowflake.Generator
+	local         snowflake.Generator
+	threshold     time.Duration
+	probeInterval time.Duration
+	logger        *slog.Logger
+	hook          func(degraded bool)
+
+	mu           sync.Mutex
+	failingSince time.Time
+	lastProbe    time.Time
+	stats        FallbackStats
+	now          func() time.Time
+}
+
+// NewFallback 创建 Fallback，remote 通常为 Client、MultiClient 或者 LeaseClient，local 为使用预留机器标识的本地生成器
+func NewFallback(remote, local snowflake.Generator, opts ...FallbackOption) *Fallback {
+	var f = &Fallback{}
+	f.remote = remote
+	f.local = local
+	f.threshold = kDefaultFallbackThreshold
+	f.probeInterval = kDefaultProbeInterval
+	f.logger = slog.Default()
+	f.now = time.Now
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// NextID 获取一个 id，降级期间由本地生成器生成
+func (this *Fallback) NextID() (int64, error) {
+	var now = this.now()
+
+	this.mu.Lock()
+	var degraded = this.stats.Degraded
+	if degraded && now.Sub(this.lastProbe) < this.probeInterval {
+		this.stats.LocalIDs++
+		this.mu.Unlock()
+		return this.local.NextID()
+	}
+	if degraded {
+		this.lastProbe = now
+	}
+	this.mu.Unlock()
+
+	var id, err = this.remote.NextID()
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if err == nil {
+		this.stats.RemoteIDs++
+		this.failingSince = time.Time{}
+		if this.stats.Degraded {
+			this.recover(now)
+		}
+		return id, nil
+	}
+
+	this.stats.RemoteErrors++
+	if this.failingSince.IsZero() {
+		this.failingSince = now
+	}
+	if !this.stats.Degraded {
+		if now.Sub(this.failingSince) < this.threshold {
+			return 0, err
+		}
+		this.degrade(now, err)
+	}
+	this.stats.LocalIDs++
+	return this.local.NextID()
+}
+
+// Stats 获取统计信息
+func (this *Fallback) Stats() FallbackStats {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	return this.stats
+}
+
+// degrade 进入降级状态，调用方需要持有 mu
+func (this *Fallback) degrade(now time.Time, cause error) {
+	this.stats.Degraded = true
+	this.stats.DegradedSince = now
+	this.stats.Degradations++
+	this.lastProbe = now
+	if this.logger != nil {
+		this.logger.Warn("snowflake: id service unavailable, switched to the local fallback generator",
+			slog.Duration("unavailable", now.Sub(this.failingSince)),
+			slog.String("error", cause.Error()),
+		)
+	}
+	if this.hook != nil {
+		this.hook(true)
+	}
+}
+
+// recover 退出降级状态，调用方需要持有 mu
+func (this *Fallback) recover(now time.Time) {
+	var since = this.stats.DegradedSince
+	this.stats.Degraded = false
+	this.stats.DegradedSince = time.Time{}
+	if this.logger != nil {
+		this.logger.Info("snowflake: id service recovered, switched back from the local fallback generator",
+			slog.Duration("degraded", now.Sub(since)),
+		)
+	}
+	if this.hook != nil {
+		this.hook(false)
+	}
+}
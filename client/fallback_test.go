@@ -0,0 +1,66 @@
+package client
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+)
+
+type fakeRemote struct {
+	err error
+	id  int64
+}
+
+func (this *fakeRemote) NextID() (int64, error) {
+	if this.err != nil {
+		return 0, this.err
+	}
+	this.id++
+	return this.id, nil
+}
+
+func TestFallback(t *testing.T) {
+	var remote = &fakeRemote{}
+	var local, _ = snowflake.New(snowflake.WithDataCenter(31), snowflake.WithMachine(1))
+	var events []bool
+	var f = NewFallback(remote, local, WithFallbackThreshold(10*time.Second), WithProbeInterval(time.Second),
+		WithFallbackLogger(nil), WithFallbackHook(func(degraded bool) { events = append(events, degraded) }))
+	var now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f.now = func() time.Time { return now }
+
+	if id, err := f.NextID(); err != nil || id != 1 {
+		t.Fatalf("NextID() = %d, %v, want 1", id, err)
+	}
+
+	// 超过阈值之前返回 id 服务的错误
+	remote.err = errors.New("connection refused")
+	if _, err := f.NextID(); err != remote.err {
+		t.Fatalf("NextID() error = %v, want %v", err, remote.err)
+	}
+	now = now.Add(10 * time.Second)
+	var id, err = f.NextID()
+	if err != nil || local.Decode(id).DataCenter != 31 {
+		t.Fatalf("NextID() = %d, %v, want a local id", id, err)
+	}
+	if stats := f.Stats(); !stats.Degraded || stats.Degradations != 1 || stats.LocalIDs != 1 || stats.RemoteErrors != 2 {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+
+	// 探测间隔内不请求 id 服务
+	remote.err = nil
+	if id, _ = f.NextID(); local.Decode(id).DataCenter != 31 {
+		t.Fatalf("NextID() = %d, want a local id before the next probe", id)
+	}
+	now = now.Add(time.Second)
+	if id, err = f.NextID(); err != nil || id != 2 {
+		t.Fatalf("NextID() = %d, %v, want remote id 2", id, err)
+	}
+	if stats := f.Stats(); stats.Degraded || stats.RemoteIDs != 2 || stats.LocalIDs != 2 {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+	if len(events) != 2 || !events[0] || events[1] {
+		t.Fatalf("hook events = %v, want [true false]", events)
+	}
+}
@@ -0,0 +1,105 @@
+package client
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/smartwalle/snowflake/server"
+)
+
+var update = flag.Bool("update", false, "regenerate api.go from server.Routes")
+
+const kGeneratedFile = "api.go"
+
+// TestGeneratedClient 检查 api.go 与 server.Routes 一致，使用 go generate 重新生成
+func TestGeneratedClient(t *testing.T) {
+	var src, err = generateClient(server.Routes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err = os.WriteFile(kGeneratedFile, src, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	current, err := os.ReadFile(kGeneratedFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(current, src) {
+		t.Fatalf("%s is out of date with server.Routes, run go generate in the client directory", kGeneratedFile)
+	}
+}
+
+func generateClient(routes []server.Route) ([]byte, error) {
+	var imports = map[string]bool{"context": true, "net/http": true}
+	var body bytes.Buffer
+	for _, route := range routes {
+		var method, ok = map[string]string{http.MethodGet: "http.MethodGet", http.MethodPost: "http.MethodPost"}[route.Method]
+		if !ok {
+			return nil, fmt.Errorf("route %s: unsupported method %s", route.Name, route.Method)
+		}
+
+		var response = route.Response
+		if strings.HasPrefix(response, "snowflake.") {
+			imports["github.com/smartwalle/snowflake"] = true
+		} else {
+			response = "server." + response
+			imports["github.com/smartwalle/snowflake/server"] = true
+		}
+		var result, zero, value = response, "rsp", "rsp"
+		if route.Field != nil {
+			result, value = route.Field.Type, "rsp."+route.Field.Name
+			zero = "nil"
+			if result == "int" || result == "int64" {
+				zero = "0"
+			}
+		}
+
+		var params, query []string
+		for _, p := range route.Params {
+			params = append(params, ", "+p.Name+" "+p.Type)
+			switch p.Type {
+			case "int":
+				query = append(query, fmt.Sprintf("%q: {strconv.Itoa(%s)}", p.Name, p.Name))
+			case "int64":
+				query = append(query, fmt.Sprintf("%q: {strconv.FormatInt(%s, 10)}", p.Name, p.Name))
+			default:
+				return nil, fmt.Errorf("route %s: unsupported param type %s", route.Name, p.Type)
+			}
+		}
+
+		fmt.Fprintf(&body, "\n// %s %s，对应 %s %s\n", route.Name, route.Doc, route.Method, route.Path)
+		fmt.Fprintf(&body, "func (this *Client) %s(ctx context.Context%s) (%s, error) {\n", route.Name, strings.Join(params, ""), result)
+		fmt.Fprintf(&body, "var rsp %s\n", response)
+		var queryArg = "nil"
+		if len(query) > 0 {
+			imports["net/url"] = true
+			imports["strconv"] = true
+			fmt.Fprintf(&body, "var query = url.Values{%s}\n", strings.Join(query, ", "))
+			queryArg = "query"
+		}
+		fmt.Fprintf(&body, "if err := this.do(ctx, %s, %q, %s, &rsp); err != nil {\nreturn %s, err\n}\n", method, route.Path, queryArg, zero)
+		fmt.Fprintf(&body, "return %s, nil\n}\n", value)
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by go generate from server.Routes; DO NOT EDIT.\n\npackage client\n\nimport (\n")
+	for _, path := range []string{"context", "net/http", "net/url", "strconv", "", "github.com/smartwalle/snowflake", "github.com/smartwalle/snowflake/server"} {
+		if path == "" {
+			src.WriteString("\n")
+		} else if imports[path] {
+			fmt.Fprintf(&src, "%q\n", path)
+		}
+	}
+	src.WriteString(")\n")
+	src.Write(body.Bytes())
+	return format.Source(src.Bytes())
+}
@@ -0,0 +1,232 @@
+// Package client 提供 id 服务的客户端。
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/smartwalle/snowflake/server"
+)
+
+const (
+	kDefaultBlockSize = 1000
+	kDefaultTimeout   = 5 * time.Second
+)
+
+var (
+	ErrClosed        = errors.New("snowflake: lease client closed")
+	ErrLeaseUnusable = errors.New("snowflake: leased block is empty or already expired")
+)
+
+type LeaseOption func(c *LeaseClient)
+
+// WithBlockSize 设置每次租用的 id 数量，默认为 1000
+func WithBlockSize(size int64) LeaseOption {
+	return func(c *LeaseClient) {
+		if size > 0 {
+			c.blockSize = size
+		}
+	}
+}
+
+// WithHTTPClient 设置请求 id 服务使用的 http.Client
+func WithHTTPClient(client *http.Client) LeaseOption {
+	return func(c *LeaseClient) {
+		if client != nil {
+			c.client = client
+		}
+	}
+}
+
+// WithLeaseBearerToken 设置租用 id 时使用的 bearer token，用于 id 服务设置了 server.WithAuth 的情况，与 Client 的 WithBearerToken 相同
+func WithLeaseBearerToken(token string) LeaseOption {
+	return func(c *LeaseClient) {
+		c.token = token
+	}
+}
+
+// LeaseClient 从 id 服务租用连续的 id 并缓存在本地，本地缓存的 id 少于一个批次的一半时会在后台补充，
+// 所以大部分情况下生成 id 不需要发起网络请求。
+type LeaseClient struct {
+	endpoint  string
+	client    *http.Client
+	blockSize int64
+	token     string
+
+	mu        sync.Mutex
+	cond      *sync.Cond
+	leases    []leasedBlock
+	remaining int64
+	refilling bool
+	closed    bool
+	now       func() time.Time
+}
+
+// leasedBlock 本地缓存的一段 id，expiresAt 使用客户端收到租约时的本地时间加上 TTL 计算
+type leasedBlock struct {
+	start     int64
+	count     int64
+	expiresAt time.Time
+}
+
+// NewLeaseClient 创建 LeaseClient，endpoint 为 id 服务的地址，如：http://127.0.0.1:8080
+func NewLeaseClient(endpoint string, opts ...LeaseOption) *LeaseClient {
+	var c = &LeaseClient{}
+	c.endpoint = strings.TrimRight(endpoint, "/")
+	c.client = &http.Client{Timeout: kDefaultTimeout}
+	c.blockSize = kDefaultBlockSize
+	c.cond = sync.NewCond(&c.mu)
+	c.now = time.Now
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// NextID 获取一个 id
+func (this *LeaseClient) NextID() (int64, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	for {
+		if this.closed {
+			return 0, ErrClosed
+		}
+		this.dropExpired()
+
+		if len(this.leases) > 0 {
+			var lease = &this.leases[0]
+			var id = lease.start
+			lease.start++
+			lease.count--
+			this.remaining--
+			if lease.count == 0 {
+				this.leases = this.leases[1:]
+			}
+			if this.remaining*2 < this.blockSize && !this.refilling {
+				this.refilling = true
+				go this.refill()
+			}
+			return id, nil
+		}
+
+		if this.refilling {
+			this.cond.Wait()
+			continue
+		}
+
+		// 本地没有可用的 id，同步租用
+		this.mu.Unlock()
+		var lease, err = this.lease()
+		this.mu.Lock()
+		if err != nil {
+			return 0, err
+		}
+		this.add(lease)
+	}
+}
+
+// LeaseStatus LeaseClient 本地缓存的租约状态
+type LeaseStatus struct {
+	Blocks     int       `json:"blocks"`      // 本地缓存的租约数量
+	Remaining  int64     `json:"remaining"`   // 本地缓存的 id 数量
+	NextExpiry time.Time `json:"next_expiry"` // 最早过期的租约的过期时间，没有租约时为零值
+	Refilling  bool      `json:"refilling"`   // 是否正在后台补充
+	Closed     bool      `json:"closed"`
+}
+
+// Status 获取本地缓存的租约状态，可以通过 snowflake.DebugSection 输出到 DebugHandler
+func (this *LeaseClient) Status() LeaseStatus {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	this.dropExpired()
+	var status = LeaseStatus{}
+	status.Blocks = len(this.leases)
+	status.Remaining = this.remaining
+	if len(this.leases) > 0 {
+		status.NextExpiry = this.leases[0].expiresAt
+	}
+	status.Refilling = this.refilling
+	status.Closed = this.closed
+	return status
+}
+
+// Close 关闭 LeaseClient，丢弃本地缓存的 id
+func (this *LeaseClient) Close() error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	this.closed = true
+	this.leases = nil
+	this.remaining = 0
+	this.cond.Broadcast()
+	return nil
+}
+
+func (this *LeaseClient) refill() {
+	var lease, err = this.lease()
+
+	this.mu.Lock()
+	this.refilling = false
+	// 后台补充失败时不做处理，本地的 id 用完以后会同步租用并返回错误
+	if err == nil && !this.closed {
+		this.add(lease)
+	}
+	this.cond.Broadcast()
+	this.mu.Unlock()
+}
+
+// add 将刚收到的租约加入本地缓存，有效期从收到的时间开始计算
+func (this *LeaseClient) add(lease server.Lease) {
+	var block = leasedBlock{}
+	block.start = lease.Start
+	block.count = lease.Count
+	block.expiresAt = this.now().Add(lease.TTL)
+	this.leases = append(this.leases, block)
+	this.remaining += block.count
+}
+
+func (this *LeaseClient) dropExpired() {
+	var now = this.now()
+	for len(this.leases) > 0 && !now.Before(this.leases[0].expiresAt) {
+		this.remaining -= this.leases[0].count
+		this.leases = this.leases[1:]
+	}
+}
+
+func (this *LeaseClient) lease() (server.Lease, error) {
+	var lease server.Lease
+	var req, err = http.NewRequest(http.MethodPost, this.endpoint+"/lease?count="+strconv.FormatInt(this.blockSize, 10), nil)
+	if err != nil {
+		return lease, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if this.token != "" {
+		req.Header.Set("Authorization", "Bearer "+this.token)
+	}
+	rsp, err := this.client.Do(req)
+	if err != nil {
+		return lease, err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		var e server.Error
+		json.NewDecoder(rsp.Body).Decode(&e)
+		return lease, fmt.Errorf("snowflake: lease failed with status %d: %s", rsp.StatusCode, e.Error)
+	}
+	if err = json.NewDecoder(rsp.Body).Decode(&lease); err != nil {
+		return lease, err
+	}
+	if lease.Count <= 0 || lease.TTL <= 0 {
+		return lease, ErrLeaseUnusable
+	}
+	return lease, nil
+}
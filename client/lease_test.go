@@ -0,0 +1,121 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+	"github.com/smartwalle/snowflake/server"
+)
+
+func TestLeaseClient(t *testing.T) {
+	var generator, _ = snowflake.New()
+	var srv = httptest.NewServer(server.New(generator))
+	defer srv.Close()
+
+	var c = NewLeaseClient(srv.URL, WithBlockSize(100))
+	defer c.Close()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var seen = make(map[int64]struct{})
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				var id, err = c.NextID()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				if _, ok := seen[id]; ok {
+					t.Errorf("duplicate id %d", id)
+				}
+				seen[id] = struct{}{}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if stats := generator.Stats(); stats.Generated < 8000 {
+		t.Fatalf("server generated %d ids, want at least 8000", stats.Generated)
+	}
+}
+
+func TestLeaseClient_BearerToken(t *testing.T) {
+	var generator, _ = snowflake.New()
+	var srv = httptest.NewServer(server.New(generator, server.WithAuth(server.StaticTokens(map[string]string{"secret": "order"}))))
+	defer srv.Close()
+
+	var c = NewLeaseClient(srv.URL, WithBlockSize(10), WithLeaseBearerToken("secret"))
+	defer c.Close()
+	for i := 0; i < 30; i++ {
+		if _, err := c.NextID(); err != nil {
+			t.Fatalf("NextID() error = %v", err)
+		}
+	}
+
+	var anonymous = NewLeaseClient(srv.URL)
+	defer anonymous.Close()
+	if _, err := anonymous.NextID(); err == nil || !strings.Contains(err.Error(), "status 401") {
+		t.Fatalf("NextID() error = %v, want status 401", err)
+	}
+}
+
+func TestLeaseClient_Expired(t *testing.T) {
+	var generator, _ = snowflake.New()
+	var srv = httptest.NewServer(server.New(generator, server.WithLeaseTTL(time.Hour)))
+	defer srv.Close()
+
+	var c = NewLeaseClient(srv.URL, WithBlockSize(10))
+	defer c.Close()
+
+	// 客户端的时钟比服务端快很多，有效期从收到租约的时间开始计算，不受影响
+	var now = time.Now().Add(24 * time.Hour)
+	c.mu.Lock()
+	c.now = func() time.Time { return now }
+	c.mu.Unlock()
+
+	var first, err = c.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var second, _ = c.NextID()
+	if second != first+1 {
+		t.Fatalf("NextID() = %d, want %d", second, first+1)
+	}
+	if status := c.Status(); status.Blocks == 0 || !status.NextExpiry.Equal(now.Add(time.Hour)) {
+		t.Fatalf("Status() = %+v", status)
+	}
+
+	c.mu.Lock()
+	now = now.Add(2 * time.Hour)
+	c.mu.Unlock()
+
+	var third, _ = c.NextID()
+	if third == second+1 {
+		t.Fatalf("NextID() = %d, expired lease should not be used", third)
+	}
+}
+
+func TestLeaseClient_Unusable(t *testing.T) {
+	var srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(server.Lease{Block: snowflake.Block{Start: 1, Count: 10}})
+	}))
+	defer srv.Close()
+
+	var c = NewLeaseClient(srv.URL)
+	defer c.Close()
+
+	if _, err := c.NextID(); err != ErrLeaseUnusable {
+		t.Fatalf("NextID() error = %v, want %v", err, ErrLeaseUnusable)
+	}
+}
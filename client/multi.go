@@ -0,0 +1,236 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+	"github.com/smartwalle/snowflake/discovery"
+)
+
+const (
+	kDefaultCooldown        = 5 * time.Second
+	kDefaultRefreshInterval = 10 * time.Second
+)
+
+var (
+	ErrNoEndpoints = errors.New("snowflake: no id server endpoints available")
+)
+
+type MultiOption func(c *MultiClient)
+
+// WithClientOptions 设置访问每一个 id 服务使用的参数，默认不在同一个 id 服务上重试，失败时直接切换到下一个 id 服务
+func WithClientOptions(opts ...ClientOption) MultiOption {
+	return func(c *MultiClient) {
+		c.clientOpts = append(c.clientOpts, opts...)
+	}
+}
+
+// WithCooldown 设置 id 服务请求失败之后暂停使用的时长，默认为 5 秒，之后会重新尝试该 id 服务
+func WithCooldown(d time.Duration) MultiOption {
+	return func(c *MultiClient) {
+		if d > 0 {
+			c.cooldown = d
+		}
+	}
+}
+
+// WithDiscovery 通过 resolver 获取名称为 name 的 id 服务地址，每隔 interval 刷新一次，interval 小于等于 0 时为 10 秒
+func WithDiscovery(resolver discovery.Resolver, name string, interval time.Duration) MultiOption {
+	return func(c *MultiClient) {
+		if interval <= 0 {
+			interval = kDefaultRefreshInterval
+		}
+		c.resolver = resolver
+		c.name = name
+		c.interval = interval
+	}
+}
+
+// MultiClient 将请求分散到多个 id 服务，请求失败（网络错误或者 5xx）的 id 服务会暂停使用一段时间，并自动切换到其它 id 服务重试。
+//
+// 所有 id 服务都处于暂停状态时仍然会依次尝试，避免 id 服务恢复之后客户端还需要等待暂停结束。
+type MultiClient struct {
+	clientOpts []ClientOption
+	cooldown   time.Duration
+	resolver   discovery.Resolver
+	name       string
+	interval   time.Duration
+
+	mu        sync.RWMutex
+	endpoints []*endpoint
+	next      uint32
+	now       func() time.Time
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+type endpoint struct {
+	url    string
+	client *Client
+	down   int64 // 暂停使用到的时间，UnixNano
+}
+
+// NewMultiClient 创建 MultiClient，endpoints 为 id 服务的地址，使用 WithDiscovery 时可以为空。
+//
+// 使用 WithDiscovery 时会先同步获取一次 id 服务地址，失败时返回错误。
+func NewMultiClient(endpoints []string, opts ...MultiOption) (*MultiClient, error) {
+	var c = &MultiClient{}
+	c.clientOpts = []ClientOption{WithRetries(0)}
+	c.cooldown = kDefaultCooldown
+	c.now = time.Now
+	c.stop = make(chan struct{})
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.setEndpoints(endpoints)
+
+	if c.resolver != nil {
+		if err := c.refresh(context.Background()); err != nil {
+			return nil, err
+		}
+		go c.watch()
+	}
+	return c, nil
+}
+
+// Endpoints 获取当前使用的 id 服务地址
+func (this *MultiClient) Endpoints() []string {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+
+	var urls = make([]string, 0, len(this.endpoints))
+	for _, e := range this.endpoints {
+		urls = append(urls, e.url)
+	}
+	return urls
+}
+
+// Next 获取一个 id
+func (this *MultiClient) Next(ctx context.Context) (id int64, err error) {
+	err = this.call(ctx, func(c *Client) error {
+		id, err = c.Next(ctx)
+		return err
+	})
+	return id, err
+}
+
+// NextID 获取一个 id，实现了 snowflake.Generator 接口
+func (this *MultiClient) NextID() (int64, error) {
+	return this.Next(context.Background())
+}
+
+// Batch 获取 count 个 id，count 不能超过 1000
+func (this *MultiClient) Batch(ctx context.Context, count int) (ids []int64, err error) {
+	err = this.call(ctx, func(c *Client) error {
+		ids, err = c.Batch(ctx, count)
+		return err
+	})
+	return ids, err
+}
+
+// Decode 按照 id 服务的布局和时间偏移量解析 id，所有 id 服务需要使用相同的布局和时间偏移量
+func (this *MultiClient) Decode(ctx context.Context, id int64) (parts snowflake.Parts, err error) {
+	err = this.call(ctx, func(c *Client) error {
+		parts, err = c.Decode(ctx, id)
+		return err
+	})
+	return parts, err
+}
+
+// Close 停止刷新 id 服务地址
+func (this *MultiClient) Close() error {
+	this.stopOnce.Do(func() {
+		close(this.stop)
+	})
+	return nil
+}
+
+// call 从下一个 id 服务开始依次尝试，优先使用没有暂停的 id 服务
+func (this *MultiClient) call(ctx context.Context, fn func(c *Client) error) error {
+	this.mu.RLock()
+	var endpoints = this.endpoints
+	this.mu.RUnlock()
+
+	if len(endpoints) == 0 {
+		return ErrNoEndpoints
+	}
+
+	var now = this.now().UnixNano()
+	var start = int(atomic.AddUint32(&this.next, 1))
+	var ordered = make([]*endpoint, 0, len(endpoints))
+	var down []*endpoint
+	for i := range endpoints {
+		var e = endpoints[(start+i)%len(endpoints)]
+		if atomic.LoadInt64(&e.down) > now {
+			down = append(down, e)
+			continue
+		}
+		ordered = append(ordered, e)
+	}
+	ordered = append(ordered, down...)
+
+	var err error
+	for _, e := range ordered {
+		if err = fn(e.client); err == nil {
+			atomic.StoreInt64(&e.down, 0)
+			return nil
+		}
+		if !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+		atomic.StoreInt64(&e.down, this.now().Add(this.cooldown).UnixNano())
+	}
+	return err
+}
+
+// setEndpoints 更新 id 服务地址，保留已有 id 服务的暂停状态
+func (this *MultiClient) setEndpoints(urls []string) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	var exists = make(map[string]*endpoint, len(this.endpoints))
+	for _, e := range this.endpoints {
+		exists[e.url] = e
+	}
+	var endpoints = make([]*endpoint, 0, len(urls))
+	for _, url := range urls {
+		var e = exists[url]
+		if e == nil {
+			e = &endpoint{url: url, client: NewClient(url, this.clientOpts...)}
+			exists[url] = e
+		}
+		endpoints = append(endpoints, e)
+	}
+	this.endpoints = endpoints
+}
+
+func (this *MultiClient) refresh(ctx context.Context) error {
+	var urls, err = discovery.Endpoints(ctx, this.resolver, this.name)
+	if err != nil {
+		return err
+	}
+	// 注册中心暂时没有健康的实例时继续使用之前的 id 服务地址，由请求失败时的切换逻辑处理
+	if len(urls) == 0 && len(this.Endpoints()) > 0 {
+		return nil
+	}
+	this.setEndpoints(urls)
+	return nil
+}
+
+func (this *MultiClient) watch() {
+	var ticker = time.NewTicker(this.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-this.stop:
+			return
+		case <-ticker.C:
+			// 注册中心不可用时继续使用之前的 id 服务地址
+			this.refresh(context.Background())
+		}
+	}
+}
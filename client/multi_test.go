@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+	"github.com/smartwalle/snowflake/discovery"
+	"github.com/smartwalle/snowflake/server"
+)
+
+func TestMultiClient_Failover(t *testing.T) {
+	var generator, _ = snowflake.New(snowflake.WithMachine(1))
+	var healthy = httptest.NewServer(server.New(generator))
+	defer healthy.Close()
+
+	var calls int32
+	var broken = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+
+	var c, err = NewMultiClient([]string{broken.URL, healthy.URL}, WithCooldown(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	for i := 0; i < 20; i++ {
+		if _, err = c.NextID(); err != nil {
+			t.Fatalf("NextID() error = %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Fatalf("broken endpoint received %d requests during cooldown, want 1", n)
+	}
+
+	// 所有 id 服务都暂停时仍然会尝试
+	healthy.Close()
+	if _, err = c.NextID(); err == nil {
+		t.Fatal("NextID() should fail when every endpoint is down")
+	}
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Fatalf("broken endpoint received %d requests, want 2", n)
+	}
+
+	var empty, _ = NewMultiClient(nil)
+	if _, err = empty.NextID(); err != ErrNoEndpoints {
+		t.Fatalf("NextID() error = %v, want %v", err, ErrNoEndpoints)
+	}
+}
+
+type staticResolver struct {
+	instances atomic.Value
+}
+
+func (this *staticResolver) Resolve(ctx context.Context, name string) ([]discovery.Instance, error) {
+	return this.instances.Load().([]discovery.Instance), nil
+}
+
+func TestMultiClient_Discovery(t *testing.T) {
+	var generator, _ = snowflake.New(snowflake.WithMachine(2))
+	var srv = httptest.NewServer(server.New(generator))
+	defer srv.Close()
+
+	var addr = srv.Listener.Addr().(*net.TCPAddr)
+	var resolver = &staticResolver{}
+	resolver.instances.Store([]discovery.Instance{{Address: addr.IP.String(), Port: addr.Port}})
+
+	var c, err = NewMultiClient(nil, WithDiscovery(resolver, "snowflaked", 10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if !reflect.DeepEqual(c.Endpoints(), []string{srv.URL}) {
+		t.Fatalf("Endpoints() = %v, want %v", c.Endpoints(), []string{srv.URL})
+	}
+	if _, err = c.Next(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// 没有健康的实例时保留之前的地址
+	resolver.instances.Store([]discovery.Instance{})
+	time.Sleep(50 * time.Millisecond)
+	if !reflect.DeepEqual(c.Endpoints(), []string{srv.URL}) {
+		t.Fatalf("Endpoints() = %v, want %v", c.Endpoints(), []string{srv.URL})
+	}
+
+	resolver.instances.Store([]discovery.Instance{{Address: "127.0.0.1", Port: 1}})
+	var deadline = time.Now().Add(time.Second)
+	for !reflect.DeepEqual(c.Endpoints(), []string{"http://127.0.0.1:1"}) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Endpoints() = %v, want the refreshed endpoint", c.Endpoints())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
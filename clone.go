@@ -0,0 +1,80 @@
+package snowflake
+
+import (
+	"errors"
+)
+
+var (
+	ErrCloneSameWorker = errors.New("snowflake: clone uses the same worker as the original generator, override the machine or worker id")
+)
+
+// Clone 使用当前生成器的配置创建新的生成器，opts 在复制的配置之后生效，用于一个进程管理多个逻辑工作节点时，
+// 从一个校验过的基础配置派生出使用不同机器标识的生成器，如：
+//
+//	order, err := base.Clone(snowflake.WithMachine(1))
+//
+// 复制布局、时间偏移量、工作节点标识、启动次数以及时钟回拨、等待等配置，不复制生成的状态、统计信息和 SequenceSampler。
+// 设置了 WithExclusiveMachineLock 和 WithProcessSlots 时，新的生成器会获取自己的文件锁。
+// 新的生成器与当前生成器的工作节点标识和进程槽位都相同时返回 ErrCloneSameWorker，避免生成重复的 id。
+func (this *SnowFlake) Clone(opts ...Option) (*SnowFlake, error) {
+	var s, err = this.clone(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if s.timeOffset == this.timeOffset && s.bits == this.bits && s.version == this.version && s.region == this.region &&
+		s.dataCenter == this.dataCenter && s.machine == this.machine && s.boot == this.boot && s.process == this.process {
+		s.Close()
+		return nil, ErrCloneSameWorker
+	}
+	return s, nil
+}
+
+// clone 使用当前生成器的配置创建新的生成器，不检查工作节点标识是否相同
+func (this *SnowFlake) clone(opts ...Option) (*SnowFlake, error) {
+	var base = &SnowFlake{}
+	this.mu.Lock()
+	copyConfig(base, this)
+	var random = this.random != nil
+	this.mu.Unlock()
+
+	var clone = optionFunc(func(s *SnowFlake) error {
+		copyConfig(s, base)
+		s.anchor = s.clock()
+		s.anchorMill = s.anchor.UnixNano() / 1e6
+		if random {
+			return WithRandomSequenceStart().Apply(s)
+		}
+		return nil
+	})
+	return New(append([]Option{clone}, opts...)...)
+}
+
+// copyConfig 将 src 的配置复制到 dst，不复制生成的状态和序列号的随机数生成器，调用方需要持有 src 的 mu
+func copyConfig(dst, src *SnowFlake) {
+	dst.version = src.version
+	dst.region = src.region
+	dst.dataCenter = src.dataCenter
+	dst.machine = src.machine
+	dst.boot = src.boot
+	dst.timeOffset = src.timeOffset
+	dst.tolerance = src.tolerance
+	dst.rollbackPolicy = src.rollbackPolicy
+	dst.rollbackHook = src.rollbackHook
+	dst.exhaustedHook = src.exhaustedHook
+	dst.waitStrategy = src.waitStrategy
+	dst.maxWait = src.maxWait
+	dst.perMilli = src.perMilli
+	dst.ahead = src.ahead
+	dst.jitter = src.jitter
+	dst.logger = src.logger
+	dst.logLevel = src.logLevel
+	dst.timeCipher = src.timeCipher
+	dst.lockDir = src.lockDir
+	dst.processDir = src.processDir
+	dst.processBits = src.processBits
+	dst.usedRanges = src.usedRanges
+	dst.clock = src.clock
+	dst.layout = src.layout
+	dst.layout.Order = append([]Part(nil), src.layout.Order...)
+	dst.bits = dst.layout.bits()
+}
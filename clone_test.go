@@ -0,0 +1,39 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSnowFlake_Clone(t *testing.T) {
+	var l = Layout{Entity: 2, DataCenter: 4, Machine: 6, Sequence: 10}
+	var base, _ = New(WithLayout(l), WithTimeOffset(testEpoch), WithDataCenter(3), WithMachine(1),
+		WithBackwardsTolerance(50*time.Millisecond), WithRandomSequenceStart(), WithMaxSequencePerMilli(100))
+
+	var clone, err = base.Clone(WithMachine(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.Layout().bits() != l.bits() || !clone.Epoch().Equal(testEpoch) || clone.DataCenterID() != 3 || clone.MachineID() != 2 {
+		t.Fatalf("clone layout = %+v, epoch = %v, dc = %d, machine = %d", clone.Layout(), clone.Epoch(), clone.DataCenterID(), clone.MachineID())
+	}
+	if clone.tolerance != 50 || clone.perMilli != 100 || clone.random == nil || clone.random == base.random {
+		t.Fatalf("clone tolerance = %d, per milli = %d, random = %p", clone.tolerance, clone.perMilli, clone.random)
+	}
+	if id := clone.NextFor(3); clone.Decode(id).Machine != 2 || clone.Decode(id).Entity != 3 {
+		t.Fatalf("clone generated %s", clone.Explain(id))
+	}
+
+	// 工作节点标识相同时会生成重复的 id
+	if _, err = base.Clone(); !errors.Is(err, ErrCloneSameWorker) {
+		t.Fatalf("Clone() error = %v, want %v", err, ErrCloneSameWorker)
+	}
+	if _, err = base.Clone(WithWorkerID(3<<6 | 1)); !errors.Is(err, ErrCloneSameWorker) {
+		t.Fatalf("Clone(WithWorkerID) error = %v, want %v", err, ErrCloneSameWorker)
+	}
+	// 覆盖的配置同样使用最终的布局校验
+	if _, err = base.Clone(WithMachine(64)); !errors.Is(err, ErrWorkerNotAllowed) {
+		t.Fatalf("Clone(WithMachine(64)) error = %v, want %v", err, ErrWorkerNotAllowed)
+	}
+}
@@ -0,0 +1,119 @@
+// snowflake-audit 检查导出的 id 中重复和不合法的值，并统计每个工作节点标识和每天的 id 数量，结果以 JSON 格式输出，如：
+//
+//	snowflake-audit -column order_id -workers 0:1,0:2,1:1 -snowflake.epoch 2024-01-01T00:00:00Z orders_a.csv orders_b.csv
+//
+// 没有指定文件时从标准输入读取，多个文件按照一个数据集检查，用于确认合并的多个数据库之间没有重复的 id。
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/smartwalle/snowflake"
+	"github.com/smartwalle/snowflake/dupcheck"
+)
+
+func main() {
+	var fs = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	var column = fs.String("column", "", "csv column holding the ids, the first column if empty")
+	var workers = fs.String("workers", "", "comma separated known workers as datacenter:machine, empty to accept any")
+	var expected = fs.Int("expected", 10000000, "expected number of ids, used to size the duplicate filter")
+	var cfg = snowflake.RegisterFlags(fs)
+	fs.Parse(os.Args[1:])
+
+	if err := run(*cfg, *column, *workers, *expected, fs.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(cfg snowflake.Config, column, workers string, expected int, files []string) error {
+	var generator, err = snowflake.NewWithConfig(cfg)
+	if err != nil {
+		return err
+	}
+
+	var opts = []dupcheck.AnalyzeOption{dupcheck.WithChecker(dupcheck.New(expected, 0.001, 1<<20))}
+	if column != "" {
+		opts = append(opts, dupcheck.WithColumn(column))
+	}
+	if workers != "" {
+		var known, err = parseWorkers(workers)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, dupcheck.WithKnownWorkers(func(dataCenter, machine int64) bool {
+			return known[[2]int64{dataCenter, machine}]
+		}))
+	}
+
+	var readers []io.Reader
+	if len(files) == 0 {
+		readers = append(readers, os.Stdin)
+	}
+	for _, name := range files {
+		var file, err = os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		readers = append(readers, file)
+	}
+
+	// 每个文件都可能有表头，需要分别读取，使用同一个 Checker 检测文件之间的重复
+	var total dupcheck.Report
+	for _, r := range readers {
+		var report, err = dupcheck.Analyze(r, generator, opts...)
+		if err != nil {
+			return err
+		}
+		total = merge(total, report)
+	}
+
+	var encoder = json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(total)
+}
+
+func parseWorkers(s string) (map[[2]int64]bool, error) {
+	var known = make(map[[2]int64]bool)
+	for _, part := range strings.Split(s, ",") {
+		var fields = strings.SplitN(strings.TrimSpace(part), ":", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("snowflake-audit: invalid worker %q, want datacenter:machine", part)
+		}
+		var dc, err1 = strconv.ParseInt(fields[0], 10, 64)
+		var m, err2 = strconv.ParseInt(fields[1], 10, 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("snowflake-audit: invalid worker %q, want datacenter:machine", part)
+		}
+		known[[2]int64{dc, m}] = true
+	}
+	return known, nil
+}
+
+func merge(a, b dupcheck.Report) dupcheck.Report {
+	a.Total += b.Total
+	a.Unparsable += b.Unparsable
+	a.Duplicates += b.Duplicates
+	a.MaybeDuplicates += b.MaybeDuplicates
+	a.FutureTimestamps += b.FutureTimestamps
+	a.InvalidWorkers += b.InvalidWorkers
+	a.DuplicateIDs = append(a.DuplicateIDs, b.DuplicateIDs...)
+	if a.Workers == nil {
+		a.Workers = make(map[string]int64)
+		a.Days = make(map[string]int64)
+	}
+	for k, v := range b.Workers {
+		a.Workers[k] += v
+	}
+	for k, v := range b.Days {
+		a.Days[k] += v
+	}
+	return a
+}
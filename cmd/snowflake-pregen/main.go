@@ -0,0 +1,81 @@
+// snowflake-pregen 离线生成 id 并写入文件，用于无法访问 id 服务的批处理系统预先为记录分配 id，如：
+//
+//	snowflake-pregen -n 1000000 -out ids.txt -ranges /var/lib/snowflake/ranges.jsonl -snowflake.datacenter 31 -snowflake.machine 0
+//
+// 使用过的时间范围会追加到 -ranges 指定的文件中，使用相同工作节点标识的在线生成器需要通过 snowflake.WithUsedRanges 读取该文件，
+// 多次运行时也会读取该文件，避免与之前生成的 id 重复。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/smartwalle/snowflake"
+)
+
+func main() {
+	var fs = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	var n = fs.Int64("n", 0, "number of ids to generate")
+	var format = fs.String("format", "decimal", "output format, decimal or binary")
+	var out = fs.String("out", "-", "output file, - for stdout")
+	var ranges = fs.String("ranges", "snowflake-ranges.jsonl", "file recording the used time ranges")
+	var cfg = snowflake.RegisterFlags(fs)
+	fs.Parse(os.Args[1:])
+
+	if err := run(*cfg, *n, *format, *out, *ranges); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(cfg snowflake.Config, n int64, format, out, ranges string) error {
+	if n <= 0 {
+		return fmt.Errorf("snowflake-pregen: -n must be positive")
+	}
+	var f snowflake.PregenFormat
+	switch format {
+	case "decimal":
+		f = snowflake.PregenDecimal
+	case "binary":
+		f = snowflake.PregenBinary
+	default:
+		return fmt.Errorf("snowflake-pregen: unknown format %q", format)
+	}
+
+	cfg.UsedRanges = ranges
+	var generator, err = snowflake.NewWithConfig(cfg)
+	if err != nil {
+		return err
+	}
+	defer generator.Close()
+
+	var w io.Writer = os.Stdout
+	if out != "-" {
+		var file *os.File
+		if file, err = os.OpenFile(out, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644); err != nil {
+			return err
+		}
+		defer file.Close()
+		w = file
+	}
+
+	used, err := generator.Pregenerate(w, n, f)
+	// 写入失败时已经生成的 id 也可能被使用，同样需要记录时间范围
+	if used.Count > 0 {
+		if rerr := snowflake.RecordUsedRange(ranges, used); rerr != nil {
+			return rerr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if file, ok := w.(*os.File); ok && file != os.Stdout {
+		if err = file.Sync(); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(os.Stderr, "generated %d ids between %s and %s\n", used.Count, used.From.Format("2006-01-02T15:04:05.000Z07:00"), used.To.Format("2006-01-02T15:04:05.000Z07:00"))
+	return nil
+}
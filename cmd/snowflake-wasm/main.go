@@ -0,0 +1,128 @@
+//go:build js && wasm
+// +build js,wasm
+
+// snowflake-wasm 将 id 的解析和编码导出给 JavaScript 使用，浏览器和 Node.js 可以使用与服务端完全相同的逻辑解析 id。
+//
+// 编译：
+//
+//	GOOS=js GOARCH=wasm go build -o snowflake.wasm ./cmd/snowflake-wasm
+//
+// 使用 Go 自带的 wasm_exec.js 加载之后，全局对象 snowflake 提供以下函数，id 使用十进制字符串传递，避免超出 JavaScript 数字的精度：
+//
+//	snowflake.decode(id, epochMillis, layout) // 解析 id，layout 为 ParseLayout 的格式，可以省略，返回各组成部分
+//	snowflake.encode(id, encoding)            // 编码 id，encoding 为 base62、base58、base32、base64url、hex 或者 padded
+//	snowflake.parse(s, encoding)              // 将 encode 的结果解析为十进制的 id
+//
+// 失败时返回包含 error 字段的对象，如：{error: "snowflake: invalid decimal id"}。
+package main
+
+import (
+	"strconv"
+	"syscall/js"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+)
+
+var encoders = map[string]func(int64) string{
+	"base62":    snowflake.Base62,
+	"base58":    snowflake.Base58,
+	"base32":    snowflake.Base32,
+	"base64url": snowflake.Base64URL,
+	"hex":       snowflake.Hex,
+	"padded":    snowflake.PaddedString,
+}
+
+var parsers = map[string]func(string) (int64, error){
+	"base62":    snowflake.ParseBase62,
+	"base58":    snowflake.ParseBase58,
+	"base32":    snowflake.ParseBase32,
+	"base64url": snowflake.ParseBase64URL,
+	"hex":       snowflake.ParseHex,
+	"padded":    snowflake.ParsePadded,
+	"decimal":   snowflake.ParseDecimal,
+}
+
+func main() {
+	var exports = map[string]interface{}{
+		"decode": js.FuncOf(decode),
+		"encode": js.FuncOf(encode),
+		"parse":  js.FuncOf(parse),
+	}
+	js.Global().Set("snowflake", js.ValueOf(exports))
+
+	// 保持运行，否则导出的函数无法调用
+	select {}
+}
+
+func failed(err string) interface{} {
+	return map[string]interface{}{"error": err}
+}
+
+func arg(args []js.Value, i int) string {
+	if i >= len(args) || args[i].IsUndefined() || args[i].IsNull() {
+		return ""
+	}
+	if args[i].Type() == js.TypeNumber {
+		return strconv.FormatInt(int64(args[i].Float()), 10)
+	}
+	return args[i].String()
+}
+
+func decode(this js.Value, args []js.Value) interface{} {
+	var id, err = snowflake.ParseDecimal(arg(args, 0))
+	if err != nil {
+		return failed(err.Error())
+	}
+	var epoch int64
+	if v := arg(args, 1); v != "" {
+		if epoch, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return failed("snowflake: invalid epoch " + v)
+		}
+	}
+	var layout = snowflake.DefaultLayout
+	if v := arg(args, 2); v != "" {
+		if layout, err = snowflake.ParseLayout(v); err != nil {
+			return failed(err.Error())
+		}
+	}
+
+	var p = layout.Decode(time.Unix(0, epoch*int64(time.Millisecond)), id)
+	return map[string]interface{}{
+		"id":         strconv.FormatInt(p.ID, 10),
+		"shard":      p.Shard,
+		"version":    p.Version,
+		"timestamp":  p.Timestamp,
+		"time":       p.Time.UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+		"entity":     p.Entity,
+		"region":     p.Region,
+		"dataCenter": p.DataCenter,
+		"machine":    p.Machine,
+		"boot":       p.Boot,
+		"sequence":   p.Sequence,
+	}
+}
+
+func encode(this js.Value, args []js.Value) interface{} {
+	var id, err = snowflake.ParseDecimal(arg(args, 0))
+	if err != nil {
+		return failed(err.Error())
+	}
+	var encoder, ok = encoders[arg(args, 1)]
+	if !ok {
+		return failed("snowflake: unknown encoding " + arg(args, 1))
+	}
+	return encoder(id)
+}
+
+func parse(this js.Value, args []js.Value) interface{} {
+	var parser, ok = parsers[arg(args, 1)]
+	if !ok {
+		return failed("snowflake: unknown encoding " + arg(args, 1))
+	}
+	var id, err = parser(arg(args, 0))
+	if err != nil {
+		return failed(err.Error())
+	}
+	return strconv.FormatInt(id, 10)
+}
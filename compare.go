@@ -0,0 +1,51 @@
+package snowflake
+
+import (
+	"time"
+)
+
+// SameInstant 判断两个 id 的生成时间相差是否不超过 window，只比较时间戳，忽略工作节点标识和序列号等部分，
+// 用于判断不同的工作节点记录的是否为同一个事件。
+func (this *SnowFlake) SameInstant(a, b int64, window time.Duration) bool {
+	var d = this.TimeOf(a).Sub(this.TimeOf(b))
+	if d < 0 {
+		d = -d
+	}
+	return d <= window
+}
+
+// SameInstant 判断两个 id 的生成时间相差是否不超过 window，使用默认生成器的时间偏移量
+func SameInstant(a, b int64, window time.Duration) bool {
+	return getDecoder().SameInstant(a, b, window)
+}
+
+// CreatedBefore 判断 id 的生成时间是否早于 t
+func (this *SnowFlake) CreatedBefore(s int64, t time.Time) bool {
+	return this.TimeOf(s).Before(t)
+}
+
+// CreatedAfter 判断 id 的生成时间是否晚于 t
+func (this *SnowFlake) CreatedAfter(s int64, t time.Time) bool {
+	return this.TimeOf(s).After(t)
+}
+
+// Between 判断 id 的生成时间是否在 [from, to) 范围内，用于数据保留和归档等按照时间筛选 id 的任务
+func (this *SnowFlake) Between(s int64, from, to time.Time) bool {
+	var t = this.TimeOf(s)
+	return !t.Before(from) && t.Before(to)
+}
+
+// CreatedBefore 判断 id 的生成时间是否早于 t，使用默认生成器的时间偏移量
+func CreatedBefore(s int64, t time.Time) bool {
+	return getDecoder().CreatedBefore(s, t)
+}
+
+// CreatedAfter 判断 id 的生成时间是否晚于 t，使用默认生成器的时间偏移量
+func CreatedAfter(s int64, t time.Time) bool {
+	return getDecoder().CreatedAfter(s, t)
+}
+
+// Between 判断 id 的生成时间是否在 [from, to) 范围内，使用默认生成器的时间偏移量
+func Between(s int64, from, to time.Time) bool {
+	return getDecoder().Between(s, from, to)
+}
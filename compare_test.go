@@ -0,0 +1,41 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnowFlake_SameInstant(t *testing.T) {
+	var a, _ = New(WithTimeOffset(testEpoch), WithMachine(1))
+	var b, _ = New(WithTimeOffset(testEpoch), WithMachine(2))
+
+	var at = testEpoch.Add(time.Hour)
+	var x, _ = a.ComposeAt(at, 0)
+	var y, _ = b.ComposeAt(at.Add(3*time.Millisecond), 9)
+
+	if !a.SameInstant(x, y, 3*time.Millisecond) || !a.SameInstant(y, x, 5*time.Millisecond) {
+		t.Fatalf("SameInstant(%d, %d) = false, want true", x, y)
+	}
+	if a.SameInstant(x, y, 2*time.Millisecond) || a.SameInstant(y, x, 0) {
+		t.Fatalf("SameInstant(%d, %d) = true, want false", x, y)
+	}
+	if !a.SameInstant(x, x, 0) {
+		t.Fatalf("SameInstant(%d, %d, 0) = false, want true", x, x)
+	}
+}
+
+func TestSnowFlake_Between(t *testing.T) {
+	var s, _ = New(WithTimeOffset(testEpoch), WithTimeEncryption([]byte("0123456789abcdef")))
+	var at = testEpoch.Add(48 * time.Hour)
+	var id = s.WithTime(s.Next(), at)
+
+	if !s.CreatedBefore(id, at.Add(time.Millisecond)) || s.CreatedBefore(id, at) {
+		t.Fatalf("CreatedBefore(%d) is wrong around %v", id, at)
+	}
+	if !s.CreatedAfter(id, at.Add(-time.Millisecond)) || s.CreatedAfter(id, at) {
+		t.Fatalf("CreatedAfter(%d) is wrong around %v", id, at)
+	}
+	if !s.Between(id, at, at.Add(time.Hour)) || s.Between(id, at.Add(-time.Hour), at) || s.Between(id, at.Add(time.Millisecond), at.Add(time.Hour)) {
+		t.Fatalf("Between(%d) should include from and exclude to", id)
+	}
+}
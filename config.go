@@ -0,0 +1,208 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidConfig = errors.New("snowflake: invalid config")
+)
+
+// Config 生成器的配置，可以和服务的其它配置放在同一个文件中，零值的字段使用默认值。
+//
+// 字段同时带有 json 和 yaml 标签，JSON 文件可以使用 LoadConfig 读取，YAML 文件可以使用 contrib/sfyaml 读取。
+type Config struct {
+	Epoch  string `json:"epoch,omitempty" yaml:"epoch,omitempty"`   // 时间偏移量，RFC 3339 格式的时间或者毫秒时间戳
+	Layout string `json:"layout,omitempty" yaml:"layout,omitempty"` // 布局，格式参考 ParseLayout
+
+	DataCenter int64  `json:"datacenter,omitempty" yaml:"datacenter,omitempty"`
+	Machine    int64  `json:"machine,omitempty" yaml:"machine,omitempty"`
+	WorkerID   *int64 `json:"worker_id,omitempty" yaml:"worker_id,omitempty"` // 设置之后忽略 DataCenter 和 Machine
+	Region     int64  `json:"region,omitempty" yaml:"region,omitempty"`
+	Version    int64  `json:"version,omitempty" yaml:"version,omitempty"`
+
+	BackwardsTolerance  string `json:"backwards_tolerance,omitempty" yaml:"backwards_tolerance,omitempty"`       // 可以容忍的时钟回拨，如 500ms
+	RollbackPolicy      string `json:"rollback_policy,omitempty" yaml:"rollback_policy,omitempty"`               // error、panic 或者 wait
+	WaitStrategy        string `json:"wait_strategy,omitempty" yaml:"wait_strategy,omitempty"`                   // yield、spin、sleep 或者 timer
+	MaxWait             string `json:"max_wait,omitempty" yaml:"max_wait,omitempty"`                             // 一次生成 id 最多等待的时长，如 50ms
+	MaxSequencePerMilli int64  `json:"max_sequence_per_milli,omitempty" yaml:"max_sequence_per_milli,omitempty"` // 每毫秒最多生成的 id 数量，为 0 时不限制
+	RandomSequenceStart bool   `json:"random_sequence_start,omitempty" yaml:"random_sequence_start,omitempty"`
+	LogLevel            string `json:"log_level,omitempty" yaml:"log_level,omitempty"` // 记录时钟回拨使用的日志级别，默认为 warn
+
+	BootCounter  *BootCounterConfig  `json:"boot_counter,omitempty" yaml:"boot_counter,omitempty"`
+	MachineLock  string              `json:"machine_lock,omitempty" yaml:"machine_lock,omitempty"` // WithExclusiveMachineLock 使用的目录
+	ProcessSlots *ProcessSlotsConfig `json:"process_slots,omitempty" yaml:"process_slots,omitempty"`
+	UsedRanges   string              `json:"used_ranges,omitempty" yaml:"used_ranges,omitempty"` // WithUsedRanges 使用的文件
+}
+
+// BootCounterConfig 对应 WithBootCounter 的参数
+type BootCounterConfig struct {
+	Path string `json:"path" yaml:"path"`
+	Bits uint8  `json:"bits" yaml:"bits"`
+}
+
+// ProcessSlotsConfig 对应 WithProcessSlots 的参数
+type ProcessSlotsConfig struct {
+	Dir  string `json:"dir" yaml:"dir"`
+	Bits uint8  `json:"bits" yaml:"bits"`
+}
+
+// LoadConfig 从 JSON 文件中读取配置
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	var data, err = os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err = json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("%w %s: %w", ErrInvalidConfig, path, err)
+	}
+	return cfg, nil
+}
+
+// NewWithConfig 使用配置创建生成器，opts 在配置之后生效
+func NewWithConfig(cfg Config, opts ...Option) (*SnowFlake, error) {
+	var cfgOpts, err = cfg.Options()
+	if err != nil {
+		return nil, err
+	}
+	return New(append(cfgOpts, opts...)...)
+}
+
+// Options 将配置转换为 Option，字段的值无法解析时返回的错误包含字段名，可以通过 errors.Is 判断是否为 ErrInvalidConfig
+func (c Config) Options() ([]Option, error) {
+	var opts, err = c.identityOptions()
+	if err != nil {
+		return nil, err
+	}
+	var runtime []Option
+	if runtime, err = c.runtimeOptions(); err != nil {
+		return nil, err
+	}
+	return append(opts, runtime...), nil
+}
+
+// identityOptions 影响生成的 id 的配置，只能在创建生成器时设置
+func (c Config) identityOptions() ([]Option, error) {
+	var opts []Option
+	if c.Layout != "" {
+		var l, err = ParseLayout(c.Layout)
+		if err != nil {
+			return nil, configError("layout", c.Layout, err)
+		}
+		opts = append(opts, WithLayout(l))
+	}
+	if c.Epoch != "" {
+		var epoch, err = parseEpoch(c.Epoch)
+		if err != nil {
+			return nil, configError("epoch", c.Epoch, err)
+		}
+		opts = append(opts, WithTimeOffset(epoch))
+	}
+	if c.BootCounter != nil {
+		opts = append(opts, WithBootCounter(c.BootCounter.Path, c.BootCounter.Bits))
+	}
+
+	if c.WorkerID != nil {
+		opts = append(opts, WithWorkerID(*c.WorkerID))
+	} else {
+		opts = append(opts, WithDataCenter(c.DataCenter), WithMachine(c.Machine))
+	}
+	if c.Region != 0 {
+		opts = append(opts, WithRegion(c.Region))
+	}
+	if c.Version != 0 {
+		opts = append(opts, WithVersion(c.Version))
+	}
+	if c.MachineLock != "" {
+		opts = append(opts, WithExclusiveMachineLock(c.MachineLock))
+	}
+	if c.ProcessSlots != nil {
+		opts = append(opts, WithProcessSlots(c.ProcessSlots.Dir, c.ProcessSlots.Bits))
+	}
+	if c.UsedRanges != "" {
+		opts = append(opts, WithUsedRanges(c.UsedRanges))
+	}
+	return opts, nil
+}
+
+// runtimeOptions 不影响生成的 id 的配置，可以通过 Reload 在运行期间调整，没有设置的字段恢复为默认值
+func (c Config) runtimeOptions() ([]Option, error) {
+	var opts []Option
+	if c.BackwardsTolerance != "" {
+		var d, err = time.ParseDuration(c.BackwardsTolerance)
+		if err != nil {
+			return nil, configError("backwards_tolerance", c.BackwardsTolerance, err)
+		}
+		opts = append(opts, WithBackwardsTolerance(d))
+	} else {
+		opts = append(opts, WithBackwardsTolerance(time.Duration(kDefaultBackwardsTolerance)*time.Millisecond))
+	}
+	if c.RollbackPolicy != "" {
+		var policy, err = parseRollbackPolicy(c.RollbackPolicy)
+		if err != nil {
+			return nil, configError("rollback_policy", c.RollbackPolicy, err)
+		}
+		opts = append(opts, WithRollbackPolicy(policy))
+	} else {
+		opts = append(opts, WithRollbackPolicy(PolicyError))
+	}
+	var strategy = WaitYield
+	if c.WaitStrategy != "" {
+		var err error
+		if strategy, err = parseWaitStrategy(c.WaitStrategy); err != nil {
+			return nil, configError("wait_strategy", c.WaitStrategy, err)
+		}
+	}
+	opts = append(opts, WithWaitStrategy(strategy))
+	var maxWait time.Duration
+	if c.MaxWait != "" {
+		var err error
+		if maxWait, err = time.ParseDuration(c.MaxWait); err != nil {
+			return nil, configError("max_wait", c.MaxWait, err)
+		}
+	}
+	opts = append(opts, WithMaxWait(maxWait))
+	opts = append(opts, WithMaxSequencePerMilli(c.MaxSequencePerMilli))
+	if c.RandomSequenceStart {
+		opts = append(opts, WithRandomSequenceStart())
+	} else {
+		opts = append(opts, optionFunc(func(s *SnowFlake) error {
+			s.random = nil
+			return nil
+		}))
+	}
+	var level = slog.LevelWarn
+	if c.LogLevel != "" {
+		if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+			return nil, configError("log_level", c.LogLevel, err)
+		}
+	}
+	opts = append(opts, optionFunc(func(s *SnowFlake) error {
+		s.logLevel = level
+		return nil
+	}))
+	return opts, nil
+}
+
+func configError(field, value string, err error) error {
+	return fmt.Errorf("%w %s=%q: %w", ErrInvalidConfig, field, value, err)
+}
+
+func parseRollbackPolicy(s string) (RollbackPolicy, error) {
+	switch strings.ToLower(s) {
+	case "error":
+		return PolicyError, nil
+	case "panic":
+		return PolicyPanic, nil
+	case "wait":
+		return PolicyWait, nil
+	}
+	return PolicyError, errors.New("snowflake: rollback policy should be error, panic or wait")
+}
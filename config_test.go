@@ -0,0 +1,80 @@
+package snowflake
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	var dir = t.TempDir()
+	var path = filepath.Join(dir, "snowflake.json")
+	var data = `{
+	"epoch": "2024-01-01T00:00:00Z",
+	"layout": "region=2,datacenter=4,machine=6,sequence=12",
+	"datacenter": 9,
+	"machine": 33,
+	"region": 3,
+	"backwards_tolerance": "200ms",
+	"rollback_policy": "wait",
+	"boot_counter": {"path": "` + filepath.Join(dir, "boot") + `", "bits": 2}
+}`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg, err = LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s *SnowFlake
+	if s, err = NewWithConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	var id = s.Next()
+	if p := s.Decode(id); p.Region != 3 || p.DataCenter != 9 || p.Machine != 33 || p.Boot != 1 {
+		t.Fatalf("Decode(%d) = %+v", id, p)
+	}
+	assertRecent(t, s, id)
+	if !s.Epoch().Equal(testEpoch) || s.tolerance != 200 || s.rollbackPolicy != PolicyWait {
+		t.Fatalf("Epoch() = %v, tolerance = %d, policy = %d", s.Epoch(), s.tolerance, s.rollbackPolicy)
+	}
+}
+
+func TestConfig_WorkerID(t *testing.T) {
+	var worker int64 = 1023
+	var s, err = NewWithConfig(Config{WorkerID: &worker, DataCenter: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.DataCenterID() != 31 || s.MachineID() != 31 {
+		t.Fatalf("DataCenterID() = %d, MachineID() = %d", s.DataCenterID(), s.MachineID())
+	}
+}
+
+func TestConfig_Invalid(t *testing.T) {
+	var tests = []Config{
+		{Epoch: "now"},
+		{Layout: "sequence=0"},
+		{BackwardsTolerance: "1"},
+		{RollbackPolicy: "ignore"},
+		{WaitStrategy: "busy"},
+	}
+	for i, cfg := range tests {
+		if _, err := NewWithConfig(cfg); !errors.Is(err, ErrInvalidConfig) {
+			t.Fatalf("%d: NewWithConfig() error = %v, want %v", i, err, ErrInvalidConfig)
+		}
+	}
+
+	var path = filepath.Join(t.TempDir(), "snowflake.json")
+	os.WriteFile(path, []byte(`{"machine": "one"}`), 0644)
+	if _, err := LoadConfig(path); !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("LoadConfig() error = %v, want %v", err, ErrInvalidConfig)
+	}
+	if _, err := NewWithConfig(Config{BackwardsTolerance: (time.Second).String(), Machine: 32}); !errors.Is(err, ErrWorkerNotAllowed) {
+		t.Fatalf("NewWithConfig() error = %v, want %v", err, ErrWorkerNotAllowed)
+	}
+}
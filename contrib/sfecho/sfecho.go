@@ -0,0 +1,39 @@
+// Package sfecho 提供 echo 的请求 id 中间件，行为与 requestid.Middleware 一致。
+package sfecho
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/smartwalle/snowflake"
+	"github.com/smartwalle/snowflake/requestid"
+)
+
+const (
+	// ContextKey 请求 id 在 echo.Context 中的名称
+	ContextKey = requestid.AttrKey
+)
+
+// RequestID 为每一个请求分配请求 id，请求 id 会写入响应的 X-Request-ID 头，并且保存在 echo.Context 和请求的 context 中。
+//
+// 请求中已经包含有效的 X-Request-ID 头时会沿用上游的请求 id，生成 id 失败时不会中断请求，只是没有请求 id。
+func RequestID(generator snowflake.Generator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			var r = c.Request()
+			var id, err = requestid.Resolve(generator, r.Header.Get(requestid.Header))
+			if err == nil {
+				c.Response().Header().Set(requestid.Header, strconv.FormatInt(id, 10))
+				c.Set(ContextKey, id)
+				c.SetRequest(r.WithContext(requestid.NewContext(r.Context(), id)))
+			}
+			return next(c)
+		}
+	}
+}
+
+// FromContext 获取 RequestID 分配的请求 id
+func FromContext(c echo.Context) (int64, bool) {
+	var id, ok = c.Get(ContextKey).(int64)
+	return id, ok
+}
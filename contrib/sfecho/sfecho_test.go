@@ -0,0 +1,38 @@
+package sfecho
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/smartwalle/snowflake"
+	"github.com/smartwalle/snowflake/requestid"
+)
+
+func TestRequestID(t *testing.T) {
+	var s, _ = snowflake.New()
+
+	var fromEcho, fromRequest int64
+	var e = echo.New()
+	e.Use(RequestID(s))
+	e.GET("/", func(c echo.Context) error {
+		fromEcho, _ = FromContext(c)
+		fromRequest, _ = requestid.FromContext(c.Request().Context())
+		return nil
+	})
+
+	var w = httptest.NewRecorder()
+	e.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if fromEcho <= 0 || fromEcho != fromRequest || w.Header().Get(requestid.Header) != strconv.FormatInt(fromEcho, 10) {
+		t.Fatalf("echo = %d, request = %d, header = %q", fromEcho, fromRequest, w.Header().Get(requestid.Header))
+	}
+
+	var req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(requestid.Header, "12345")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	if fromEcho != 12345 {
+		t.Fatalf("request id = %d, want 12345", fromEcho)
+	}
+}
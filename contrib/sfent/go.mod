@@ -0,0 +1,12 @@
+module github.com/smartwalle/snowflake/contrib/sfent
+
+go 1.24
+
+require (
+	entgo.io/ent v0.14.6
+	github.com/smartwalle/snowflake v0.0.0
+)
+
+require github.com/google/uuid v1.3.0 // indirect
+
+replace github.com/smartwalle/snowflake => ../../
@@ -0,0 +1,73 @@
+// Package sfent 提供 ent 的 mixin 和字段，使用 snowflake 生成 int64 类型的 id。
+package sfent
+
+import (
+	"errors"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/mixin"
+	"github.com/smartwalle/snowflake"
+)
+
+var (
+	ErrGenerateFailed = errors.New("sfent: failed to generate snowflake id")
+)
+
+// Mixin 为 schema 声明由 generator 生成的 int64 类型的 id 字段，如：
+//
+//	func (Order) Mixin() []ent.Mixin {
+//		return []ent.Mixin{sfent.NewMixin(generator)}
+//	}
+//
+// 使用自定义起始时间、布局的生成器时只需要传入对应的 Generator，需要保证生成 schema 代码和运行时使用的是同一个生成器。
+type Mixin struct {
+	mixin.Schema
+	generator snowflake.Generator
+}
+
+// NewMixin 创建 Mixin，generator 用于生成 id
+func NewMixin(generator snowflake.Generator) Mixin {
+	return Mixin{generator: generator}
+}
+
+func (this Mixin) Fields() []ent.Field {
+	return []ent.Field{Field("id", this.generator)}
+}
+
+// Field 声明由 generator 生成的 int64 类型的字段，字段创建之后不可修改。
+//
+// ent 的默认值函数不能返回错误，生成 id 失败时默认值为 -1，并由字段的校验返回 ErrGenerateFailed，避免写入无效的 id。
+func Field(name string, generator snowflake.Generator, annotations ...schema.Annotation) ent.Field {
+	return field.Int64(name).
+		DefaultFunc(defaultFunc(generator)).
+		Validate(validate).
+		Immutable().
+		Unique().
+		Annotations(annotations...)
+}
+
+// MigrationDefault 返回数据库层面的默认值 0，用于通过 Field 为已有数据的表增加字段，
+// 已有的记录在迁移之后为 0，需要另外回填，新的记录仍然由 generator 生成。
+func MigrationDefault() schema.Annotation {
+	return entsql.Default("0")
+}
+
+func defaultFunc(generator snowflake.Generator) func() int64 {
+	return func() int64 {
+		var id, err = generator.NextID()
+		if err != nil {
+			return -1
+		}
+		return id
+	}
+}
+
+func validate(id int64) error {
+	if id < 0 {
+		return ErrGenerateFailed
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package sfent
+
+import (
+	"errors"
+	"testing"
+
+	"entgo.io/ent/dialect/entsql"
+)
+
+type counter struct {
+	next int64
+	err  error
+}
+
+func (this *counter) NextID() (int64, error) {
+	if this.err != nil {
+		return 0, this.err
+	}
+	this.next++
+	return this.next, nil
+}
+
+func TestMixin(t *testing.T) {
+	var g = &counter{next: 100}
+	var fields = NewMixin(g).Fields()
+	if len(fields) != 1 {
+		t.Fatalf("Fields() returned %d fields, want 1", len(fields))
+	}
+
+	var desc = fields[0].Descriptor()
+	if desc.Err != nil {
+		t.Fatal(desc.Err)
+	}
+	if desc.Name != "id" || !desc.Immutable || !desc.Unique {
+		t.Fatalf("Descriptor() = %+v", desc)
+	}
+
+	var fn = desc.Default.(func() int64)
+	var validate = desc.Validators[0].(func(int64) error)
+	if id := fn(); id != 101 || validate(id) != nil {
+		t.Fatalf("default = %d, validate = %v, want 101, nil", id, validate(id))
+	}
+
+	g.err = errors.New("generator unavailable")
+	if id := fn(); validate(id) != ErrGenerateFailed {
+		t.Fatalf("validate(%d) = %v, want %v", id, validate(id), ErrGenerateFailed)
+	}
+}
+
+func TestField_MigrationDefault(t *testing.T) {
+	var desc = Field("order_no", &counter{}, MigrationDefault()).Descriptor()
+	if len(desc.Annotations) != 1 {
+		t.Fatalf("Annotations = %v, want 1 annotation", desc.Annotations)
+	}
+	if a, ok := desc.Annotations[0].(*entsql.Annotation); !ok || a.Default != "0" {
+		t.Fatalf("Annotations[0] = %#v, want entsql default 0", desc.Annotations[0])
+	}
+}
@@ -0,0 +1,40 @@
+// Package sfgin 提供 gin 的请求 id 中间件，行为与 requestid.Middleware 一致。
+package sfgin
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartwalle/snowflake"
+	"github.com/smartwalle/snowflake/requestid"
+)
+
+const (
+	// ContextKey 请求 id 在 gin.Context 中的名称
+	ContextKey = requestid.AttrKey
+)
+
+// RequestID 为每一个请求分配请求 id，请求 id 会写入响应的 X-Request-ID 头，并且保存在 gin.Context 和请求的 context 中。
+//
+// 请求中已经包含有效的 X-Request-ID 头时会沿用上游的请求 id，生成 id 失败时不会中断请求，只是没有请求 id。
+func RequestID(generator snowflake.Generator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var id, err = requestid.Resolve(generator, c.GetHeader(requestid.Header))
+		if err == nil {
+			c.Header(requestid.Header, strconv.FormatInt(id, 10))
+			c.Set(ContextKey, id)
+			c.Request = c.Request.WithContext(requestid.NewContext(c.Request.Context(), id))
+		}
+		c.Next()
+	}
+}
+
+// FromContext 获取 RequestID 分配的请求 id
+func FromContext(c *gin.Context) (int64, bool) {
+	var id, ok = c.Get(ContextKey)
+	if !ok {
+		return 0, false
+	}
+	v, ok := id.(int64)
+	return v, ok
+}
@@ -0,0 +1,38 @@
+package sfgin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/smartwalle/snowflake"
+	"github.com/smartwalle/snowflake/requestid"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var s, _ = snowflake.New()
+
+	var fromGin, fromRequest int64
+	var r = gin.New()
+	r.Use(RequestID(s))
+	r.GET("/", func(c *gin.Context) {
+		fromGin, _ = FromContext(c)
+		fromRequest, _ = requestid.FromContext(c.Request.Context())
+	})
+
+	var w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if fromGin <= 0 || fromGin != fromRequest || w.Header().Get(requestid.Header) != strconv.FormatInt(fromGin, 10) {
+		t.Fatalf("gin = %d, request = %d, header = %q", fromGin, fromRequest, w.Header().Get(requestid.Header))
+	}
+
+	var req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(requestid.Header, "12345")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	if fromGin != 12345 {
+		t.Fatalf("request id = %d, want 12345", fromGin)
+	}
+}
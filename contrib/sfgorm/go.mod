@@ -0,0 +1,16 @@
+module github.com/smartwalle/snowflake/contrib/sfgorm
+
+go 1.21
+
+require (
+	github.com/smartwalle/snowflake v0.0.0
+	gorm.io/gorm v1.31.2
+)
+
+require (
+	github.com/jinzhu/inflection v1.0.0 // indirect
+	github.com/jinzhu/now v1.1.5 // indirect
+	golang.org/x/text v0.20.0 // indirect
+)
+
+replace github.com/smartwalle/snowflake => ../../
@@ -0,0 +1,87 @@
+// Package sfgorm 提供 GORM 插件，在创建记录时自动为标记了 snowflake 的字段生成 id。
+package sfgorm
+
+import (
+	"reflect"
+
+	"github.com/smartwalle/snowflake"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	kTagSetting = "SNOWFLAKE"
+)
+
+// Plugin GORM 插件，在执行 Create 之前为值为零的、标记了 snowflake 的字段生成 id，如：
+//
+//	type Order struct {
+//		ID   int64 `gorm:"primaryKey;snowflake"`
+//		Name string
+//	}
+//
+//	db.Use(sfgorm.New(generator))
+//
+// 字段的类型需要是 int64 或者底层类型为 int64 的类型，使用自定义起始时间、布局的生成器时只需要传入对应的 Generator。
+type Plugin struct {
+	generator snowflake.Generator
+}
+
+// New 创建插件，generator 用于生成 id
+func New(generator snowflake.Generator) *Plugin {
+	var p = &Plugin{}
+	p.generator = generator
+	return p
+}
+
+func (this *Plugin) Name() string {
+	return "snowflake"
+}
+
+func (this *Plugin) Initialize(db *gorm.DB) error {
+	return db.Callback().Create().Before("gorm:create").Register("snowflake:assign_id", this.assign)
+}
+
+func (this *Plugin) assign(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	var fields []*schema.Field
+	for _, field := range db.Statement.Schema.Fields {
+		if _, ok := field.TagSettings[kTagSetting]; ok {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return
+	}
+
+	var rv = db.Statement.ReflectValue
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			this.assignValue(db, fields, reflect.Indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		this.assignValue(db, fields, rv)
+	}
+}
+
+func (this *Plugin) assignValue(db *gorm.DB, fields []*schema.Field, rv reflect.Value) {
+	var ctx = db.Statement.Context
+	for _, field := range fields {
+		if _, zero := field.ValueOf(ctx, rv); !zero {
+			continue
+		}
+		var id, err = this.generator.NextID()
+		if err != nil {
+			db.AddError(err)
+			return
+		}
+		if err = field.Set(ctx, rv, id); err != nil {
+			db.AddError(err)
+			return
+		}
+	}
+}
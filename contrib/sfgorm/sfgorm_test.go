@@ -0,0 +1,69 @@
+package sfgorm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/smartwalle/snowflake"
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+type order struct {
+	ID   int64 `gorm:"primaryKey;snowflake"`
+	Name string
+}
+
+type counter struct {
+	next int64
+	err  error
+}
+
+func (this *counter) NextID() (int64, error) {
+	if this.err != nil {
+		return 0, this.err
+	}
+	this.next++
+	return this.next, nil
+}
+
+func open(t *testing.T, generator snowflake.Generator) *gorm.DB {
+	var db, err = gorm.Open(tests.DummyDialector{}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = db.Use(New(generator)); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestPlugin(t *testing.T) {
+	var db = open(t, &counter{next: 100})
+
+	var o = order{Name: "a"}
+	if err := db.Create(&o).Error; err != nil {
+		t.Fatal(err)
+	}
+	if o.ID != 101 {
+		t.Fatalf("ID = %d, want 101", o.ID)
+	}
+
+	// 已经设置的 id 不会被覆盖
+	var orders = []*order{{Name: "b"}, {ID: 7, Name: "c"}, {Name: "d"}}
+	if err := db.Create(&orders).Error; err != nil {
+		t.Fatal(err)
+	}
+	if orders[0].ID != 102 || orders[1].ID != 7 || orders[2].ID != 103 {
+		t.Fatalf("IDs = %d, %d, %d, want 102, 7, 103", orders[0].ID, orders[1].ID, orders[2].ID)
+	}
+}
+
+func TestPlugin_Error(t *testing.T) {
+	var genErr = errors.New("generator unavailable")
+	var db = open(t, &counter{err: genErr})
+
+	if err := db.Create(&order{Name: "a"}).Error; !errors.Is(err, genErr) {
+		t.Fatalf("Create() error = %v, want %v", err, genErr)
+	}
+}
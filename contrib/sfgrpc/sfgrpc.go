@@ -0,0 +1,103 @@
+// Package sfgrpc 提供 gRPC 的请求 id 拦截器，通过 metadata 传递请求 id，与 requestid.Middleware 配合使用。
+package sfgrpc
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/smartwalle/snowflake"
+	"github.com/smartwalle/snowflake/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// MetadataKey 传递请求 id 的 metadata 名称
+	MetadataKey = "x-request-id"
+)
+
+// UnaryServerInterceptor 为每一个请求分配请求 id，上游通过 metadata 传递了有效的请求 id 时直接使用。
+//
+// 请求 id 保存在请求的 context 中，可以通过 FromContext 获取，同时会通过响应的 header 返回给客户端，生成 id 失败时不会中断请求。
+func UnaryServerInterceptor(generator snowflake.Generator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(serverContext(ctx, generator), req)
+	}
+}
+
+// StreamServerInterceptor 与 UnaryServerInterceptor 相同，用于流式请求
+func StreamServerInterceptor(generator snowflake.Generator) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: serverContext(ss.Context(), generator)})
+	}
+}
+
+// UnaryClientInterceptor 将 context 中的请求 id 通过 metadata 传递给服务端，如在处理 HTTP 请求时调用其它服务。
+//
+// context 中没有请求 id 并且 generator 不为 nil 时会生成新的请求 id。
+func UnaryClientInterceptor(generator snowflake.Generator) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(clientContext(ctx, generator), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor 与 UnaryClientInterceptor 相同，用于流式请求
+func StreamClientInterceptor(generator snowflake.Generator) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(clientContext(ctx, generator), desc, cc, method, opts...)
+	}
+}
+
+// FromContext 获取请求 id，优先使用拦截器保存在 context 中的请求 id，没有时从请求的 metadata 中获取
+func FromContext(ctx context.Context) (int64, bool) {
+	if id, ok := requestid.FromContext(ctx); ok {
+		return id, true
+	}
+	var md, _ = metadata.FromIncomingContext(ctx)
+	for _, v := range md.Get(MetadataKey) {
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil && id > 0 {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+func serverContext(ctx context.Context, generator snowflake.Generator) context.Context {
+	var incoming string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(MetadataKey); len(values) > 0 {
+			incoming = values[0]
+		}
+	}
+
+	var id, err = requestid.Resolve(generator, incoming)
+	if err != nil {
+		return ctx
+	}
+	// 没有通过 grpc.Server 调用时（如测试）无法设置 header，忽略错误
+	grpc.SetHeader(ctx, metadata.Pairs(MetadataKey, strconv.FormatInt(id, 10)))
+	return requestid.NewContext(ctx, id)
+}
+
+func clientContext(ctx context.Context, generator snowflake.Generator) context.Context {
+	var id, ok = requestid.FromContext(ctx)
+	if !ok {
+		if generator == nil {
+			return ctx
+		}
+		var err error
+		if id, err = generator.NextID(); err != nil {
+			return ctx
+		}
+	}
+	return metadata.AppendToOutgoingContext(ctx, MetadataKey, strconv.FormatInt(id, 10))
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (this *serverStream) Context() context.Context {
+	return this.ctx
+}
@@ -0,0 +1,86 @@
+package sfgrpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smartwalle/snowflake"
+	"github.com/smartwalle/snowflake/requestid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (this *testServerStream) Context() context.Context {
+	return this.ctx
+}
+
+func TestServerInterceptor(t *testing.T) {
+	var s, _ = snowflake.New()
+	var got int64
+	var handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+		got, _ = FromContext(ctx)
+		return nil, nil
+	}
+
+	var unary = UnaryServerInterceptor(s)
+	unary(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+	if got <= 0 {
+		t.Fatalf("request id = %d, want a generated id", got)
+	}
+
+	var ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "12345"))
+	unary(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	if got != 12345 {
+		t.Fatalf("request id = %d, want 12345", got)
+	}
+
+	got = 0
+	var stream = StreamServerInterceptor(s)
+	stream(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{}, func(srv interface{}, ss grpc.ServerStream) error {
+		got, _ = requestid.FromContext(ss.Context())
+		return nil
+	})
+	if got != 12345 {
+		t.Fatalf("stream request id = %d, want 12345", got)
+	}
+}
+
+func TestClientInterceptor(t *testing.T) {
+	var s, _ = snowflake.New()
+	var outgoing []string
+	var invoker = func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		var md, _ = metadata.FromOutgoingContext(ctx)
+		outgoing = md.Get(MetadataKey)
+		return nil
+	}
+
+	UnaryClientInterceptor(nil)(requestid.NewContext(context.Background(), 42), "/svc/Method", nil, nil, nil, invoker)
+	if len(outgoing) != 1 || outgoing[0] != "42" {
+		t.Fatalf("outgoing = %v, want [42]", outgoing)
+	}
+
+	UnaryClientInterceptor(nil)(context.Background(), "/svc/Method", nil, nil, nil, invoker)
+	if len(outgoing) != 0 {
+		t.Fatalf("outgoing = %v, want none", outgoing)
+	}
+
+	UnaryClientInterceptor(s)(context.Background(), "/svc/Method", nil, nil, nil, invoker)
+	if len(outgoing) != 1 || outgoing[0] == "" {
+		t.Fatalf("outgoing = %v, want a generated id", outgoing)
+	}
+}
+
+func TestFromContext_Metadata(t *testing.T) {
+	var ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "7"))
+	if id, ok := FromContext(ctx); !ok || id != 7 {
+		t.Fatalf("FromContext() = %d, %v, want 7, true", id, ok)
+	}
+	if _, ok := FromContext(context.Background()); ok {
+		t.Fatal("FromContext() should not find a request id")
+	}
+}
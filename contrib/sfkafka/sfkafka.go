@@ -0,0 +1,47 @@
+// Package sfkafka 将 audit 记录写入 Kafka。
+package sfkafka
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/smartwalle/snowflake/audit"
+)
+
+// MessageWriter 写入 Kafka 消息，*kafka.Writer 实现了该接口
+type MessageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// Sink 将每条记录写入一条 Kafka 消息，消息的 key 为十进制的 id，value 为 JSON 格式的记录
+type Sink struct {
+	writer  MessageWriter
+	timeout time.Duration
+}
+
+// NewSink 创建 Sink，timeout 为每次写入的超时时间，为 0 时不设置超时时间
+func NewSink(writer MessageWriter, timeout time.Duration) *Sink {
+	return &Sink{writer: writer, timeout: timeout}
+}
+
+func (this *Sink) Write(records []audit.Record) error {
+	var msgs = make([]kafka.Message, 0, len(records))
+	for _, r := range records {
+		var value, err = json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, kafka.Message{Key: []byte(strconv.FormatInt(r.ID, 10)), Value: value, Time: r.Time})
+	}
+
+	var ctx = context.Background()
+	if this.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, this.timeout)
+		defer cancel()
+	}
+	return this.writer.WriteMessages(ctx, msgs...)
+}
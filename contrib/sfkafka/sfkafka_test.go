@@ -0,0 +1,41 @@
+package sfkafka
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/smartwalle/snowflake"
+	"github.com/smartwalle/snowflake/audit"
+)
+
+type fakeWriter struct {
+	msgs []kafka.Message
+}
+
+func (this *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	this.msgs = append(this.msgs, msgs...)
+	return nil
+}
+
+var _ MessageWriter = (*kafka.Writer)(nil)
+
+func TestSink(t *testing.T) {
+	var fw = &fakeWriter{}
+	var w = audit.NewWriter(NewSink(fw, 0))
+	var s, _ = snowflake.New()
+	var g = audit.Wrap(s, w, "payments")
+	var id, _ = g.NextID()
+	w.Close()
+
+	if len(fw.msgs) != 1 {
+		t.Fatalf("wrote %d messages, want 1", len(fw.msgs))
+	}
+	var r audit.Record
+	json.Unmarshal(fw.msgs[0].Value, &r)
+	if r.ID != id || r.Label != "payments" || string(fw.msgs[0].Key) != strconv.FormatInt(id, 10) {
+		t.Fatalf("message %+v decodes to %+v", fw.msgs[0], r)
+	}
+}
@@ -0,0 +1,9 @@
+module github.com/smartwalle/snowflake/contrib/sfproto
+
+go 1.23
+
+require github.com/smartwalle/snowflake v0.0.0
+
+require google.golang.org/protobuf v1.36.10
+
+replace github.com/smartwalle/snowflake => ../../
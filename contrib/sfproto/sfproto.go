@@ -0,0 +1,91 @@
+// Package sfproto 提供 snowflake id 的 protobuf 消息定义和转换函数，用于在 gRPC 接口之间使用统一的、可以解析的 id 表示方式。
+//
+// 其它语言可以直接使用 snowflake.proto 生成代码。
+package sfproto
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative snowflake.proto
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+)
+
+var (
+	ErrInvalidID = errors.New("sfproto: value and text of the id don't match")
+)
+
+// FromID 创建只包含 id 的 SnowflakeID
+func FromID(id int64) *SnowflakeID {
+	return &SnowflakeID{Value: id, Text: strconv.FormatInt(id, 10)}
+}
+
+// FromGenerator 创建包含生成器布局信息的 SnowflakeID，接收方可以在没有对应生成器的情况下解析 id
+func FromGenerator(s *snowflake.SnowFlake, id int64) *SnowflakeID {
+	var x = FromID(id)
+	x.Layout = FromLayout(s.Layout(), s.Epoch())
+	return x
+}
+
+// FromLayout 将 snowflake.Layout 和时间偏移量转换为 Layout
+func FromLayout(l snowflake.Layout, epoch time.Time) *Layout {
+	var x = &Layout{}
+	x.EpochMs = epoch.UnixNano() / 1e6
+	x.ShardBits = uint32(l.Shard)
+	x.VersionBits = uint32(l.Version)
+	x.EntityBits = uint32(l.Entity)
+	x.RegionBits = uint32(l.Region)
+	x.DataCenterBits = uint32(l.DataCenter)
+	x.MachineBits = uint32(l.Machine)
+	x.SequenceBits = uint32(l.Sequence)
+	x.BootBits = uint32(l.Boot)
+	for _, p := range l.Order {
+		x.Order = append(x.Order, uint32(p))
+	}
+	return x
+}
+
+// ToID 获取 id，value 为 0 时使用 text，两者都有值但是不一致时返回 ErrInvalidID
+func (x *SnowflakeID) ToID() (int64, error) {
+	if x.GetText() == "" {
+		return x.GetValue(), nil
+	}
+	var id, err = strconv.ParseInt(x.GetText(), 10, 64)
+	if err != nil || id < 0 || (x.GetValue() != 0 && x.GetValue() != id) {
+		return 0, ErrInvalidID
+	}
+	return id, nil
+}
+
+// Decode 按照消息中的布局解析 id，没有布局信息时使用默认布局和 1970-01-01 的时间偏移量
+func (x *SnowflakeID) Decode() (snowflake.Parts, error) {
+	var id, err = x.ToID()
+	if err != nil {
+		return snowflake.Parts{}, err
+	}
+	if x.GetLayout() == nil {
+		return snowflake.DefaultLayout.Decode(time.Time{}, id), nil
+	}
+	var l, epoch = x.GetLayout().ToLayout()
+	return l.Decode(epoch, id), nil
+}
+
+// ToLayout 将 Layout 转换为 snowflake.Layout 和时间偏移量
+func (x *Layout) ToLayout() (snowflake.Layout, time.Time) {
+	var l snowflake.Layout
+	l.Shard = uint8(x.GetShardBits())
+	l.Version = uint8(x.GetVersionBits())
+	l.Entity = uint8(x.GetEntityBits())
+	l.Region = uint8(x.GetRegionBits())
+	l.DataCenter = uint8(x.GetDataCenterBits())
+	l.Machine = uint8(x.GetMachineBits())
+	l.Sequence = uint8(x.GetSequenceBits())
+	l.Boot = uint8(x.GetBootBits())
+	for _, p := range x.GetOrder() {
+		l.Order = append(l.Order, snowflake.Part(p))
+	}
+	var mill = x.GetEpochMs()
+	return l, time.Unix(mill/1e3, (mill%1e3)*1e6)
+}
@@ -0,0 +1,65 @@
+package sfproto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFromGenerator(t *testing.T) {
+	var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var s, err = snowflake.New(snowflake.WithTimeOffset(epoch), snowflake.WithShardBits(2), snowflake.WithRegionBits(1), snowflake.WithRegion(1), snowflake.WithMachine(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var legacy = snowflake.Layout{Machine: 5, Sequence: 12, Order: []snowflake.Part{snowflake.PartTime, snowflake.PartSequence, snowflake.PartMachine}}
+	l, err := snowflake.New(snowflake.WithLayout(legacy), snowflake.WithMachine(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, g := range []*snowflake.SnowFlake{s, l} {
+		var id = g.Next()
+		data, err := proto.Marshal(FromGenerator(g, id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var x = &SnowflakeID{}
+		if err = proto.Unmarshal(data, x); err != nil {
+			t.Fatal(err)
+		}
+
+		p, err := x.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p != g.Decode(id) {
+			t.Fatalf("Decode() = %+v, want %+v", p, g.Decode(id))
+		}
+	}
+}
+
+func TestSnowflakeID_ToID(t *testing.T) {
+	// JSON 客户端只设置 text
+	var x = &SnowflakeID{}
+	if err := protojson.Unmarshal([]byte(`{"text":"146559593487814656"}`), x); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := x.ToID(); err != nil || id != 146559593487814656 {
+		t.Fatalf("ToID() = %d, %v", id, err)
+	}
+	if id, err := (&SnowflakeID{Value: 7}).ToID(); err != nil || id != 7 {
+		t.Fatalf("ToID() = %d, %v", id, err)
+	}
+	if _, err := (&SnowflakeID{Value: 7, Text: "8"}).ToID(); err != ErrInvalidID {
+		t.Fatalf("ToID() error = %v, want %v", err, ErrInvalidID)
+	}
+
+	var id = int64(146559593487814656)
+	if p, _ := FromID(id).Decode(); p != snowflake.DefaultLayout.Decode(time.Time{}, id) {
+		t.Fatalf("Decode() = %+v", p)
+	}
+}
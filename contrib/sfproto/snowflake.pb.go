@@ -0,0 +1,278 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: snowflake.proto
+
+package sfproto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SnowflakeID snowflake id，value 和 text 表示同一个 id，text 用于 JSON 等无法准确表示 64 位整数的场景
+type SnowflakeID struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Value int64                  `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	Text  string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	// 生成 id 使用的布局，为空时表示默认布局和 1970-01-01 的时间偏移量
+	Layout        *Layout `protobuf:"bytes,3,opt,name=layout,proto3" json:"layout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnowflakeID) Reset() {
+	*x = SnowflakeID{}
+	mi := &file_snowflake_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnowflakeID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnowflakeID) ProtoMessage() {}
+
+func (x *SnowflakeID) ProtoReflect() protoreflect.Message {
+	mi := &file_snowflake_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnowflakeID.ProtoReflect.Descriptor instead.
+func (*SnowflakeID) Descriptor() ([]byte, []int) {
+	return file_snowflake_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SnowflakeID) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *SnowflakeID) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SnowflakeID) GetLayout() *Layout {
+	if x != nil {
+		return x.Layout
+	}
+	return nil
+}
+
+// Layout id 的布局，与 snowflake.Layout 一致，时间戳占用剩余的位数
+type Layout struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 时间偏移量，单位是毫秒
+	EpochMs        int64  `protobuf:"varint,1,opt,name=epoch_ms,json=epochMs,proto3" json:"epoch_ms,omitempty"`
+	VersionBits    uint32 `protobuf:"varint,2,opt,name=version_bits,json=versionBits,proto3" json:"version_bits,omitempty"`
+	EntityBits     uint32 `protobuf:"varint,3,opt,name=entity_bits,json=entityBits,proto3" json:"entity_bits,omitempty"`
+	RegionBits     uint32 `protobuf:"varint,4,opt,name=region_bits,json=regionBits,proto3" json:"region_bits,omitempty"`
+	DataCenterBits uint32 `protobuf:"varint,5,opt,name=data_center_bits,json=dataCenterBits,proto3" json:"data_center_bits,omitempty"`
+	MachineBits    uint32 `protobuf:"varint,6,opt,name=machine_bits,json=machineBits,proto3" json:"machine_bits,omitempty"`
+	SequenceBits   uint32 `protobuf:"varint,7,opt,name=sequence_bits,json=sequenceBits,proto3" json:"sequence_bits,omitempty"`
+	ShardBits      uint32 `protobuf:"varint,8,opt,name=shard_bits,json=shardBits,proto3" json:"shard_bits,omitempty"`
+	// 从高位到低位排列的组成部分，值与 snowflake.Part 一致，为空时使用默认的顺序
+	Order         []uint32 `protobuf:"varint,9,rep,packed,name=order,proto3" json:"order,omitempty"`
+	BootBits      uint32   `protobuf:"varint,10,opt,name=boot_bits,json=bootBits,proto3" json:"boot_bits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Layout) Reset() {
+	*x = Layout{}
+	mi := &file_snowflake_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Layout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Layout) ProtoMessage() {}
+
+func (x *Layout) ProtoReflect() protoreflect.Message {
+	mi := &file_snowflake_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Layout.ProtoReflect.Descriptor instead.
+func (*Layout) Descriptor() ([]byte, []int) {
+	return file_snowflake_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Layout) GetEpochMs() int64 {
+	if x != nil {
+		return x.EpochMs
+	}
+	return 0
+}
+
+func (x *Layout) GetVersionBits() uint32 {
+	if x != nil {
+		return x.VersionBits
+	}
+	return 0
+}
+
+func (x *Layout) GetEntityBits() uint32 {
+	if x != nil {
+		return x.EntityBits
+	}
+	return 0
+}
+
+func (x *Layout) GetRegionBits() uint32 {
+	if x != nil {
+		return x.RegionBits
+	}
+	return 0
+}
+
+func (x *Layout) GetDataCenterBits() uint32 {
+	if x != nil {
+		return x.DataCenterBits
+	}
+	return 0
+}
+
+func (x *Layout) GetMachineBits() uint32 {
+	if x != nil {
+		return x.MachineBits
+	}
+	return 0
+}
+
+func (x *Layout) GetSequenceBits() uint32 {
+	if x != nil {
+		return x.SequenceBits
+	}
+	return 0
+}
+
+func (x *Layout) GetShardBits() uint32 {
+	if x != nil {
+		return x.ShardBits
+	}
+	return 0
+}
+
+func (x *Layout) GetOrder() []uint32 {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *Layout) GetBootBits() uint32 {
+	if x != nil {
+		return x.BootBits
+	}
+	return 0
+}
+
+var File_snowflake_proto protoreflect.FileDescriptor
+
+const file_snowflake_proto_rawDesc = "" +
+	"\n" +
+	"\x0fsnowflake.proto\x12\fsnowflake.v1\"e\n" +
+	"\vSnowflakeID\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x03R\x05value\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\tR\x04text\x12,\n" +
+	"\x06layout\x18\x03 \x01(\v2\x14.snowflake.v1.LayoutR\x06layout\"\xcc\x02\n" +
+	"\x06Layout\x12\x19\n" +
+	"\bepoch_ms\x18\x01 \x01(\x03R\aepochMs\x12!\n" +
+	"\fversion_bits\x18\x02 \x01(\rR\vversionBits\x12\x1f\n" +
+	"\ventity_bits\x18\x03 \x01(\rR\n" +
+	"entityBits\x12\x1f\n" +
+	"\vregion_bits\x18\x04 \x01(\rR\n" +
+	"regionBits\x12(\n" +
+	"\x10data_center_bits\x18\x05 \x01(\rR\x0edataCenterBits\x12!\n" +
+	"\fmachine_bits\x18\x06 \x01(\rR\vmachineBits\x12#\n" +
+	"\rsequence_bits\x18\a \x01(\rR\fsequenceBits\x12\x1d\n" +
+	"\n" +
+	"shard_bits\x18\b \x01(\rR\tshardBits\x12\x14\n" +
+	"\x05order\x18\t \x03(\rR\x05order\x12\x1b\n" +
+	"\tboot_bits\x18\n" +
+	" \x01(\rR\bbootBitsB9Z7github.com/smartwalle/snowflake/contrib/sfproto;sfprotob\x06proto3"
+
+var (
+	file_snowflake_proto_rawDescOnce sync.Once
+	file_snowflake_proto_rawDescData []byte
+)
+
+func file_snowflake_proto_rawDescGZIP() []byte {
+	file_snowflake_proto_rawDescOnce.Do(func() {
+		file_snowflake_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_snowflake_proto_rawDesc), len(file_snowflake_proto_rawDesc)))
+	})
+	return file_snowflake_proto_rawDescData
+}
+
+var file_snowflake_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_snowflake_proto_goTypes = []any{
+	(*SnowflakeID)(nil), // 0: snowflake.v1.SnowflakeID
+	(*Layout)(nil),      // 1: snowflake.v1.Layout
+}
+var file_snowflake_proto_depIdxs = []int32{
+	1, // 0: snowflake.v1.SnowflakeID.layout:type_name -> snowflake.v1.Layout
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_snowflake_proto_init() }
+func file_snowflake_proto_init() {
+	if File_snowflake_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_snowflake_proto_rawDesc), len(file_snowflake_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_snowflake_proto_goTypes,
+		DependencyIndexes: file_snowflake_proto_depIdxs,
+		MessageInfos:      file_snowflake_proto_msgTypes,
+	}.Build()
+	File_snowflake_proto = out.File
+	file_snowflake_proto_goTypes = nil
+	file_snowflake_proto_depIdxs = nil
+}
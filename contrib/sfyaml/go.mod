@@ -0,0 +1,10 @@
+module github.com/smartwalle/snowflake/contrib/sfyaml
+
+go 1.21
+
+require (
+	github.com/smartwalle/snowflake v0.0.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+replace github.com/smartwalle/snowflake => ../../
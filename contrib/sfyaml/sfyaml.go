@@ -0,0 +1,42 @@
+// Package sfyaml 从 YAML 文件中读取 snowflake.Config，避免 snowflake 依赖 YAML 库。
+package sfyaml
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/smartwalle/snowflake"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadConfig 从 YAML 文件中读取配置，字段名与 snowflake.Config 的 yaml 标签一致：
+//
+//	epoch: 2024-01-01T00:00:00Z
+//	layout: datacenter=5,machine=5,sequence=12
+//	datacenter: 3
+//	machine: 17
+func LoadConfig(path string) (snowflake.Config, error) {
+	var cfg snowflake.Config
+	var data, err = os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err = Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("%w %s: %w", snowflake.ErrInvalidConfig, path, err)
+	}
+	return cfg, nil
+}
+
+// Unmarshal 解析 YAML 格式的配置，可以用于配置嵌套在服务配置中的场景
+func Unmarshal(data []byte, cfg *snowflake.Config) error {
+	return yaml.Unmarshal(data, cfg)
+}
+
+// New 使用 YAML 文件中的配置创建生成器
+func New(path string, opts ...snowflake.Option) (*snowflake.SnowFlake, error) {
+	var cfg, err = LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return snowflake.NewWithConfig(cfg, opts...)
+}
@@ -0,0 +1,38 @@
+package sfyaml
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/smartwalle/snowflake"
+)
+
+func TestNew(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "snowflake.yaml")
+	var data = `
+epoch: "2024-01-01T00:00:00Z"
+layout: datacenter=4,machine=6,sequence=12
+datacenter: 9
+machine: 33
+rollback_policy: panic
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var s, err = New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p = s.Decode(s.Next())
+	if p.DataCenter != 9 || p.Machine != 33 || s.Layout().Machine != 6 {
+		t.Fatalf("Decode() = %+v", p)
+	}
+
+	os.WriteFile(path, []byte("machine: [1]"), 0644)
+	if _, err = New(path); !errors.Is(err, snowflake.ErrInvalidConfig) {
+		t.Fatalf("New() error = %v, want %v", err, snowflake.ErrInvalidConfig)
+	}
+}
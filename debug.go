@@ -0,0 +1,106 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// DebugInfo 生成器的诊断信息，通过 DebugHandler 以 JSON 的格式输出
+type DebugInfo struct {
+	Epoch       time.Time `json:"epoch"`
+	EpochEnd    time.Time `json:"epoch_end"`
+	Layout      Layout    `json:"layout"`
+	Version     int64     `json:"version"`
+	Region      int64     `json:"region"`
+	DataCenter  int64     `json:"data_center"`
+	Machine     int64     `json:"machine"`
+	Boot        int64     `json:"boot"`
+	ProcessSlot int64     `json:"process_slot"`
+	Tolerance   int64     `json:"tolerance"` // 可以容忍的时钟回拨（毫秒）
+
+	Watermark   time.Time `json:"watermark"` // 上一次生成 id 使用的时间
+	Sequence    int64     `json:"sequence"`  // 上一次生成 id 使用的序列号
+	SequenceMax int64     `json:"sequence_max"`
+	Utilization float64   `json:"utilization"` // 上一次生成 id 的毫秒内已经使用的序列号的比例
+
+	Stats           Stats                  `json:"stats"`
+	RecentRollbacks []RollbackEvent        `json:"recent_rollbacks"`
+	Sections        map[string]interface{} `json:"sections,omitempty"` // 通过 DebugSection 添加的信息
+}
+
+// DebugSection DebugHandler 输出的额外信息，如 LeaseClient 的租约状态
+type DebugSection struct {
+	Name  string
+	Value func() interface{}
+}
+
+// Debug 获取生成器的诊断信息
+func (this *SnowFlake) Debug() DebugInfo {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	return this.debugLocked()
+}
+
+// debugLocked 获取生成器的诊断信息，调用方需要持有 mu
+func (this *SnowFlake) debugLocked() DebugInfo {
+	var info = DebugInfo{}
+	info.Epoch = millisecondToTime(this.timeOffset)
+	info.EpochEnd = this.EpochEnd()
+	info.Layout = this.layout
+	info.Layout.Order = append([]Part(nil), this.layout.Order...)
+	info.Version = this.version
+	info.Region = this.region
+	info.DataCenter = this.dataCenter
+	info.Machine = this.machine
+	info.Boot = this.boot
+	info.ProcessSlot = this.process
+	info.Tolerance = this.tolerance
+	if this.millisecond > 0 {
+		info.Watermark = millisecondToTime(this.millisecond)
+		info.Sequence = this.sequence
+		info.Utilization = float64(this.sequenceUsed()) / float64(this.bits.sequence.max+1)
+	}
+	info.SequenceMax = this.bits.sequence.max
+	info.Stats = this.stats
+	info.RecentRollbacks = append([]RollbackEvent{}, this.rollbacks...)
+	return info
+}
+
+// LastTimestamp 获取上一次生成 id 使用的时间，还没有生成过 id 时返回零值
+func (this *SnowFlake) LastTimestamp() time.Time {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	if this.millisecond <= 0 {
+		return time.Time{}
+	}
+	return millisecondToTime(this.millisecond)
+}
+
+// CurrentSequence 获取上一次生成 id 使用的序列号
+func (this *SnowFlake) CurrentSequence() int64 {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	return this.sequence
+}
+
+// DebugHandler 以 JSON 的格式输出生成器的诊断信息，作用与 /debug/vars 类似，用于线上排查问题，如：
+//
+//	http.Handle("/debug/snowflake", snowflake.DebugHandler(s, snowflake.DebugSection{Name: "lease", Value: func() interface{} { return c.Status() }}))
+//
+// 诊断信息包含生成器的配置，需要与业务接口分开部署或者限制访问。
+func DebugHandler(s *SnowFlake, sections ...DebugSection) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var info = s.Debug()
+		if len(sections) > 0 {
+			info.Sections = make(map[string]interface{}, len(sections))
+			for _, section := range sections {
+				info.Sections[section.Name] = section.Value()
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		var encoder = json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(info)
+	})
+}
@@ -0,0 +1,59 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDebugHandler(t *testing.T) {
+	var s, _ = New(WithTimeOffset(testEpoch), WithDataCenter(3), WithMachine(5))
+	var now = s.anchor
+	s.clock = func() time.Time { return now }
+	for i := 0; i < 1024; i++ {
+		s.Next()
+	}
+	now = now.Add(-10 * time.Millisecond)
+	s.Next()
+
+	var rec = httptest.NewRecorder()
+	DebugHandler(s, DebugSection{Name: "lease", Value: func() interface{} { return map[string]int{"blocks": 2} }}).
+		ServeHTTP(rec, httptest.NewRequest("GET", "/debug/snowflake", nil))
+
+	var info DebugInfo
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatal(err)
+	}
+	if info.DataCenter != 3 || info.Machine != 5 || !info.Epoch.Equal(testEpoch) || !info.EpochEnd.Equal(s.EpochEnd()) {
+		t.Fatalf("DebugInfo = %+v", info)
+	}
+	if info.Stats.Generated != 1025 || info.Sequence != 1024 || info.Utilization != 1025.0/4096 {
+		t.Fatalf("DebugInfo = %+v", info)
+	}
+	if !info.Watermark.Equal(s.anchor.Truncate(time.Millisecond)) {
+		t.Fatalf("Watermark = %v, want %v", info.Watermark, s.anchor.Truncate(time.Millisecond))
+	}
+	if len(info.RecentRollbacks) != 1 || info.RecentRollbacks[0].Magnitude != 10*time.Millisecond {
+		t.Fatalf("RecentRollbacks = %+v", info.RecentRollbacks)
+	}
+	if lease, ok := info.Sections["lease"].(map[string]interface{}); !ok || lease["blocks"] != float64(2) {
+		t.Fatalf("Sections = %+v", info.Sections)
+	}
+}
+
+func TestSnowFlake_LastTimestamp(t *testing.T) {
+	var s, _ = New(WithTimeOffset(testEpoch))
+	if !s.LastTimestamp().IsZero() || s.CurrentSequence() != 0 {
+		t.Fatalf("LastTimestamp() = %v, CurrentSequence() = %d", s.LastTimestamp(), s.CurrentSequence())
+	}
+	var now = time.Now().Add(time.Millisecond)
+	s.clock = func() time.Time { return now }
+	var id int64
+	for i := 0; i < 3; i++ {
+		id = s.Next()
+	}
+	if !s.LastTimestamp().Equal(s.TimeOf(id)) || s.CurrentSequence() != 2 {
+		t.Fatalf("LastTimestamp() = %v, CurrentSequence() = %d, want %v and 2", s.LastTimestamp(), s.CurrentSequence(), s.TimeOf(id))
+	}
+}
@@ -0,0 +1,107 @@
+package snowflake
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("Retry", func(t *testing.T) {
+		ResetDefault(t)
+		if err := Init(WithMachine(64)); !errors.Is(err, ErrWorkerNotAllowed) {
+			t.Fatalf("Init() error = %v, want %v", err, ErrWorkerNotAllowed)
+		}
+		// 失败之后可以再次调用
+		if err := Init(WithMachine(7)); err != nil {
+			t.Fatal(err)
+		}
+		if err := Init(WithMachine(8)); err != ErrAlreadyInitialized {
+			t.Fatalf("Init() error = %v, want %v", err, ErrAlreadyInitialized)
+		}
+		if p := Default().Decode(Next()); p.Machine != 7 {
+			t.Fatalf("Decode(Next()) = %+v, want machine 7", p)
+		}
+	})
+
+	t.Run("AfterNext", func(t *testing.T) {
+		ResetDefault(t)
+		// 已经使用了默认生成器之后不能再初始化
+		Next()
+		if err := Init(WithMachine(7)); err != ErrAlreadyInitialized {
+			t.Fatalf("Init() error = %v, want %v", err, ErrAlreadyInitialized)
+		}
+	})
+}
+
+func TestInit_AfterDecode(t *testing.T) {
+	ResetDefault(t)
+	var saved = atomic.LoadInt32(&requireInit)
+	atomic.StoreInt32(&requireInit, 1)
+	defer atomic.StoreInt32(&requireInit, saved)
+
+	// 解析 id 不会创建默认生成器，RequireInit 时也不会 panic
+	var s, _ = New()
+	var id = s.Next()
+	if !TimeOf(id).Equal(s.TimeOf(id)) || Explain(id) != s.Explain(id) || !Between(id, s.TimeOf(id), s.TimeOf(id).Add(time.Millisecond)) {
+		t.Fatalf("TimeOf(%d) = %v, want %v", id, TimeOf(id), s.TimeOf(id))
+	}
+	FormatTime(id, time.UTC, time.RFC3339)
+	ObjectIDOf(id)
+	PartitionKeyByMachine(id, 8)
+	PartitionKeyByTime(id, 8, time.Hour)
+	if err := Init(WithMachine(7), WithTimeOffset(testEpoch)); err != nil {
+		t.Fatalf("Init() after decoding error = %v", err)
+	}
+	// 初始化之后使用默认生成器的时间偏移量
+	var next = Next()
+	if p := Default().Decode(next); p.Machine != 7 || time.Since(TimeOf(next)) > time.Second {
+		t.Fatalf("TimeOf(%d) = %v, %+v", next, TimeOf(next), p)
+	}
+}
+
+func TestInit_Concurrent(t *testing.T) {
+	ResetDefault(t)
+	var wg sync.WaitGroup
+	var succeeded int32
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if Init(WithMachine(int64(i))) == nil {
+				atomic.AddInt32(&succeeded, 1)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if succeeded != 1 {
+		t.Fatalf("%d calls of Init succeeded, want 1", succeeded)
+	}
+}
+
+func TestMustInit(t *testing.T) {
+	ResetDefault(t)
+	MustInit(WithMachine(3))
+	defer func() {
+		if r := recover(); r != ErrAlreadyInitialized {
+			t.Fatalf("MustInit() panic = %v, want %v", r, ErrAlreadyInitialized)
+		}
+	}()
+	MustInit(WithMachine(3))
+}
+
+func TestResetDefault(t *testing.T) {
+	var before = Default()
+	t.Run("Reset", func(t *testing.T) {
+		ResetDefault(t)
+		MustInit(WithMachine(9))
+		if Default() == before || Default().MachineID() != 9 {
+			t.Fatalf("Default() is not replaced after ResetDefault")
+		}
+	})
+	if Default() != before {
+		t.Fatalf("Default() is not restored after the test")
+	}
+}
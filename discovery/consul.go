@@ -0,0 +1,177 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	kDefaultCheckInterval   = 10 * time.Second
+	kDefaultCheckTimeout    = 2 * time.Second
+	kDefaultDeregisterAfter = time.Minute
+
+	kMetaDataCenter = "snowflake_datacenter"
+	kMetaMachine    = "snowflake_machine"
+)
+
+type ConsulOption func(c *Consul)
+
+// WithConsulToken 设置访问 Consul 使用的 ACL token
+func WithConsulToken(token string) ConsulOption {
+	return func(c *Consul) {
+		c.token = token
+	}
+}
+
+// WithConsulHTTPClient 设置访问 Consul 使用的 http.Client
+func WithConsulHTTPClient(client *http.Client) ConsulOption {
+	return func(c *Consul) {
+		if client != nil {
+			c.client = client
+		}
+	}
+}
+
+// WithCheckInterval 设置 Consul 对 id 服务进行健康检查（GET /health）的间隔，默认为 10 秒
+func WithCheckInterval(d time.Duration) ConsulOption {
+	return func(c *Consul) {
+		if d > 0 {
+			c.checkInterval = d
+		}
+	}
+}
+
+// WithDeregisterAfter 设置健康检查失败多久之后由 Consul 自动注销实例，默认为 1 分钟，用于清理没有正常注销就退出的实例
+func WithDeregisterAfter(d time.Duration) ConsulOption {
+	return func(c *Consul) {
+		if d > 0 {
+			c.deregisterAfter = d
+		}
+	}
+}
+
+// Consul 基于 Consul agent HTTP API 的 Registry 和 Resolver，不依赖 Consul 的 sdk。
+//
+// 实例的数据中心标识和机器标识保存在服务的 Meta 中，Consul 通过 id 服务的 GET /health 接口进行健康检查，Resolve 只返回健康检查通过的实例。
+type Consul struct {
+	address         string
+	token           string
+	client          *http.Client
+	checkInterval   time.Duration
+	deregisterAfter time.Duration
+}
+
+// NewConsul 创建 Consul，address 为 Consul agent 的地址，如：http://127.0.0.1:8500
+func NewConsul(address string, opts ...ConsulOption) *Consul {
+	var c = &Consul{}
+	c.address = strings.TrimRight(address, "/")
+	c.client = &http.Client{Timeout: 5 * time.Second}
+	c.checkInterval = kDefaultCheckInterval
+	c.deregisterAfter = kDefaultDeregisterAfter
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type consulCheck struct {
+	HTTP                           string `json:"HTTP"`
+	Interval                       string `json:"Interval"`
+	Timeout                        string `json:"Timeout"`
+	DeregisterCriticalServiceAfter string `json:"DeregisterCriticalServiceAfter"`
+}
+
+type consulService struct {
+	ID      string            `json:"ID"`
+	Service string            `json:"Service,omitempty"`
+	Name    string            `json:"Name,omitempty"`
+	Address string            `json:"Address"`
+	Port    int               `json:"Port"`
+	Meta    map[string]string `json:"Meta"`
+	Check   *consulCheck      `json:"Check,omitempty"`
+}
+
+// Register 注册实例，同一个实例标识重复注册时会覆盖之前的信息
+func (this *Consul) Register(ctx context.Context, instance Instance) error {
+	var service = consulService{}
+	service.ID = instance.ID
+	service.Name = instance.Name
+	service.Address = instance.Address
+	service.Port = instance.Port
+	service.Meta = map[string]string{
+		kMetaDataCenter: strconv.FormatInt(instance.DataCenter, 10),
+		kMetaMachine:    strconv.FormatInt(instance.Machine, 10),
+	}
+	service.Check = &consulCheck{
+		HTTP:                           instance.Endpoint() + "/health",
+		Interval:                       this.checkInterval.String(),
+		Timeout:                        kDefaultCheckTimeout.String(),
+		DeregisterCriticalServiceAfter: this.deregisterAfter.String(),
+	}
+
+	var body, err = json.Marshal(service)
+	if err != nil {
+		return err
+	}
+	return this.do(ctx, http.MethodPut, "/v1/agent/service/register", body, nil)
+}
+
+// Deregister 注销实例
+func (this *Consul) Deregister(ctx context.Context, instance Instance) error {
+	return this.do(ctx, http.MethodPut, "/v1/agent/service/deregister/"+url.PathEscape(instance.ID), nil, nil)
+}
+
+// Resolve 获取指定服务下所有健康检查通过的实例
+func (this *Consul) Resolve(ctx context.Context, name string) ([]Instance, error) {
+	var entries []struct {
+		Service consulService `json:"Service"`
+	}
+	if err := this.do(ctx, http.MethodGet, "/v1/health/service/"+url.PathEscape(name)+"?passing=true", nil, &entries); err != nil {
+		return nil, err
+	}
+
+	var instances = make([]Instance, 0, len(entries))
+	for _, entry := range entries {
+		var instance = Instance{}
+		instance.ID = entry.Service.ID
+		instance.Name = entry.Service.Service
+		instance.Address = entry.Service.Address
+		instance.Port = entry.Service.Port
+		instance.DataCenter, _ = strconv.ParseInt(entry.Service.Meta[kMetaDataCenter], 10, 64)
+		instance.Machine, _ = strconv.ParseInt(entry.Service.Meta[kMetaMachine], 10, 64)
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+func (this *Consul) do(ctx context.Context, method, path string, body []byte, out interface{}) error {
+	var req, err = http.NewRequestWithContext(ctx, method, this.address+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if this.token != "" {
+		req.Header.Set("X-Consul-Token", this.token)
+	}
+	rsp, err := this.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode != http.StatusOK {
+		var msg, _ = io.ReadAll(io.LimitReader(rsp.Body, 512))
+		return fmt.Errorf("snowflake: consul %s %s failed with status %d: %s", method, path, rsp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(rsp.Body).Decode(out)
+}
@@ -0,0 +1,98 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/smartwalle/snowflake"
+)
+
+// fakeConsul 模拟 Consul agent 的服务注册和健康查询接口
+type fakeConsul struct {
+	mu       sync.Mutex
+	services map[string]consulService
+	token    string
+}
+
+func (this *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	this.token = r.Header.Get("X-Consul-Token")
+	switch {
+	case r.Method == http.MethodPut && r.URL.Path == "/v1/agent/service/register":
+		var service consulService
+		if err := json.NewDecoder(r.Body).Decode(&service); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		service.Service = service.Name
+		this.services[service.ID] = service
+	case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/v1/agent/service/deregister/"):
+		delete(this.services, strings.TrimPrefix(r.URL.Path, "/v1/agent/service/deregister/"))
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/v1/health/service/"):
+		var name = strings.TrimPrefix(r.URL.Path, "/v1/health/service/")
+		var entries []map[string]consulService
+		for _, service := range this.services {
+			if service.Service == name {
+				entries = append(entries, map[string]consulService{"Service": service})
+			}
+		}
+		json.NewEncoder(w).Encode(entries)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestConsul(t *testing.T) {
+	var fake = &fakeConsul{services: make(map[string]consulService)}
+	var srv = httptest.NewServer(fake)
+	defer srv.Close()
+
+	var generator, _ = snowflake.New(snowflake.WithDataCenter(3), snowflake.WithMachine(7))
+	var instance = NewInstance("snowflaked", "10.0.0.1", 8080, generator)
+	if instance.ID != "snowflaked-3-7" || instance.Endpoint() != "http://10.0.0.1:8080" {
+		t.Fatalf("NewInstance() = %+v", instance)
+	}
+
+	var ctx = context.Background()
+	var consul = NewConsul(srv.URL, WithConsulToken("secret"))
+	if err := consul.Register(ctx, instance); err != nil {
+		t.Fatal(err)
+	}
+	if fake.token != "secret" {
+		t.Fatalf("consul received token %q, want %q", fake.token, "secret")
+	}
+	if check := fake.services[instance.ID].Check; check == nil || check.HTTP != "http://10.0.0.1:8080/health" {
+		t.Fatalf("registered check = %+v", check)
+	}
+
+	var instances, err = consul.Resolve(ctx, "snowflaked")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(instances, []Instance{instance}) {
+		t.Fatalf("Resolve() = %+v, want %+v", instances, []Instance{instance})
+	}
+	endpoints, err := Endpoints(ctx, consul, "snowflaked")
+	if err != nil || !reflect.DeepEqual(endpoints, []string{"http://10.0.0.1:8080"}) {
+		t.Fatalf("Endpoints() = %v, %v", endpoints, err)
+	}
+
+	if err = consul.Deregister(ctx, instance); err != nil {
+		t.Fatal(err)
+	}
+	if instances, _ = consul.Resolve(ctx, "snowflaked"); len(instances) != 0 {
+		t.Fatalf("Resolve() after Deregister = %+v", instances)
+	}
+
+	if err = NewConsul(srv.URL+"/missing").Register(ctx, instance); err == nil {
+		t.Fatal("Register() should fail when consul returns 404")
+	}
+}
@@ -0,0 +1,65 @@
+// Package discovery 用于 id 服务的注册与发现，id 服务启动时将自己注册到注册中心，客户端从注册中心获取健康的 id 服务地址。
+package discovery
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	"github.com/smartwalle/snowflake"
+)
+
+// Instance 注册到注册中心的一个 id 服务实例
+type Instance struct {
+	ID         string // 实例标识，同一个服务下唯一
+	Name       string // 服务名称
+	Address    string // 地址，如：10.0.0.1
+	Port       int    // 端口
+	DataCenter int64  // 生成器使用的数据中心标识
+	Machine    int64  // 生成器使用的机器标识
+}
+
+// NewInstance 根据生成器的数据中心标识和机器标识创建 Instance，实例标识为 name-数据中心标识-机器标识
+func NewInstance(name, address string, port int, generator *snowflake.SnowFlake) Instance {
+	var i = Instance{}
+	i.Name = name
+	i.Address = address
+	i.Port = port
+	i.DataCenter = generator.DataCenterID()
+	i.Machine = generator.MachineID()
+	i.ID = name + "-" + strconv.FormatInt(i.DataCenter, 10) + "-" + strconv.FormatInt(i.Machine, 10)
+	return i
+}
+
+// Endpoint 获取实例的 http 地址，如：http://10.0.0.1:8080，可以直接用于 client.NewClient
+func (i Instance) Endpoint() string {
+	return "http://" + net.JoinHostPort(i.Address, strconv.Itoa(i.Port))
+}
+
+// Registry 注册中心
+type Registry interface {
+	// Register 注册实例
+	Register(ctx context.Context, instance Instance) error
+
+	// Deregister 注销实例
+	Deregister(ctx context.Context, instance Instance) error
+}
+
+// Resolver 获取健康的 id 服务实例
+type Resolver interface {
+	// Resolve 获取指定服务下所有健康的实例
+	Resolve(ctx context.Context, name string) ([]Instance, error)
+}
+
+// Endpoints 获取指定服务下所有健康实例的 http 地址
+func Endpoints(ctx context.Context, resolver Resolver, name string) ([]string, error) {
+	var instances, err = resolver.Resolve(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	var endpoints = make([]string, 0, len(instances))
+	for _, instance := range instances {
+		endpoints = append(endpoints, instance.Endpoint())
+	}
+	return endpoints, nil
+}
@@ -0,0 +1,105 @@
+package snowflake
+
+import (
+	"sync"
+	"time"
+)
+
+// DriftDetector 定时比较系统时间与单调时钟经过的时长，用于发现系统时间被调整（如 NTP 校时、手动修改时间）。
+//
+// 生成器使用单调时钟计算时间戳，不受系统时间调整的影响，但是系统时间与生成器的时间戳偏差过大时，
+// 重启之后生成的 id 可能与之前的 id 重复，需要在偏差变大之前发现。
+type DriftDetector struct {
+	mu        sync.Mutex
+	interval  time.Duration
+	threshold time.Duration
+	onStep    func(step, drift time.Duration)
+
+	wall      func() int64         // 系统时间（纳秒）
+	mono      func() time.Duration // 单调时钟经过的时长
+	startWall int64
+	drift     time.Duration // 系统时间相对于单调时钟的偏差，为正数时表示系统时间走快了
+	steps     int64
+	stop      chan struct{}
+}
+
+// NewDriftDetector 创建 DriftDetector，interval 为检查的间隔，两次检查之间偏差的变化超过 threshold 时认为系统时间被调整，
+// 会调用 onStep，step 为本次调整的时长，drift 为累计的偏差，onStep 可以为 nil
+func NewDriftDetector(interval, threshold time.Duration, onStep func(step, drift time.Duration)) *DriftDetector {
+	var base = time.Now()
+	var d = &DriftDetector{}
+	d.interval = interval
+	d.threshold = threshold
+	d.onStep = onStep
+	d.wall = func() int64 { return time.Now().UnixNano() }
+	d.mono = func() time.Duration { return time.Since(base) }
+	d.startWall = d.wall()
+	return d
+}
+
+// Start 在后台定时检查，重复调用不会启动多个检查
+func (this *DriftDetector) Start() {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	if this.stop != nil {
+		return
+	}
+	this.stop = make(chan struct{})
+	go this.run(this.stop)
+}
+
+// Stop 停止后台的检查
+func (this *DriftDetector) Stop() {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	if this.stop != nil {
+		close(this.stop)
+		this.stop = nil
+	}
+}
+
+func (this *DriftDetector) run(stop chan struct{}) {
+	var ticker = time.NewTicker(this.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			this.Check()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Check 立即检查一次，返回当前的偏差
+func (this *DriftDetector) Check() time.Duration {
+	this.mu.Lock()
+	var drift = time.Duration(this.wall()-this.startWall) - this.mono()
+	var step = drift - this.drift
+	this.drift = drift
+	var stepped = step > this.threshold || -step > this.threshold
+	if stepped {
+		this.steps++
+	}
+	var onStep = this.onStep
+	this.mu.Unlock()
+
+	if stepped && onStep != nil {
+		onStep(step, drift)
+	}
+	return drift
+}
+
+// Drift 获取最近一次检查得到的偏差
+func (this *DriftDetector) Drift() time.Duration {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	return this.drift
+}
+
+// Steps 获取检测到系统时间被调整的次数
+func (this *DriftDetector) Steps() int64 {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	return this.steps
+}
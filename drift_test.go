@@ -0,0 +1,44 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDriftDetector(t *testing.T) {
+	var steps []time.Duration
+	var d = NewDriftDetector(time.Second, 100*time.Millisecond, func(step, drift time.Duration) {
+		steps = append(steps, step)
+	})
+	var wall, mono = int64(0), time.Duration(0)
+	d.wall = func() int64 { return wall }
+	d.mono = func() time.Duration { return mono }
+	d.startWall = 0
+
+	// 系统时间与单调时钟同步前进，轻微的偏差不认为是调整
+	wall, mono = int64(time.Second+10*time.Millisecond), time.Second
+	if drift := d.Check(); drift != 10*time.Millisecond || len(steps) != 0 {
+		t.Fatalf("Check() = %v, steps = %v", drift, steps)
+	}
+
+	// 系统时间被回拨了 2 秒
+	wall, mono = int64(10*time.Millisecond), 2*time.Second
+	if drift := d.Check(); drift != -1990*time.Millisecond || d.Drift() != drift {
+		t.Fatalf("Check() = %v, Drift() = %v", drift, d.Drift())
+	}
+	if len(steps) != 1 || steps[0] != -2*time.Second || d.Steps() != 1 {
+		t.Fatalf("steps = %v, Steps() = %d", steps, d.Steps())
+	}
+}
+
+func TestDriftDetector_Start(t *testing.T) {
+	var d = NewDriftDetector(time.Millisecond, time.Hour, nil)
+	d.Start()
+	d.Start()
+	time.Sleep(10 * time.Millisecond)
+	d.Stop()
+	d.Stop()
+	if d.Steps() != 0 {
+		t.Fatalf("Steps() = %d, want 0", d.Steps())
+	}
+}
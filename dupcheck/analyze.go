@@ -0,0 +1,166 @@
+package dupcheck
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+)
+
+var (
+	ErrColumnNotFound = errors.New("dupcheck: column not found in the csv header")
+)
+
+const (
+	kMaxReportedDuplicates = 1000
+	kDefaultClockSkew      = time.Minute
+)
+
+// Report Analyze 的结果
+type Report struct {
+	Total            int64            `json:"total"`             // 读取的 id 数量，不包含无法解析的值
+	Unparsable       int64            `json:"unparsable"`        // 无法解析为 id 的值
+	Duplicates       int64            `json:"duplicates"`        // 确定重复的 id 数量
+	MaybeDuplicates  int64            `json:"maybe_duplicates"`  // 可能重复的 id 数量，需要精确核对
+	FutureTimestamps int64            `json:"future_timestamps"` // 时间戳晚于当前时间的 id 数量
+	InvalidWorkers   int64            `json:"invalid_workers"`   // 工作节点标识不在 WithKnownWorkers 范围内的 id 数量
+	DuplicateIDs     []int64          `json:"duplicate_ids"`     // 确定重复的 id，最多记录 1000 个
+	Workers          map[string]int64 `json:"workers"`           // 每个工作节点标识（数据中心标识:机器标识）的 id 数量
+	Days             map[string]int64 `json:"days"`              // 每天（UTC）的 id 数量
+}
+
+type AnalyzeOption func(a *analyzer)
+
+// WithColumn 设置 id 所在的列名，设置之后第一行作为表头，默认使用第一列，第一行无法解析时作为表头跳过
+func WithColumn(name string) AnalyzeOption {
+	return func(a *analyzer) {
+		a.column = name
+	}
+}
+
+// WithKnownWorkers 设置合法的工作节点标识，不在范围内的 id 计入 InvalidWorkers，如合并数据库后检查是否混入了其它系统生成的 id
+func WithKnownWorkers(known func(dataCenter, machine int64) bool) AnalyzeOption {
+	return func(a *analyzer) {
+		a.known = known
+	}
+}
+
+// WithClockSkew 设置判断时间戳晚于当前时间时允许的误差，默认为 1 分钟
+func WithClockSkew(d time.Duration) AnalyzeOption {
+	return func(a *analyzer) {
+		if d >= 0 {
+			a.skew = d
+		}
+	}
+}
+
+// WithChecker 设置检测重复使用的 Checker，默认为 New(10000000, 0.001, 1<<20)，数据量较大时需要根据数量创建
+func WithChecker(checker *Checker) AnalyzeOption {
+	return func(a *analyzer) {
+		if checker != nil {
+			a.checker = checker
+		}
+	}
+}
+
+type analyzer struct {
+	column  string
+	known   func(dataCenter, machine int64) bool
+	skew    time.Duration
+	checker *Checker
+}
+
+// Analyze 检查导出的 id，r 为 CSV 格式，每行一个 id 的文本同样可以读取，generator 用于按照布局和时间偏移量解析 id。
+//
+// 除了检测重复的 id 以外，还会统计时间戳在未来、工作节点标识不合法的 id，以及每个工作节点标识和每天的 id 数量。
+// Parquet 等格式需要先转换为 CSV，如使用 duckdb：COPY (SELECT id FROM 'export.parquet') TO 'ids.csv'。
+func Analyze(r io.Reader, generator *snowflake.SnowFlake, opts ...AnalyzeOption) (Report, error) {
+	var a = &analyzer{}
+	a.skew = kDefaultClockSkew
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.checker == nil {
+		a.checker = New(10000000, 0.001, 1<<20)
+	}
+
+	var report = Report{}
+	report.Workers = make(map[string]int64)
+	report.Days = make(map[string]int64)
+
+	var reader = csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.ReuseRecord = true
+
+	var now = time.Now().Add(a.skew)
+	var index = 0
+	for row := 0; ; row++ {
+		var record, err = reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+
+		if row == 0 && a.column != "" {
+			if index = columnIndex(record, a.column); index < 0 {
+				return report, fmt.Errorf("%w: %s", ErrColumnNotFound, a.column)
+			}
+			continue
+		}
+		if index >= len(record) {
+			report.Unparsable++
+			continue
+		}
+		var value = strings.TrimSpace(record[index])
+		if value == "" {
+			continue
+		}
+		var id int64
+		if id, err = strconv.ParseInt(value, 10, 64); err != nil || id < 0 {
+			if row == 0 {
+				// 没有设置列名时，第一行无法解析的值作为表头
+				continue
+			}
+			report.Unparsable++
+			continue
+		}
+
+		report.Total++
+		switch a.checker.Check(id) {
+		case Duplicate:
+			report.Duplicates++
+			if len(report.DuplicateIDs) < kMaxReportedDuplicates {
+				report.DuplicateIDs = append(report.DuplicateIDs, id)
+			}
+		case MaybeDuplicate:
+			report.MaybeDuplicates++
+		}
+
+		var p = generator.Decode(id)
+		if p.Time.After(now) {
+			report.FutureTimestamps++
+		}
+		if a.known != nil && !a.known(p.DataCenter, p.Machine) {
+			report.InvalidWorkers++
+		}
+		report.Workers[strconv.FormatInt(p.DataCenter, 10)+":"+strconv.FormatInt(p.Machine, 10)]++
+		report.Days[p.Time.UTC().Format("2006-01-02")]++
+	}
+	return report, nil
+}
+
+func columnIndex(header []string, name string) int {
+	for i, column := range header {
+		if strings.TrimSpace(column) == name {
+			return i
+		}
+	}
+	return -1
+}
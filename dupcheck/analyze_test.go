@@ -0,0 +1,62 @@
+package dupcheck
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+)
+
+func TestAnalyze(t *testing.T) {
+	var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var a, _ = snowflake.New(snowflake.WithTimeOffset(epoch), snowflake.WithDataCenter(1), snowflake.WithMachine(1))
+	var b, _ = snowflake.New(snowflake.WithTimeOffset(epoch), snowflake.WithDataCenter(1), snowflake.WithMachine(2))
+
+	var csv strings.Builder
+	csv.WriteString("name,order_id\n")
+	var ids []int64
+	for i := 0; i < 10; i++ {
+		ids = append(ids, a.Next())
+	}
+	for i := 0; i < 5; i++ {
+		ids = append(ids, b.Next())
+	}
+	for i, id := range ids {
+		fmt.Fprintf(&csv, "row%d,%d\n", i, id)
+	}
+	// 重复的 id、无法解析的值和时间在未来的 id
+	fmt.Fprintf(&csv, "dup,%d\n", ids[3])
+	csv.WriteString("bad,abc\n")
+	// 使用早一年的时间偏移量生成的 id，按照 a 的时间偏移量解析时在一年之后
+	var early, _ = snowflake.New(snowflake.WithTimeOffset(epoch.AddDate(-1, 0, 0)), snowflake.WithDataCenter(1), snowflake.WithMachine(1))
+	fmt.Fprintf(&csv, "future,%d\n", early.Next())
+
+	var report, err = Analyze(strings.NewReader(csv.String()), a, WithColumn("order_id"),
+		WithKnownWorkers(func(dataCenter, machine int64) bool { return dataCenter == 1 && machine == 1 }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 17 || report.Duplicates != 1 || report.DuplicateIDs[0] != ids[3] || report.Unparsable != 1 {
+		t.Fatalf("Analyze() = %+v", report)
+	}
+	if report.FutureTimestamps != 1 || report.InvalidWorkers != 5 {
+		t.Fatalf("Analyze() = %+v", report)
+	}
+	if report.Workers["1:1"] != 12 || report.Workers["1:2"] != 5 {
+		t.Fatalf("Workers = %v", report.Workers)
+	}
+	if report.Days[time.Now().UTC().Format("2006-01-02")] != 16 {
+		t.Fatalf("Days = %v", report.Days)
+	}
+
+	// 每行一个 id 的文本，第一行无法解析时作为表头
+	if report, err = Analyze(strings.NewReader(fmt.Sprintf("id\n%d\n%d\n", ids[0], ids[0])), a); err != nil || report.Total != 2 || report.Duplicates != 1 {
+		t.Fatalf("Analyze() = %+v, %v", report, err)
+	}
+	if _, err = Analyze(strings.NewReader("a,b\n1,2\n"), a, WithColumn("id")); !errors.Is(err, ErrColumnNotFound) {
+		t.Fatalf("Analyze() error = %v, want %v", err, ErrColumnNotFound)
+	}
+}
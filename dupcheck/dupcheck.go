@@ -0,0 +1,179 @@
+// Package dupcheck 检测重复的 id，用于在测试环境和压测中提前发现生成器配置错误（如多个进程使用了同一个机器标识）导致的重复 id。
+//
+// Checker 使用固定大小的布隆过滤器记录所有检测过的 id，同时精确地记录最近的一部分 id，占用的内存不会随着 id 的数量增长。
+package dupcheck
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/smartwalle/snowflake"
+)
+
+var (
+	ErrDuplicate = errors.New("dupcheck: duplicate id")
+)
+
+// Result 检测的结果
+type Result int
+
+const (
+	Unique         Result = iota // 没有出现过
+	Duplicate                    // 在最近的 id 中出现过，一定是重复的
+	MaybeDuplicate               // 布隆过滤器中出现过，可能是重复的，也可能是误判
+)
+
+// Stats 检测的统计信息
+type Stats struct {
+	Checked        int64 // 检测过的 id 数量
+	Duplicates     int64 // 确定重复的 id 数量
+	MaybeDuplicate int64 // 可能重复的 id 数量
+}
+
+// Checker 重复 id 检测器，可以在多个 goroutine 中同时使用
+type Checker struct {
+	mu     sync.Mutex
+	bits   []uint64
+	m      uint64 // 布隆过滤器的位数
+	k      int    // 哈希函数的数量
+	recent map[int64]struct{}
+	ring   []int64
+	next   int
+	stats  Stats
+}
+
+// New 创建检测器，expected 为预计检测的 id 数量，rate 为布隆过滤器期望的误判率，如 0.001，
+// window 为精确记录的最近的 id 数量，在这个范围内出现的重复 id 不会误判。
+func New(expected int, rate float64, window int) *Checker {
+	if expected < 1 {
+		expected = 1
+	}
+	if rate <= 0 || rate >= 1 {
+		rate = 0.001
+	}
+	if window < 0 {
+		window = 0
+	}
+
+	var m = uint64(math.Ceil(-float64(expected) * math.Log(rate) / (math.Ln2 * math.Ln2)))
+	m = (m + 63) / 64 * 64
+	var k = int(math.Round(float64(m) / float64(expected) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+
+	var c = &Checker{}
+	c.bits = make([]uint64, m/64)
+	c.m = m
+	c.k = k
+	c.recent = make(map[int64]struct{}, window)
+	c.ring = make([]int64, 0, window)
+	return c
+}
+
+// Check 检测 id 是否出现过，并记录该 id
+func (this *Checker) Check(id int64) Result {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	this.stats.Checked++
+	if _, ok := this.recent[id]; ok {
+		this.stats.Duplicates++
+		return Duplicate
+	}
+	this.remember(id)
+
+	// 使用两个哈希值组合出 k 个哈希函数
+	var h1 = mix64(uint64(id))
+	var h2 = mix64(h1) | 1
+	var seen = true
+	for i := 0; i < this.k; i++ {
+		var bit = (h1 + uint64(i)*h2) % this.m
+		if this.bits[bit/64]&(1<<(bit%64)) == 0 {
+			seen = false
+			this.bits[bit/64] |= 1 << (bit % 64)
+		}
+	}
+	if seen {
+		this.stats.MaybeDuplicate++
+		return MaybeDuplicate
+	}
+	return Unique
+}
+
+// remember 将 id 加入最近的 id 中，超出数量时移除最早的 id
+func (this *Checker) remember(id int64) {
+	if cap(this.ring) == 0 {
+		return
+	}
+	if len(this.ring) < cap(this.ring) {
+		this.ring = append(this.ring, id)
+	} else {
+		delete(this.recent, this.ring[this.next])
+		this.ring[this.next] = id
+		this.next = (this.next + 1) % len(this.ring)
+	}
+	this.recent[id] = struct{}{}
+}
+
+// Stats 获取检测的统计信息
+func (this *Checker) Stats() Stats {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	return this.stats
+}
+
+// Scan 检测 r 中的 id，每行一个十进制的 id，忽略空行，返回确定重复的 id
+func (this *Checker) Scan(r io.Reader) ([]int64, error) {
+	var duplicates []int64
+	var scanner = bufio.NewScanner(r)
+	for scanner.Scan() {
+		var line = strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var id, err = strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			return duplicates, err
+		}
+		if this.Check(id) == Duplicate {
+			duplicates = append(duplicates, id)
+		}
+	}
+	return duplicates, scanner.Err()
+}
+
+type generator struct {
+	snowflake.Generator
+	checker *Checker
+}
+
+// Wrap 检测 g 生成的所有 id，生成了确定重复的 id 时返回该 id 和 ErrDuplicate，可能重复的 id 只记录在 Stats 中
+func Wrap(g snowflake.Generator, checker *Checker) snowflake.Generator {
+	return &generator{Generator: g, checker: checker}
+}
+
+func (this *generator) NextID() (int64, error) {
+	var id, err = this.Generator.NextID()
+	if err != nil {
+		return id, err
+	}
+	if this.checker.Check(id) == Duplicate {
+		return id, ErrDuplicate
+	}
+	return id, nil
+}
+
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
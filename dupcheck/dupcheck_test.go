@@ -0,0 +1,64 @@
+package dupcheck
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/smartwalle/snowflake"
+)
+
+func TestChecker_Check(t *testing.T) {
+	var c = New(100000, 0.001, 1000)
+	for id := int64(1); id <= 100000; id++ {
+		c.Check(id * 7919)
+	}
+	var stats = c.Stats()
+	if stats.Duplicates != 0 || stats.MaybeDuplicate > 100000/100 {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+
+	if r := c.Check(100000 * 7919); r != Duplicate {
+		t.Fatalf("Check() = %d, want %d", r, Duplicate)
+	}
+	// 超出精确记录的范围之后由布隆过滤器发现
+	if r := c.Check(7919); r != MaybeDuplicate {
+		t.Fatalf("Check() = %d, want %d", r, MaybeDuplicate)
+	}
+}
+
+func TestChecker_Scan(t *testing.T) {
+	var c = New(100, 0.01, 10)
+	var duplicates, err = c.Scan(strings.NewReader("1\n2\n\n3\n2\n"))
+	if err != nil || len(duplicates) != 1 || duplicates[0] != 2 {
+		t.Fatalf("Scan() = %v, %v", duplicates, err)
+	}
+	if _, err = c.Scan(strings.NewReader("x\n")); err == nil {
+		t.Fatal("Scan() should fail on invalid id")
+	}
+}
+
+// repeating 每个 id 生成两次
+type repeating int64
+
+func (this *repeating) NextID() (int64, error) {
+	return atomic.AddInt64((*int64)(this), 1) / 2, nil
+}
+
+func TestWrap(t *testing.T) {
+	var s, _ = snowflake.New()
+	var g = Wrap(s, New(1000, 0.001, 100))
+	for i := 0; i < 1000; i++ {
+		if _, err := g.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var c repeating
+	g = Wrap(&c, New(1000, 0.001, 100))
+	g.NextID()
+	g.NextID()
+	if _, err := g.NextID(); err != ErrDuplicate {
+		t.Fatalf("NextID() error = %v, want %v", err, ErrDuplicate)
+	}
+}
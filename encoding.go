@@ -0,0 +1,152 @@
+package snowflake
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	kBase62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	kPaddedLength   = 19 // int64 最大值的十进制位数
+	kBase32Length   = 13 // 13 个字符可以表示 65 位
+)
+
+var (
+	ErrInvalidBase62 = errors.New("snowflake: invalid base62 id")
+	ErrInvalidPadded = errors.New("snowflake: invalid padded id")
+	ErrInvalidBase32 = errors.New("snowflake: invalid base32 id")
+	ErrInvalidBase64 = errors.New("snowflake: invalid base64url id")
+)
+
+var base62Index = func() (m [256]int8) {
+	for i := range m {
+		m[i] = -1
+	}
+	for i := 0; i < len(kBase62Alphabet); i++ {
+		m[kBase62Alphabet[i]] = int8(i)
+	}
+	return m
+}()
+
+// Base62 获取 id 的 base62 编码
+func Base62(s int64) string {
+	if s == 0 {
+		return kBase62Alphabet[:1]
+	}
+	var b [11]byte
+	var i = len(b)
+	var n = uint64(s)
+	for n > 0 {
+		i--
+		b[i] = kBase62Alphabet[n%62]
+		n /= 62
+	}
+	return string(b[i:])
+}
+
+// ParseBase62 解析 base62 编码的 id
+func ParseBase62(s string) (int64, error) {
+	if len(s) == 0 || len(s) > 11 {
+		return 0, parseError("base62", ErrInvalidBase62, s, ErrBadLength, -1)
+	}
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		var v = base62Index[s[i]]
+		if v < 0 {
+			return 0, parseError("base62", ErrInvalidBase62, s, ErrBadCharacter, i)
+		}
+		if n > (1<<63-1-uint64(v))/62 {
+			return 0, parseError("base62", ErrInvalidBase62, s, ErrOutOfRange, -1)
+		}
+		n = n*62 + uint64(v)
+	}
+	return int64(n), nil
+}
+
+// PaddedString 获取固定 19 位的十进制形式的 id，不足 19 位时在前面补 0，用于只能处理定长字段的系统，id 需要大于等于 0
+func PaddedString(s int64) string {
+	var text = strconv.FormatInt(s, 10)
+	if len(text) >= kPaddedLength {
+		return text
+	}
+	return strings.Repeat("0", kPaddedLength-len(text)) + text
+}
+
+// ParsePadded 解析 PaddedString 生成的 id，只接受 19 位的十进制数字
+func ParsePadded(s string) (int64, error) {
+	if len(s) != kPaddedLength {
+		return 0, parseError("padded", ErrInvalidPadded, s, ErrBadLength, -1)
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, parseError("padded", ErrInvalidPadded, s, ErrBadCharacter, i)
+		}
+	}
+	var n, err = strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, parseError("padded", ErrInvalidPadded, s, ErrOutOfRange, -1)
+	}
+	return n, nil
+}
+
+// Base32 获取固定 13 个字符的 base32 编码，使用与 TypeID 相同的小写 Crockford 字母表，
+// 字母表按照 ASCII 排序，所以编码结果的字典序与 id 的大小一致，可以直接作为 S3 的 key 或者文件名排序，id 需要大于等于 0
+func Base32(s int64) string {
+	var b [kBase32Length]byte
+	var n = uint64(s)
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = kTypeIDAlphabet[n&0x1f]
+		n >>= 5
+	}
+	return string(b[:])
+}
+
+// ParseBase32 解析 Base32 生成的 id，只接受 13 个字符的小写编码
+func ParseBase32(s string) (int64, error) {
+	if len(s) != kBase32Length {
+		return 0, parseError("base32", ErrInvalidBase32, s, ErrBadLength, -1)
+	}
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		var v = typeIDIndex[s[i]]
+		if v < 0 {
+			return 0, parseError("base32", ErrInvalidBase32, s, ErrBadCharacter, i)
+		}
+		n = n<<5 | uint64(v)
+	}
+	// 第一个字符只有最低的 3 位属于 id，超过时 id 会溢出
+	if typeIDIndex[s[0]] > 7 {
+		return 0, parseError("base32", ErrInvalidBase32, s, ErrOutOfRange, -1)
+	}
+	return int64(n), nil
+}
+
+// Base64URL 获取固定 11 个字符的 URL 安全的 base64 编码（没有填充），即 8 字节大端序的 id 的编码，适用于短链接和 cookie
+func Base64URL(s int64) string {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(s))
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// ParseBase64URL 解析 Base64URL 生成的 id，只接受 11 个字符的规范编码
+func ParseBase64URL(s string) (int64, error) {
+	if len(s) != base64.RawURLEncoding.EncodedLen(8) {
+		return 0, parseError("base64url", ErrInvalidBase64, s, ErrBadLength, -1)
+	}
+	var b [8]byte
+	if _, err := base64.RawURLEncoding.Strict().Decode(b[:], []byte(s)); err != nil {
+		var offset = len(s) - 1
+		if corrupt, ok := err.(base64.CorruptInputError); ok && int(corrupt) < len(s) {
+			offset = int(corrupt)
+		}
+		return 0, parseError("base64url", ErrInvalidBase64, s, ErrBadCharacter, offset)
+	}
+	var n = int64(binary.BigEndian.Uint64(b[:]))
+	if n < 0 {
+		return 0, parseError("base64url", ErrInvalidBase64, s, ErrOutOfRange, -1)
+	}
+	return n, nil
+}
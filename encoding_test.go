@@ -0,0 +1,109 @@
+package snowflake
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+func TestBase62(t *testing.T) {
+	var ids = []int64{0, 1, 61, 62, 1<<63 - 1, Next()}
+	for _, id := range ids {
+		var s = Base62(id)
+		var got, err = ParseBase62(s)
+		if err != nil || got != id {
+			t.Fatalf("ParseBase62(%q) = %d, %v, want %d", s, got, err, id)
+		}
+	}
+
+	var bad = []string{"", "abc-", "AzL8n0Y58m8", "zzzzzzzzzzzz"}
+	for _, s := range bad {
+		if _, err := ParseBase62(s); !errors.Is(err, ErrInvalidBase62) {
+			t.Fatalf("ParseBase62(%q) error = %v, want %v", s, err, ErrInvalidBase62)
+		}
+	}
+}
+
+func TestPaddedString(t *testing.T) {
+	var ids = []int64{0, 7, 1465371247374594656, 1<<63 - 1, Next()}
+	for _, id := range ids {
+		var s = PaddedString(id)
+		if len(s) != 19 {
+			t.Fatalf("PaddedString(%d) = %q, want 19 characters", id, s)
+		}
+		var got, err = ParsePadded(s)
+		if err != nil || got != id {
+			t.Fatalf("ParsePadded(%q) = %d, %v, want %d", s, got, err, id)
+		}
+	}
+	if s := PaddedString(42); s != "0000000000000000042" {
+		t.Fatalf("PaddedString(42) = %q", s)
+	}
+
+	var bad = []string{"", "42", "000000000000000042", "+000000000000000042", "-000000000000000042", "00000000000000004 2", "9999999999999999999"}
+	for _, s := range bad {
+		if _, err := ParsePadded(s); !errors.Is(err, ErrInvalidPadded) {
+			t.Fatalf("ParsePadded(%q) error = %v, want %v", s, err, ErrInvalidPadded)
+		}
+	}
+}
+
+func TestBase32(t *testing.T) {
+	var ids = []int64{0, 1, 31, 32, 1465371247374594656, 1<<63 - 1, Next()}
+	for _, id := range ids {
+		var s = Base32(id)
+		if len(s) != 13 {
+			t.Fatalf("Base32(%d) = %q, want 13 characters", id, s)
+		}
+		var got, err = ParseBase32(s)
+		if err != nil || got != id {
+			t.Fatalf("ParseBase32(%q) = %d, %v, want %d", s, got, err, id)
+		}
+	}
+	if s := Base32(1<<63 - 1); s != "7zzzzzzzzzzzz" {
+		t.Fatalf("Base32(max) = %q", s)
+	}
+
+	// 字典序与 id 的大小一致
+	var values = []int64{1<<63 - 1, 0, 31, 1 << 40, 32, 1<<40 - 1, 7}
+	var encoded []string
+	for _, id := range values {
+		encoded = append(encoded, Base32(id))
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	sort.Strings(encoded)
+	for i := range values {
+		if encoded[i] != Base32(values[i]) {
+			t.Fatalf("sorted encodings = %v", encoded)
+		}
+	}
+
+	var bad = []string{"", "000000000000", "0000000000000u", "8000000000000", "000000000000U", "00000000000i0"}
+	for _, s := range bad {
+		if _, err := ParseBase32(s); !errors.Is(err, ErrInvalidBase32) {
+			t.Fatalf("ParseBase32(%q) error = %v, want %v", s, err, ErrInvalidBase32)
+		}
+	}
+}
+
+func TestBase64URL(t *testing.T) {
+	var ids = []int64{0, 1, 1465371247374594656, 1<<63 - 1, Next()}
+	for _, id := range ids {
+		var s = Base64URL(id)
+		if len(s) != 11 {
+			t.Fatalf("Base64URL(%d) = %q, want 11 characters", id, s)
+		}
+		var got, err = ParseBase64URL(s)
+		if err != nil || got != id {
+			t.Fatalf("ParseBase64URL(%q) = %d, %v, want %d", s, got, err, id)
+		}
+	}
+
+	// gAAAAAAAAAA 的最高位为 1，AAAAAAAAAAB 的最后一个字符包含多余的位
+	var bad = []string{"", "AAAAAAAAAA", "AAAAAAAAAAAA", "AAAAAAAAAA=", "AAAAAAAAAA+", "AAAAAAAAAA/", "gAAAAAAAAAA", "AAAAAAAAAAB"}
+	for _, s := range bad {
+		if _, err := ParseBase64URL(s); !errors.Is(err, ErrInvalidBase64) {
+			t.Fatalf("ParseBase64URL(%q) error = %v, want %v", s, err, ErrInvalidBase64)
+		}
+	}
+}
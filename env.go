@@ -0,0 +1,135 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EnvDataCenter = "SNOWFLAKE_DATACENTER"
+	EnvMachine    = "SNOWFLAKE_MACHINE"
+	EnvEpoch      = "SNOWFLAKE_EPOCH"
+	EnvLayout     = "SNOWFLAKE_LAYOUT"
+)
+
+var (
+	ErrInvalidEnv = errors.New("snowflake: invalid environment variable")
+)
+
+// NewFromEnv 使用环境变量创建生成器，便于容器化部署时不修改代码即可调整配置：
+//
+//	SNOWFLAKE_DATACENTER 数据中心标识，如 3
+//	SNOWFLAKE_MACHINE    机器标识，如 17
+//	SNOWFLAKE_EPOCH      时间偏移量，RFC 3339 格式的时间或者毫秒时间戳，如 2024-01-01T00:00:00Z
+//	SNOWFLAKE_LAYOUT     布局，格式参考 ParseLayout，如 datacenter=5,machine=5,sequence=12
+//
+// 没有设置的环境变量使用默认值，opts 在环境变量之后生效。环境变量的值无法解析时返回的错误包含变量名，可以通过 errors.Is 判断是否为 ErrInvalidEnv。
+func NewFromEnv(opts ...Option) (*SnowFlake, error) {
+	var envOpts, err = envOptions()
+	if err != nil {
+		return nil, err
+	}
+	return New(append(envOpts, opts...)...)
+}
+
+func envOptions() ([]Option, error) {
+	var opts []Option
+	if v, ok := lookupEnv(EnvLayout); ok {
+		var l, err = ParseLayout(v)
+		if err != nil {
+			return nil, envError(EnvLayout, v, err)
+		}
+		opts = append(opts, WithLayout(l))
+	}
+	if v, ok := lookupEnv(EnvEpoch); ok {
+		var epoch, err = parseEpoch(v)
+		if err != nil {
+			return nil, envError(EnvEpoch, v, err)
+		}
+		opts = append(opts, WithTimeOffset(epoch))
+	}
+	if v, ok := lookupEnv(EnvDataCenter); ok {
+		var n, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, envError(EnvDataCenter, v, err)
+		}
+		opts = append(opts, WithDataCenter(n))
+	}
+	if v, ok := lookupEnv(EnvMachine); ok {
+		var n, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, envError(EnvMachine, v, err)
+		}
+		opts = append(opts, WithMachine(n))
+	}
+	return opts, nil
+}
+
+func lookupEnv(key string) (string, bool) {
+	var v, ok = os.LookupEnv(key)
+	v = strings.TrimSpace(v)
+	return v, ok && v != ""
+}
+
+func envError(key, value string, err error) error {
+	return fmt.Errorf("%w %s=%q: %w", ErrInvalidEnv, key, value, err)
+}
+
+// parseEpoch 解析 RFC 3339 格式的时间或者毫秒时间戳
+func parseEpoch(v string) (time.Time, error) {
+	if mill, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return millisecondToTime(mill), nil
+	}
+	return time.Parse(time.RFC3339, v)
+}
+
+// ParseLayout 解析逗号分隔的布局，键为组成部分的名称，值为占用的位数，没有出现的部分占用 0 位，
+// 可以使用的名称为 shard、version、entity、region、datacenter、machine、boot 和 sequence，如：
+//
+//	datacenter=5,machine=5,sequence=12
+func ParseLayout(s string) (Layout, error) {
+	var l Layout
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		var kv = strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			return Layout{}, fmt.Errorf("%w: item %q should be name=bits", ErrInvalidLayout, item)
+		}
+		var bits, err = strconv.ParseUint(strings.TrimSpace(kv[1]), 10, 8)
+		if err != nil {
+			return Layout{}, fmt.Errorf("%w: invalid bits of item %q", ErrInvalidLayout, item)
+		}
+		var name = strings.ToLower(strings.TrimSpace(kv[0]))
+		switch name {
+		case "shard":
+			l.Shard = uint8(bits)
+		case "version":
+			l.Version = uint8(bits)
+		case "entity":
+			l.Entity = uint8(bits)
+		case "region":
+			l.Region = uint8(bits)
+		case "datacenter":
+			l.DataCenter = uint8(bits)
+		case "machine":
+			l.Machine = uint8(bits)
+		case "boot":
+			l.Boot = uint8(bits)
+		case "sequence":
+			l.Sequence = uint8(bits)
+		default:
+			return Layout{}, fmt.Errorf("%w: unknown part %q", ErrInvalidLayout, name)
+		}
+	}
+	if !l.valid() {
+		return Layout{}, ErrInvalidLayout
+	}
+	return l, nil
+}
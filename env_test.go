@@ -0,0 +1,60 @@
+package snowflake
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv(EnvLayout, "datacenter=3, machine=7, sequence=12")
+	t.Setenv(EnvEpoch, "2024-01-01T00:00:00Z")
+	t.Setenv(EnvDataCenter, "6")
+	t.Setenv(EnvMachine, "100")
+
+	var s, err = NewFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var id = s.Next()
+	if p := s.Decode(id); p.DataCenter != 6 || p.Machine != 100 {
+		t.Fatalf("Decode(%d) = %+v", id, p)
+	}
+	if !s.Epoch().Equal(testEpoch) || s.Layout().Machine != 7 {
+		t.Fatalf("Epoch() = %v, Layout() = %+v", s.Epoch(), s.Layout())
+	}
+	assertRecent(t, s, id)
+
+	// 毫秒时间戳
+	t.Setenv(EnvEpoch, "1704067200000")
+	if s, err = NewFromEnv(); err != nil || !s.Epoch().Equal(testEpoch) {
+		t.Fatalf("NewFromEnv() = %v, %v", s, err)
+	}
+}
+
+func TestNewFromEnv_Invalid(t *testing.T) {
+	var tests = []struct {
+		key   string
+		value string
+	}{
+		{EnvDataCenter, "a"},
+		{EnvMachine, "1.5"},
+		{EnvEpoch, "yesterday"},
+		{EnvLayout, "machine=5"},
+		{EnvLayout, "node=5,sequence=12"},
+	}
+	for _, test := range tests {
+		t.Run(test.key, func(t *testing.T) {
+			t.Setenv(test.key, test.value)
+			var _, err = NewFromEnv()
+			if !errors.Is(err, ErrInvalidEnv) || !strings.Contains(err.Error(), test.key) {
+				t.Fatalf("NewFromEnv() error = %v", err)
+			}
+		})
+	}
+
+	t.Setenv(EnvMachine, "64")
+	if _, err := NewFromEnv(); !errors.Is(err, ErrWorkerNotAllowed) {
+		t.Fatalf("NewFromEnv() error = %v, want %v", err, ErrWorkerNotAllowed)
+	}
+}
@@ -0,0 +1,61 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidEpoch = errors.New("snowflake: invalid epoch")
+)
+
+// WithEpochMillis 使用毫秒时间戳设置时间偏移量，与 WithTimeOffset 相同
+func WithEpochMillis(mill int64) Option {
+	return WithTimeOffset(millisecondToTime(mill))
+}
+
+// WithEpochString 使用 RFC 3339 格式的时间设置时间偏移量，如 2020-01-01T00:00:00Z，无法解析时返回的错误可以通过 errors.Is 判断是否为 ErrInvalidEpoch
+func WithEpochString(s string) Option {
+	return optionFunc(func(sf *SnowFlake) error {
+		var t, err = time.Parse(time.RFC3339, s)
+		if err != nil {
+			return fmt.Errorf("%w %q: %w", ErrInvalidEpoch, s, err)
+		}
+		return WithTimeOffset(t).Apply(sf)
+	})
+}
+
+// WithEpochExhaustedHook 设置时间戳部分用完时的回调函数，end 为时间戳部分能够表示的最后时间。
+//
+// 时间戳部分用完之后生成器不会再生成 id，Next 返回 -1，NextID 返回 ErrEpochExhausted，回调函数只会调用一次。
+// 回调函数在生成 id 的时候同步调用，调用期间生成器处于锁定状态，不能在回调函数中调用生成器的方法。
+func WithEpochExhaustedHook(hook func(end time.Time)) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		s.exhaustedHook = hook
+		return nil
+	})
+}
+
+// EpochEnd 获取时间戳部分能够表示的最后时间，超过之后生成器无法再生成 id，需要通过 WithTimeOffset 设置新的时间偏移量
+func (this *SnowFlake) EpochEnd() time.Time {
+	return millisecondToTime(this.timeOffset + this.bits.time.max)
+}
+
+// checkEpoch 检查时间戳是否超出了时间戳部分的范围，调用方需要持有 mu
+func (this *SnowFlake) checkEpoch(millisecond int64) error {
+	if this.bits.time.allow(millisecond - this.timeOffset) {
+		return nil
+	}
+	if millisecond < this.timeOffset {
+		return ErrEpochInFuture
+	}
+	if !this.exhausted {
+		this.exhausted = true
+		this.recordEvent(EventEpochExhausted, "end="+this.EpochEnd().UTC().Format(kExplainTimeLayout))
+		if this.exhaustedHook != nil {
+			this.exhaustedHook(this.EpochEnd())
+		}
+	}
+	return ErrEpochExhausted
+}
@@ -0,0 +1,70 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithEpochExhaustedHook(t *testing.T) {
+	var calls []time.Time
+	var s, _ = New(WithTimeOffset(testEpoch), WithEntityBits(3), WithEpochExhaustedHook(func(end time.Time) {
+		calls = append(calls, end)
+	}))
+	var end = s.EpochEnd()
+	if want := testEpoch.Add(time.Duration(1<<38-1) * time.Millisecond); !end.Equal(want) {
+		t.Fatalf("EpochEnd() = %v, want %v", end, want)
+	}
+
+	// 模拟时间戳部分已经用完
+	s.clock = func() time.Time { return end.Add(time.Millisecond) }
+	s.anchor = s.clock()
+	s.anchorMill = s.anchor.UnixNano() / 1e6
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.NextID(); err != ErrEpochExhausted {
+			t.Fatalf("NextID() error = %v, want %v", err, ErrEpochExhausted)
+		}
+	}
+	if _, err := s.NextBlock(3); err != ErrEpochExhausted {
+		t.Fatalf("NextBlock() error = %v, want %v", err, ErrEpochExhausted)
+	}
+	if id := s.Next(); id != -1 {
+		t.Fatalf("Next() = %d, want -1", id)
+	}
+	if len(calls) != 1 || !calls[0].Equal(end) {
+		t.Fatalf("hook calls = %v, want once with %v", calls, end)
+	}
+}
+
+func TestNew_EpochInFuture(t *testing.T) {
+	if _, err := New(WithTimeOffset(time.Now().Add(time.Hour))); err != ErrEpochInFuture {
+		t.Fatalf("New() error = %v, want %v", err, ErrEpochInFuture)
+	}
+	if _, err := New(WithTimeOffset(time.Now().Add(-time.Hour))); err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// 生成 id 时的时间早于时间偏移量
+	var s, _ = New(WithTimeOffset(testEpoch))
+	s.clock = func() time.Time { return testEpoch.Add(-time.Second) }
+	s.anchor = s.clock()
+	s.anchorMill = s.anchor.UnixNano() / 1e6
+	s.millisecond = 0
+	if _, err := s.NextID(); err != ErrEpochInFuture {
+		t.Fatalf("NextID() error = %v, want %v", err, ErrEpochInFuture)
+	}
+}
+
+func TestWithEpochMillis(t *testing.T) {
+	var s, err = New(WithEpochMillis(1704067200000))
+	if err != nil || !s.Epoch().Equal(testEpoch) {
+		t.Fatalf("New() = %v, %v", s, err)
+	}
+	if s, err = New(WithEpochString("2024-01-01T08:00:00+08:00")); err != nil || !s.Epoch().Equal(testEpoch) {
+		t.Fatalf("New() = %v, %v", s, err)
+	}
+	if _, err = New(WithEpochString("2024-01-01")); !errors.Is(err, ErrInvalidEpoch) {
+		t.Fatalf("New() error = %v, want %v", err, ErrInvalidEpoch)
+	}
+}
@@ -0,0 +1,27 @@
+package snowflake
+
+import (
+	"fmt"
+)
+
+// RangeError 标识或者配置超出布局范围时返回的错误，包含超出范围的值和允许的最大值。
+//
+// 可以通过 errors.Is 判断对应的错误，如 errors.Is(err, ErrWorkerNotAllowed)，也可以通过 errors.As 获取具体的值。
+type RangeError struct {
+	Field string // 超出范围的配置，如 data center、machine
+	Got   int64  // 超出范围的值
+	Max   int64  // 允许的最大值，最小值为 0
+	Err   error  // 对应的错误，如 ErrDataCenterNotAllowed
+}
+
+func (this *RangeError) Error() string {
+	return fmt.Sprintf("snowflake: %s %d out of range [0, %d]", this.Field, this.Got, this.Max)
+}
+
+func (this *RangeError) Unwrap() error {
+	return this.Err
+}
+
+func rangeError(field string, got int64, f field, err error) error {
+	return &RangeError{Field: field, Got: got, Max: f.max, Err: err}
+}
@@ -0,0 +1,35 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRangeError(t *testing.T) {
+	var _, err = New(WithLayout(Layout{DataCenter: 2, Machine: 4, Sequence: 12}), WithMachine(20))
+	var re *RangeError
+	if !errors.As(err, &re) || re.Field != "machine" || re.Got != 20 || re.Max != 15 || !errors.Is(err, ErrWorkerNotAllowed) {
+		t.Fatalf("New() error = %#v", err)
+	}
+	if err.Error() != "snowflake: machine 20 out of range [0, 15]" {
+		t.Fatalf("Error() = %q", err.Error())
+	}
+
+	// 使用最终的布局校验时返回的错误
+	_, err = New(WithDataCenter(9), WithMachine(9), WithLayout(Layout{DataCenter: 3, Machine: 3, Sequence: 12}))
+	if !errors.As(err, &re) || re.Field != "data center" || re.Max != 7 || !errors.Is(err, ErrWorkerNotAllowed) {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	_, err = New(WithWorkerID(-1))
+	if !errors.As(err, &re) || re.Field != "worker id" || re.Max != 1023 || !errors.Is(err, ErrWorkerIDNotAllowed) {
+		t.Fatalf("New() error = %v", err)
+	}
+}
+
+func TestConfig_WrapsCause(t *testing.T) {
+	var _, err = NewWithConfig(Config{Layout: "node=3,sequence=12"})
+	if !errors.Is(err, ErrInvalidConfig) || !errors.Is(err, ErrInvalidLayout) {
+		t.Fatalf("NewWithConfig() error = %v, want both %v and %v", err, ErrInvalidConfig, ErrInvalidLayout)
+	}
+}
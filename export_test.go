@@ -0,0 +1,11 @@
+package snowflake
+
+import (
+	"testing"
+)
+
+// ResetDefault 清除默认生成器，tb 对应的测试结束时恢复，用于包内的测试，其它包的测试使用 snowflaketest.ResetDefault
+func ResetDefault(tb testing.TB) {
+	tb.Helper()
+	tb.Cleanup(resetDefault())
+}
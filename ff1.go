@@ -0,0 +1,175 @@
+package snowflake
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"math/big"
+)
+
+const (
+	kFF1Rounds = 10
+)
+
+var (
+	ErrInvalidFF1Key = errors.New("snowflake: ff1 key must be 16, 24 or 32 bytes")
+	ErrInvalidFF1ID  = errors.New("snowflake: ff1 id can't be less than 0")
+)
+
+// FF1 使用 NIST SP 800-38G 中定义的 AES-FF1 格式保留加密算法对 id 进行加密。
+//
+// 加密在 63 位的空间内进行，加密结果仍然是非负的 int64，并且与原始 id 一一对应，
+// 对外暴露的 id 不会携带任何时间信息，持有密钥的一方可以通过 Decrypt 还原出原始 id。
+type FF1 struct {
+	block cipher.Block
+	tweak []byte
+}
+
+// NewFF1 创建 FF1，key 为 AES 密钥，tweak 为可选的调整值，相同的 key 使用不同的 tweak 会得到不同的映射
+func NewFF1(key []byte, tweak []byte) (*FF1, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, ErrInvalidFF1Key
+	}
+	var block, err = aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	var f = &FF1{}
+	f.block = block
+	f.tweak = append([]byte(nil), tweak...)
+	return f, nil
+}
+
+// Encrypt 加密 id
+func (this *FF1) Encrypt(id int64) (int64, error) {
+	if id < 0 {
+		return 0, ErrInvalidFF1ID
+	}
+	var x = this.encrypt(2, int(kIDBits), big.NewInt(id))
+	return x.Int64(), nil
+}
+
+// Decrypt 解密由 Encrypt 加密的 id
+func (this *FF1) Decrypt(id int64) (int64, error) {
+	if id < 0 {
+		return 0, ErrInvalidFF1ID
+	}
+	var x = this.decrypt(2, int(kIDBits), big.NewInt(id))
+	return x.Int64(), nil
+}
+
+// encrypt 对长度为 n 的 radix 进制数字串 x 进行加密，x 以整数形式表示
+func (this *FF1) encrypt(radix, n int, x *big.Int) *big.Int {
+	var u = n / 2
+	var v = n - u
+	var a, b = this.split(radix, v, x)
+	var p, b64, d = this.prepare(radix, n, u, v)
+
+	var modU = pow(radix, u)
+	var modV = pow(radix, v)
+	for i := 0; i < kFF1Rounds; i++ {
+		var y = this.round(p, i, b, b64, d)
+		var m = modV
+		if i%2 == 0 {
+			m = modU
+		}
+		var c = new(big.Int).Add(a, y)
+		c.Mod(c, m)
+		a, b = b, c
+	}
+	return new(big.Int).Add(new(big.Int).Mul(a, modV), b)
+}
+
+// decrypt 对长度为 n 的 radix 进制数字串 x 进行解密，x 以整数形式表示
+func (this *FF1) decrypt(radix, n int, x *big.Int) *big.Int {
+	var u = n / 2
+	var v = n - u
+	var a, b = this.split(radix, v, x)
+	var p, b64, d = this.prepare(radix, n, u, v)
+
+	var modU = pow(radix, u)
+	var modV = pow(radix, v)
+	for i := kFF1Rounds - 1; i >= 0; i-- {
+		var y = this.round(p, i, a, b64, d)
+		var m = modV
+		if i%2 == 0 {
+			m = modU
+		}
+		var c = new(big.Int).Sub(b, y)
+		c.Mod(c, m)
+		b, a = a, c
+	}
+	return new(big.Int).Add(new(big.Int).Mul(a, modV), b)
+}
+
+func (this *FF1) split(radix, v int, x *big.Int) (*big.Int, *big.Int) {
+	var a, b = new(big.Int).QuoRem(x, pow(radix, v), new(big.Int))
+	return a, b
+}
+
+// prepare 计算固定的 P 块以及每一轮使用的 b、d 的长度
+func (this *FF1) prepare(radix, n, u, v int) (p []byte, b int, d int) {
+	var bits = new(big.Int).Sub(pow(radix, v), big.NewInt(1)).BitLen()
+	b = (bits + 7) / 8
+	d = 4*((b+3)/4) + 4
+
+	p = make([]byte, aes.BlockSize)
+	p[0] = 1
+	p[1] = 2
+	p[2] = 1
+	p[3] = byte(radix >> 16)
+	p[4] = byte(radix >> 8)
+	p[5] = byte(radix)
+	p[6] = 10
+	p[7] = byte(u)
+	binary.BigEndian.PutUint32(p[8:12], uint32(n))
+	binary.BigEndian.PutUint32(p[12:16], uint32(len(this.tweak)))
+	return p, b, d
+}
+
+// round 计算第 i 轮的 y 值
+func (this *FF1) round(p []byte, i int, num *big.Int, b, d int) *big.Int {
+	var t = len(this.tweak)
+	var pad = ((-t-b-1)%16 + 16) % 16
+
+	var q = make([]byte, t+pad+1+b)
+	copy(q, this.tweak)
+	q[t+pad] = byte(i)
+	var nb = num.Bytes()
+	copy(q[len(q)-len(nb):], nb)
+
+	// PRF: 使用全零的 IV 对 P || Q 进行 CBC-MAC
+	var r = make([]byte, aes.BlockSize)
+	this.cbcMAC(r, p)
+	this.cbcMAC(r, q)
+
+	var s = make([]byte, 0, ((d+aes.BlockSize-1)/aes.BlockSize)*aes.BlockSize)
+	s = append(s, r...)
+	for j := 1; len(s) < d; j++ {
+		var block = make([]byte, aes.BlockSize)
+		binary.BigEndian.PutUint64(block[8:], uint64(j))
+		for k := range block {
+			block[k] ^= r[k]
+		}
+		this.block.Encrypt(block, block)
+		s = append(s, block...)
+	}
+	return new(big.Int).SetBytes(s[:d])
+}
+
+func (this *FF1) cbcMAC(y []byte, data []byte) {
+	for len(data) > 0 {
+		for k := 0; k < aes.BlockSize; k++ {
+			y[k] ^= data[k]
+		}
+		this.block.Encrypt(y, y)
+		data = data[aes.BlockSize:]
+	}
+}
+
+func pow(radix, n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(int64(radix)), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,57 @@
+package snowflake
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestFF1_NIST(t *testing.T) {
+	// NIST SP 800-38G 示例 1 - 3
+	var tests = []struct {
+		radix int
+		tweak string
+		pt    string
+		ct    string
+	}{
+		{10, "", "0123456789", "2433477484"},
+		{10, "39383736353433323130", "0123456789", "6124200773"},
+		{36, "3737373770717273373737", "0123456789abcdefghi", "a9tv40mll9kdu509eum"},
+	}
+	var key, _ = hex.DecodeString("2B7E151628AED2A6ABF7158809CF4F3C")
+
+	for _, test := range tests {
+		var tweak, _ = hex.DecodeString(test.tweak)
+		var f, err = NewFF1(key, tweak)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var pt, _ = new(big.Int).SetString(test.pt, test.radix)
+		var ct = f.encrypt(test.radix, len(test.pt), pt)
+		if got := ct.Text(test.radix); got != test.ct {
+			t.Fatalf("encrypt(%s) = %s, want %s", test.pt, got, test.ct)
+		}
+		if got := f.decrypt(test.radix, len(test.pt), ct); got.Cmp(pt) != 0 {
+			t.Fatalf("decrypt(%s) = %s, want %s", test.ct, got.Text(test.radix), test.pt)
+		}
+	}
+}
+
+func TestFF1_EncryptID(t *testing.T) {
+	var f, _ = NewFF1([]byte("0123456789abcdef"), []byte("snowflake"))
+	var ids = []int64{0, 1, 1<<63 - 1, Next()}
+	for _, id := range ids {
+		var enc, err = f.Encrypt(id)
+		if err != nil || enc < 0 {
+			t.Fatalf("Encrypt(%d) = %d, %v", id, enc, err)
+		}
+		if dec, _ := f.Decrypt(enc); dec != id {
+			t.Fatalf("Decrypt(%d) = %d, want %d", enc, dec, id)
+		}
+	}
+
+	if _, err := f.Encrypt(-1); err != ErrInvalidFF1ID {
+		t.Fatalf("Encrypt(-1) error = %v, want %v", err, ErrInvalidFF1ID)
+	}
+}
@@ -0,0 +1,20 @@
+package snowflake
+
+import (
+	"flag"
+)
+
+// RegisterFlags 在 fs 中注册生成器的命令行参数，解析之后通过 NewWithConfig 创建生成器，让基于本包的命令行工具使用一致的参数：
+//
+//	-snowflake.datacenter 数据中心标识
+//	-snowflake.machine    机器标识
+//	-snowflake.epoch      时间偏移量，RFC 3339 格式的时间或者毫秒时间戳
+//	-snowflake.layout     布局，格式参考 ParseLayout
+func RegisterFlags(fs *flag.FlagSet) *Config {
+	var cfg = &Config{}
+	fs.Int64Var(&cfg.DataCenter, "snowflake.datacenter", 0, "snowflake data center id")
+	fs.Int64Var(&cfg.Machine, "snowflake.machine", 0, "snowflake machine id")
+	fs.StringVar(&cfg.Epoch, "snowflake.epoch", "", "snowflake epoch, RFC 3339 time or unix milliseconds")
+	fs.StringVar(&cfg.Layout, "snowflake.layout", "", "snowflake layout, e.g. datacenter=5,machine=5,sequence=12")
+	return cfg
+}
@@ -0,0 +1,25 @@
+package snowflake
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestRegisterFlags(t *testing.T) {
+	var fs = flag.NewFlagSet("test", flag.ContinueOnError)
+	var cfg = RegisterFlags(fs)
+	var err = fs.Parse([]string{"-snowflake.datacenter=2", "-snowflake.machine", "30", "-snowflake.epoch=2024-01-01T00:00:00Z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s *SnowFlake
+	if s, err = NewWithConfig(*cfg); err != nil {
+		t.Fatal(err)
+	}
+	var id = s.Next()
+	if p := s.Decode(id); p.DataCenter != 2 || p.Machine != 30 || !s.Epoch().Equal(testEpoch) {
+		t.Fatalf("Decode(%d) = %+v, Epoch() = %v", id, p, s.Epoch())
+	}
+	assertRecent(t, s, id)
+}
@@ -0,0 +1,207 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math/bits"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	kFlakeMaxWorker   = 1<<48 - 1
+	kFlakeMaxSequence = 1<<16 - 1
+	kFlakeBase62Len   = 22 // 128 位最多需要 22 个 base62 字符
+)
+
+var (
+	ErrInvalidFlake       = errors.New("snowflake: invalid flake id")
+	ErrFlakeWorkerInvalid = errors.New("snowflake: flake worker needs 48 bits, such as a mac address")
+)
+
+// Flake 与 Boundary Flake 格式兼容的 128 位 id，从高位到低位依次为：64 位毫秒时间戳（从 1970-01-01 开始）、
+// 48 位工作节点标识（通常为 MAC 地址）和 16 位序列号，均为大端序，可以直接按照字节比较大小。
+//
+// Erlang 的 flake 服务返回的 16 字节的二进制 id 可以通过 FlakeFromBytes 转换，base62 形式的 id 可以通过 ParseFlake 解析。
+type Flake [16]byte
+
+// NewFlake 使用时间、工作节点标识和序列号组成 Flake，worker 只使用低 48 位
+func NewFlake(t time.Time, worker uint64, sequence uint16) Flake {
+	var f Flake
+	binary.BigEndian.PutUint64(f[:8], uint64(t.UnixNano()/1e6))
+	binary.BigEndian.PutUint64(f[8:], (worker&kFlakeMaxWorker)<<16|uint64(sequence))
+	return f
+}
+
+// FlakeFromBytes 将 16 字节的二进制 id 转换为 Flake
+func FlakeFromBytes(b []byte) (Flake, error) {
+	var f Flake
+	if len(b) != len(f) {
+		return f, ErrInvalidFlake
+	}
+	copy(f[:], b)
+	return f, nil
+}
+
+// ParseFlake 解析 base62 形式的 Flake，与 flake 的 id(62) 返回的格式相同
+func ParseFlake(s string) (Flake, error) {
+	var f Flake
+	if len(s) == 0 || len(s) > kFlakeBase62Len {
+		return f, ErrInvalidFlake
+	}
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		var v = base62Index[s[i]]
+		if v < 0 {
+			return f, ErrInvalidFlake
+		}
+		// (hi, lo) = (hi, lo) * 62 + v，超过 128 位时返回错误
+		var high, carry, overflow uint64
+		high, lo = bits.Mul64(lo, 62)
+		lo, carry = bits.Add64(lo, uint64(v), 0)
+		overflow, hi = bits.Mul64(hi, 62)
+		if hi, carry = bits.Add64(hi, high+carry, 0); overflow != 0 || carry != 0 {
+			return f, ErrInvalidFlake
+		}
+	}
+	binary.BigEndian.PutUint64(f[:8], hi)
+	binary.BigEndian.PutUint64(f[8:], lo)
+	return f, nil
+}
+
+// ParseFlakeHex 解析 32 个字符的十六进制形式的 Flake
+func ParseFlakeHex(s string) (Flake, error) {
+	var f Flake
+	if len(s) != 2*len(f) {
+		return f, ErrInvalidFlake
+	}
+	if _, err := hex.Decode(f[:], []byte(s)); err != nil {
+		return f, ErrInvalidFlake
+	}
+	return f, nil
+}
+
+// Timestamp 获取 Flake 的时间戳（毫秒）
+func (this Flake) Timestamp() int64 {
+	return int64(binary.BigEndian.Uint64(this[:8]))
+}
+
+// Time 获取 Flake 的生成时间
+func (this Flake) Time() time.Time {
+	return millisecondToTime(this.Timestamp())
+}
+
+// Worker 获取 Flake 的工作节点标识
+func (this Flake) Worker() uint64 {
+	return binary.BigEndian.Uint64(this[8:]) >> 16
+}
+
+// MAC 获取 Flake 的工作节点标识对应的 MAC 地址
+func (this Flake) MAC() net.HardwareAddr {
+	return net.HardwareAddr(append([]byte(nil), this[8:14]...))
+}
+
+// Sequence 获取 Flake 的序列号
+func (this Flake) Sequence() uint16 {
+	return binary.BigEndian.Uint16(this[14:])
+}
+
+// Base62 获取 base62 形式的 Flake
+func (this Flake) Base62() string {
+	var hi, lo = binary.BigEndian.Uint64(this[:8]), binary.BigEndian.Uint64(this[8:])
+	if hi == 0 && lo == 0 {
+		return "0"
+	}
+	var buf [kFlakeBase62Len]byte
+	var i = len(buf)
+	for hi != 0 || lo != 0 {
+		var r uint64
+		hi, r = bits.Div64(0, hi, 62)
+		lo, r = bits.Div64(r, lo, 62)
+		i--
+		buf[i] = kBase62Alphabet[r]
+	}
+	return string(buf[i:])
+}
+
+// Hex 获取十六进制形式的 Flake
+func (this Flake) Hex() string {
+	return hex.EncodeToString(this[:])
+}
+
+func (this Flake) String() string {
+	return this.Base62()
+}
+
+// MarshalText 使用 base62 形式序列化，JSON 中为字符串
+func (this Flake) MarshalText() ([]byte, error) {
+	return []byte(this.Base62()), nil
+}
+
+func (this *Flake) UnmarshalText(text []byte) error {
+	var f, err = ParseFlake(string(text))
+	if err != nil {
+		return err
+	}
+	*this = f
+	return nil
+}
+
+// FlakeWorker 将 6 字节的 MAC 地址转换为 Flake 的工作节点标识
+func FlakeWorker(mac net.HardwareAddr) (uint64, error) {
+	if len(mac) != 6 {
+		return 0, ErrFlakeWorkerInvalid
+	}
+	var b [8]byte
+	copy(b[2:], mac)
+	return binary.BigEndian.Uint64(b[:]), nil
+}
+
+// FlakeGenerator 生成与 Boundary Flake 兼容的 id，每一毫秒的序列号从 0 开始，与 flake 一样检测到时钟回拨时返回错误
+type FlakeGenerator struct {
+	mu          sync.Mutex
+	worker      uint64
+	millisecond int64
+	sequence    int64
+	clock       func() time.Time
+}
+
+// NewFlakeGenerator 创建 FlakeGenerator，worker 为 48 位的工作节点标识，可以通过 FlakeWorker 由 MAC 地址得到，
+// 不能与 Erlang 的 flake 服务使用相同的 MAC 地址
+func NewFlakeGenerator(worker uint64) (*FlakeGenerator, error) {
+	if worker > kFlakeMaxWorker {
+		return nil, ErrFlakeWorkerInvalid
+	}
+	var g = &FlakeGenerator{}
+	g.worker = worker
+	g.sequence = -1
+	g.clock = time.Now
+	return g, nil
+}
+
+// Next 生成新的 Flake，时钟比上一次生成 id 的时间慢时返回 ErrClockMovedBackwards
+func (this *FlakeGenerator) Next() (Flake, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	for {
+		var now = this.clock()
+		var millisecond = now.UnixNano() / 1e6
+		switch {
+		case millisecond < this.millisecond:
+			return Flake{}, ErrClockMovedBackwards
+		case millisecond > this.millisecond:
+			this.millisecond = millisecond
+			this.sequence = 0
+		case this.sequence < kFlakeMaxSequence:
+			this.sequence++
+		default:
+			// 当前毫秒的序列号用完，等待下一毫秒
+			time.Sleep(time.Duration(millisecond+1)*time.Millisecond - time.Duration(now.UnixNano()))
+			continue
+		}
+		return NewFlake(millisecondToTime(this.millisecond), this.worker, uint16(this.sequence)), nil
+	}
+}
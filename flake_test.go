@@ -0,0 +1,88 @@
+package snowflake
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestFlake(t *testing.T) {
+	var mac, _ = net.ParseMAC("01:23:45:67:89:ab")
+	var worker, err = FlakeWorker(mac)
+	if err != nil || worker != 0x0123456789ab {
+		t.Fatalf("FlakeWorker(%s) = %x, %v", mac, worker, err)
+	}
+	if _, err = FlakeWorker(mac[:4]); err != ErrFlakeWorkerInvalid {
+		t.Fatalf("FlakeWorker() error = %v, want %v", err, ErrFlakeWorkerInvalid)
+	}
+
+	var f = NewFlake(millisecondToTime(1700000000000), worker, 7)
+	var want = []byte{0, 0, 0x01, 0x8b, 0xcf, 0xe5, 0x68, 0x00, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab, 0, 7}
+	if !bytes.Equal(f[:], want) {
+		t.Fatalf("NewFlake() = %x, want %x", f[:], want)
+	}
+	if f.Timestamp() != 1700000000000 || f.Worker() != worker || f.Sequence() != 7 || f.MAC().String() != mac.String() {
+		t.Fatalf("Flake fields = %d, %x, %d, %s", f.Timestamp(), f.Worker(), f.Sequence(), f.MAC())
+	}
+
+	// base62 与 flake 的 id(62) 相同
+	for v, s := range map[Flake]string{f: "AboKHPfE31cZfIwdd9", {}: "0", {7: 1}: "LygHa16AHYG", {0: 0xff, 1: 0xff, 2: 0xff, 3: 0xff, 4: 0xff, 5: 0xff, 6: 0xff, 7: 0xff, 8: 0xff, 9: 0xff, 10: 0xff, 11: 0xff, 12: 0xff, 13: 0xff, 14: 0xff, 15: 0xff}: "7n42DGM5Tflk9n8mt7Fhc7"} {
+		if v.Base62() != s {
+			t.Fatalf("Base62(%s) = %s, want %s", v.Hex(), v.Base62(), s)
+		}
+		if parsed, err := ParseFlake(s); err != nil || parsed != v {
+			t.Fatalf("ParseFlake(%s) = %s, %v", s, parsed.Hex(), err)
+		}
+	}
+	for _, s := range []string{"", "7n42DGM5Tflk9n8mt7Fhc8", "zzzzzzzzzzzzzzzzzzzzzz", "0000000000000000000000z", "Abo-KHP"} {
+		if _, err = ParseFlake(s); err != ErrInvalidFlake {
+			t.Fatalf("ParseFlake(%q) error = %v, want %v", s, err, ErrInvalidFlake)
+		}
+	}
+
+	if parsed, err := ParseFlakeHex(f.Hex()); err != nil || parsed != f {
+		t.Fatalf("ParseFlakeHex(%s) = %s, %v", f.Hex(), parsed.Hex(), err)
+	}
+	if parsed, err := FlakeFromBytes(want); err != nil || parsed != f {
+		t.Fatalf("FlakeFromBytes() = %s, %v", parsed.Hex(), err)
+	}
+	if _, err = FlakeFromBytes(want[:15]); err != ErrInvalidFlake {
+		t.Fatalf("FlakeFromBytes() error = %v, want %v", err, ErrInvalidFlake)
+	}
+
+	var data, _ = json.Marshal(f)
+	var decoded Flake
+	if err = json.Unmarshal(data, &decoded); err != nil || decoded != f {
+		t.Fatalf("json round trip %s = %s, %v", data, decoded.Hex(), err)
+	}
+}
+
+func TestFlakeGenerator(t *testing.T) {
+	if _, err := NewFlakeGenerator(1 << 48); err != ErrFlakeWorkerInvalid {
+		t.Fatalf("NewFlakeGenerator() error = %v, want %v", err, ErrFlakeWorkerInvalid)
+	}
+
+	var g, _ = NewFlakeGenerator(0x0123456789ab)
+	var last Flake
+	for i := 0; i < 100000; i++ {
+		var f, err = g.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Compare(last[:], f[:]) >= 0 {
+			t.Fatalf("Next() = %s, should be greater than %s", f.Hex(), last.Hex())
+		}
+		if f.Worker() != 0x0123456789ab {
+			t.Fatalf("Worker() = %x", f.Worker())
+		}
+		last = f
+	}
+
+	var now = time.Now()
+	g.clock = func() time.Time { return now.Add(-time.Second) }
+	if _, err := g.Next(); err != ErrClockMovedBackwards {
+		t.Fatalf("Next() error = %v, want %v", err, ErrClockMovedBackwards)
+	}
+}
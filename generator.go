@@ -0,0 +1,43 @@
+package snowflake
+
+import (
+	"strconv"
+)
+
+// Generator id 生成器的通用接口，SnowFlake、HiLo 等生成器都实现了该接口
+type Generator interface {
+	// NextID 生成新的 id
+	NextID() (int64, error)
+}
+
+// NextID 生成新的 id，与 Next 不同的是，无法生成 id 时会返回具体的错误
+func (this *SnowFlake) NextID() (int64, error) {
+	return this.next(0)
+}
+
+// NextWithString 生成新的 id，同时返回 id 的十进制字符串，无法生成 id 时返回 -1 和空字符串
+func (this *SnowFlake) NextWithString() (int64, string) {
+	var id, err = this.next(0)
+	if err != nil {
+		return -1, ""
+	}
+	return id, strconv.FormatInt(id, 10)
+}
+
+// NextAs 生成新的 id，同时返回使用 encode 编码之后的字符串，如：
+//
+//	id, s, err := sf.NextAs(snowflake.Base62)
+//
+// 避免分别调用 Next 和编码函数时误用了两个不同的 id，encode 也可以是 Encoding 的 Encode 等方法。
+func (this *SnowFlake) NextAs(encode func(int64) string) (int64, string, error) {
+	var id, err = this.next(0)
+	if err != nil {
+		return 0, "", err
+	}
+	return id, encode(id), nil
+}
+
+// NextAs 使用默认生成器生成新的 id，同时返回使用 encode 编码之后的字符串
+func NextAs(encode func(int64) string) (int64, string, error) {
+	return getDefault().NextAs(encode)
+}
@@ -0,0 +1,36 @@
+package snowflake
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSnowFlake_NextAs(t *testing.T) {
+	var s, _ = New(WithMaxWait(10 * time.Millisecond))
+	var id, text = s.NextWithString()
+	if text != strconv.FormatInt(id, 10) {
+		t.Fatalf("NextWithString() = %d, %q", id, text)
+	}
+
+	var enc, _ = NewEncoding("0123456789abcdefghijklmnopqrstuvwxyz")
+	for _, encode := range []func(int64) string{Base62, Base58, Hex, enc.Encode} {
+		var id, text, err = s.NextAs(encode)
+		if err != nil || text != encode(id) {
+			t.Fatalf("NextAs() = %d, %q, %v", id, text, err)
+		}
+	}
+
+	// 无法生成 id 时不调用编码函数
+	var now = time.Now().Add(time.Millisecond)
+	s.clock = func() time.Time { return now }
+	for i := int64(0); i <= kMaxSequence; i++ {
+		s.Next()
+	}
+	if id, text = s.NextWithString(); id != -1 || text != "" {
+		t.Fatalf("NextWithString() = %d, %q, want -1", id, text)
+	}
+	if _, _, err := s.NextAs(func(int64) string { t.Fatal("encode called"); return "" }); err != ErrWaitTimeout {
+		t.Fatalf("NextAs() error = %v, want %v", err, ErrWaitTimeout)
+	}
+}
@@ -1,3 +1,3 @@
 module github.com/smartwalle/snowflake
 
-go 1.12
+go 1.21
@@ -0,0 +1,13 @@
+//go:build !windows
+// +build !windows
+
+package snowflake
+
+import (
+	"time"
+)
+
+// measureGranularity 其它平台的时钟精度足够高，不需要提前使用时间戳
+func measureGranularity() time.Duration {
+	return 0
+}
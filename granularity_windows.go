@@ -0,0 +1,35 @@
+//go:build windows
+// +build windows
+
+package snowflake
+
+import (
+	"time"
+)
+
+const (
+	kGranularitySamples = 3
+)
+
+// measureGranularity 测量时钟读数变化的最小间隔，Windows 的时钟读数会按照系统定时器的精度跳变，默认约为 15.6ms，
+// 其它程序通过 timeBeginPeriod 提高了定时器的精度时测量的结果为 1ms 左右，最多测量约 50ms
+func measureGranularity() time.Duration {
+	var min time.Duration
+	var deadline = time.Now().Add(50 * time.Millisecond)
+	var last = time.Now()
+	for i := 0; i < kGranularitySamples && time.Now().Before(deadline); {
+		var now = time.Now()
+		if step := now.Sub(last); step > 0 {
+			if i > 0 && (min == 0 || step < min) {
+				// 第一次变化时 last 不是跳变的时间，不能作为间隔
+				min = step
+			}
+			last = now
+			i++
+		}
+	}
+	if min <= time.Millisecond {
+		return 0
+	}
+	return min
+}
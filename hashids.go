@@ -0,0 +1,257 @@
+package snowflake
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+)
+
+const (
+	kHashidsAlphabet      = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
+	kHashidsSeparators    = "cfhistuCFHISTU"
+	kHashidsMinAlphabet   = 16
+	kHashidsSeparatorDiv  = 3.5
+	kHashidsGuardDiv      = 12.0
+	kHashidsMaxNumberSize = 12 // 单个数字编码后的最大长度（16 个字符的字母表编码 int64 所需的长度）
+)
+
+var (
+	ErrInvalidHashidsAlphabet = errors.New("snowflake: hashids alphabet must contain at least 16 unique characters without spaces")
+	ErrInvalidHashids         = errors.New("snowflake: invalid hashids")
+)
+
+// Hashids 使用 Hashids 算法对 id 进行混淆，生成的字符串不会直接暴露 id 的生成时间和数量。
+//
+// 编码结果与其它语言的 Hashids 实现兼容，只要使用相同的 salt、字母表和最小长度，即可在服务端还原出原始 id。
+type Hashids struct {
+	salt       []byte
+	alphabet   []byte
+	separators []byte
+	guards     []byte
+	minLength  int
+}
+
+// NewHashids 使用默认的字母表创建 Hashids，salt 用于打乱字母表，minLength 指定编码结果的最小长度
+func NewHashids(salt string, minLength int) (*Hashids, error) {
+	return NewHashidsWithAlphabet(salt, minLength, kHashidsAlphabet)
+}
+
+// NewHashidsWithAlphabet 使用自定义的字母表创建 Hashids
+func NewHashidsWithAlphabet(salt string, minLength int, alphabet string) (*Hashids, error) {
+	var unique = make([]byte, 0, len(alphabet))
+	for i := 0; i < len(alphabet); i++ {
+		if alphabet[i] == ' ' {
+			return nil, ErrInvalidHashidsAlphabet
+		}
+		if !containsByte(unique, alphabet[i]) {
+			unique = append(unique, alphabet[i])
+		}
+	}
+	if len(unique) < kHashidsMinAlphabet {
+		return nil, ErrInvalidHashidsAlphabet
+	}
+	if minLength < 0 {
+		minLength = 0
+	}
+
+	var h = &Hashids{}
+	h.salt = []byte(salt)
+	h.minLength = minLength
+
+	var chars = make([]byte, 0, len(unique))
+	var separators = make([]byte, 0, len(kHashidsSeparators))
+	for _, c := range unique {
+		if strings.IndexByte(kHashidsSeparators, c) >= 0 {
+			separators = append(separators, c)
+		} else {
+			chars = append(chars, c)
+		}
+	}
+	consistentShuffle(separators, h.salt)
+
+	if len(separators) == 0 || float64(len(chars))/float64(len(separators)) > kHashidsSeparatorDiv {
+		var sepLength = ceilDiv(len(chars), kHashidsSeparatorDiv)
+		if sepLength == 1 {
+			sepLength++
+		}
+		if sepLength > len(separators) {
+			var diff = sepLength - len(separators)
+			separators = append(separators, chars[:diff]...)
+			chars = chars[diff:]
+		} else {
+			separators = separators[:sepLength]
+		}
+	}
+	consistentShuffle(chars, h.salt)
+
+	var guardCount = ceilDiv(len(chars), kHashidsGuardDiv)
+	if len(chars) < 3 {
+		h.guards = separators[:guardCount]
+		separators = separators[guardCount:]
+	} else {
+		h.guards = chars[:guardCount]
+		chars = chars[guardCount:]
+	}
+	h.alphabet = chars
+	h.separators = separators
+	return h, nil
+}
+
+// Encode 获取 id 的 Hashids 编码，id 不能小于 0
+func (this *Hashids) Encode(id int64) (string, error) {
+	if id < 0 {
+		return "", ErrInvalidHashids
+	}
+
+	var alphabet = make([]byte, len(this.alphabet))
+	copy(alphabet, this.alphabet)
+
+	var numberHash = int(id % 100)
+	var lottery = alphabet[numberHash%len(alphabet)]
+
+	var result = make([]byte, 0, this.minLength+kHashidsMaxNumberSize+1)
+	result = append(result, lottery)
+
+	this.shuffleWithLottery(alphabet, lottery)
+	result = append(result, hashidsHash(id, alphabet)...)
+
+	if len(result) < this.minLength {
+		var guardIndex = (numberHash + int(result[0])) % len(this.guards)
+		result = append([]byte{this.guards[guardIndex]}, result...)
+
+		if len(result) < this.minLength {
+			guardIndex = (numberHash + int(result[2])) % len(this.guards)
+			result = append(result, this.guards[guardIndex])
+		}
+	}
+
+	var half = len(alphabet) / 2
+	for len(result) < this.minLength {
+		var salt = make([]byte, len(alphabet))
+		copy(salt, alphabet)
+		consistentShuffle(alphabet, salt)
+
+		var padded = make([]byte, 0, len(result)+len(alphabet))
+		padded = append(padded, alphabet[half:]...)
+		padded = append(padded, result...)
+		padded = append(padded, alphabet[:half]...)
+		result = padded
+
+		if excess := len(result) - this.minLength; excess > 0 {
+			result = result[excess/2 : excess/2+this.minLength]
+		}
+	}
+	return string(result), nil
+}
+
+// Decode 解析由 Encode 生成的字符串，还原出原始 id
+func (this *Hashids) Decode(s string) (int64, error) {
+	var parts = splitByBytes(s, this.guards)
+	var part = parts[0]
+	if len(parts) == 2 || len(parts) == 3 {
+		part = parts[1]
+	}
+	if len(part) < 2 || len(splitByBytes(part[1:], this.separators)) != 1 {
+		return 0, ErrInvalidHashids
+	}
+
+	var alphabet = make([]byte, len(this.alphabet))
+	copy(alphabet, this.alphabet)
+
+	var lottery = part[0]
+	this.shuffleWithLottery(alphabet, lottery)
+
+	var id, ok = hashidsUnhash(part[1:], alphabet)
+	if !ok {
+		return 0, ErrInvalidHashids
+	}
+
+	// 重新编码进行校验，避免不同的字符串解析出相同的 id
+	if encoded, err := this.Encode(id); err != nil || encoded != s {
+		return 0, ErrInvalidHashids
+	}
+	return id, nil
+}
+
+func (this *Hashids) shuffleWithLottery(alphabet []byte, lottery byte) {
+	var buffer = make([]byte, 0, 1+len(this.salt)+len(alphabet))
+	buffer = append(buffer, lottery)
+	buffer = append(buffer, this.salt...)
+	buffer = append(buffer, alphabet...)
+	consistentShuffle(alphabet, buffer[:len(alphabet)])
+}
+
+func hashidsHash(n int64, alphabet []byte) []byte {
+	var size = int64(len(alphabet))
+	var buf [64]byte
+	var i = len(buf)
+	for {
+		i--
+		buf[i] = alphabet[n%size]
+		n /= size
+		if n == 0 {
+			break
+		}
+	}
+	return buf[i:]
+}
+
+func hashidsUnhash(s string, alphabet []byte) (int64, bool) {
+	var size = int64(len(alphabet))
+	var n int64
+	for i := 0; i < len(s); i++ {
+		var pos = int64(bytes.IndexByte(alphabet, s[i]))
+		if pos < 0 {
+			return 0, false
+		}
+		if n > (1<<63-1-pos)/size {
+			return 0, false
+		}
+		n = n*size + pos
+	}
+	return n, true
+}
+
+func consistentShuffle(alphabet []byte, salt []byte) {
+	if len(salt) == 0 {
+		return
+	}
+	for i, v, p := len(alphabet)-1, 0, 0; i > 0; i, v = i-1, v+1 {
+		v %= len(salt)
+		var integer = int(salt[v])
+		p += integer
+		var j = (integer + v + p) % i
+		alphabet[i], alphabet[j] = alphabet[j], alphabet[i]
+	}
+}
+
+// splitByBytes 使用 seps 中的任意字符分割字符串，与 Hashids 参考实现一致，会保留空字符串
+func splitByBytes(s string, seps []byte) []string {
+	var parts []string
+	var start = 0
+	for i := 0; i < len(s); i++ {
+		if containsByte(seps, s[i]) {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func containsByte(b []byte, c byte) bool {
+	for _, v := range b {
+		if v == c {
+			return true
+		}
+	}
+	return false
+}
+
+func ceilDiv(n int, d float64) int {
+	var v = float64(n) / d
+	var i = int(v)
+	if float64(i) < v {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,44 @@
+package snowflake
+
+import (
+	"testing"
+)
+
+func TestHashids_Encode(t *testing.T) {
+	var tests = []struct {
+		salt      string
+		minLength int
+		id        int64
+		want      string
+	}{
+		{"this is my salt", 0, 12345, "NkK9"},
+		{"this is my salt", 8, 1, "gB0NV05e"},
+	}
+	for _, test := range tests {
+		var h, err = NewHashids(test.salt, test.minLength)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _ := h.Encode(test.id); got != test.want {
+			t.Fatalf("Encode(%d) = %q, want %q", test.id, got, test.want)
+		}
+	}
+}
+
+func TestHashids_Decode(t *testing.T) {
+	var h, _ = NewHashids("snowflake", 12)
+	for i := 0; i < 1000; i++ {
+		var id = Next()
+		var s, _ = h.Encode(id)
+		if got, err := h.Decode(s); err != nil || got != id {
+			t.Fatalf("Decode(%q) = %d, %v, want %d", s, got, err, id)
+		}
+	}
+
+	var bad = []string{"", "a", "NkK9NkK9", "!!!!!!!!!!!!"}
+	for _, s := range bad {
+		if _, err := h.Decode(s); err != ErrInvalidHashids {
+			t.Fatalf("Decode(%q) error = %v, want %v", s, err, ErrInvalidHashids)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package snowflake
+
+import (
+	"errors"
+	"sync"
+)
+
+var (
+	ErrInvalidMaxLo  = errors.New("snowflake: hilo max lo must be greater than 0")
+	ErrInvalidHi     = errors.New("snowflake: hilo store returned an invalid hi")
+	ErrHiLoExhausted = errors.New("snowflake: hilo id space exhausted")
+)
+
+// HiStore 为 HiLo 分配 hi 值，每次调用都需要返回一个新的、没有分配过的非负数，通常由数据库序列或者中心化的服务实现
+type HiStore interface {
+	NextHi() (int64, error)
+}
+
+// HiStoreFunc 将普通函数转换为 HiStore
+type HiStoreFunc func() (int64, error)
+
+func (f HiStoreFunc) NextHi() (int64, error) {
+	return f()
+}
+
+// HiLo 使用 Hi/Lo 算法生成 id，每次从 HiStore 获取一个 hi 值，然后在本地依次使用 lo 值，生成的 id 为 hi * maxLo + lo。
+//
+// 与 SnowFlake 相比，HiLo 生成的 id 不是按时间排序的，但是本地生成的速度非常快，而且同一批次的 id 是连续的，有利于数据库的聚簇索引。
+type HiLo struct {
+	mu    sync.Mutex
+	store HiStore
+	maxLo int64
+	hi    int64
+	lo    int64
+}
+
+// NewHiLo 创建 HiLo，maxLo 为每个 hi 值可以生成的 id 数量
+func NewHiLo(store HiStore, maxLo int64) (*HiLo, error) {
+	if maxLo <= 0 {
+		return nil, ErrInvalidMaxLo
+	}
+	var h = &HiLo{}
+	h.store = store
+	h.maxLo = maxLo
+	h.lo = maxLo
+	return h, nil
+}
+
+// NextID 生成新的 id，当前 hi 值的 lo 用完之后会从 HiStore 获取新的 hi 值
+func (this *HiLo) NextID() (int64, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if this.lo >= this.maxLo {
+		var hi, err = this.store.NextHi()
+		if err != nil {
+			return 0, err
+		}
+		if hi < 0 {
+			return 0, ErrInvalidHi
+		}
+		// 需要保证这一批次最大的 id，即 hi * maxLo + maxLo - 1 不会溢出
+		if hi > (1<<63-1-(this.maxLo-1))/this.maxLo {
+			return 0, ErrHiLoExhausted
+		}
+		this.hi = hi
+		this.lo = 0
+	}
+
+	var id = this.hi*this.maxLo + this.lo
+	this.lo++
+	return id, nil
+}
@@ -0,0 +1,69 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHiLo(t *testing.T) {
+	var calls int64
+	var h, err = NewHiLo(HiStoreFunc(func() (int64, error) {
+		calls++
+		return calls, nil
+	}), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var g Generator = h
+	for i := int64(0); i < 250; i++ {
+		var id, err = g.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := (i/100+1)*100 + i%100; id != want {
+			t.Fatalf("NextID() = %d, want %d", id, want)
+		}
+	}
+	if calls != 3 {
+		t.Fatalf("NextHi called %d times, want 3", calls)
+	}
+}
+
+func TestHiLo_StoreError(t *testing.T) {
+	var storeErr = errors.New("store unavailable")
+	var h, _ = NewHiLo(HiStoreFunc(func() (int64, error) {
+		return 0, storeErr
+	}), 10)
+	if _, err := h.NextID(); err != storeErr {
+		t.Fatalf("NextID() error = %v, want %v", err, storeErr)
+	}
+
+	h, _ = NewHiLo(HiStoreFunc(func() (int64, error) {
+		return 1 << 62, nil
+	}), 10)
+	if _, err := h.NextID(); err != ErrHiLoExhausted {
+		t.Fatalf("NextID() error = %v, want %v", err, ErrHiLoExhausted)
+	}
+
+	// hi * maxLo 没有溢出，但是这一批次后面的 lo 会溢出
+	h, _ = NewHiLo(HiStoreFunc(func() (int64, error) {
+		return (1<<63 - 1) / 10, nil
+	}), 10)
+	if _, err := h.NextID(); err != ErrHiLoExhausted {
+		t.Fatalf("NextID() error = %v, want %v", err, ErrHiLoExhausted)
+	}
+
+	h, _ = NewHiLo(HiStoreFunc(func() (int64, error) {
+		return (1<<63-1)/10 - 1, nil
+	}), 10)
+	for i := 0; i < 10; i++ {
+		if id, err := h.NextID(); err != nil || id < 0 {
+			t.Fatalf("NextID() = %d, %v, want non-negative id", id, err)
+		}
+	}
+
+	if _, err := NewHiLo(nil, 0); err != ErrInvalidMaxLo {
+		t.Fatalf("NewHiLo() error = %v, want %v", err, ErrInvalidMaxLo)
+	}
+}
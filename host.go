@@ -0,0 +1,63 @@
+package snowflake
+
+import (
+	"hash/fnv"
+	"log/slog"
+	"net"
+	"os"
+)
+
+// WithHostWorkerID 使用主机名和 IP 地址的哈希值作为工作节点标识，哈希值会按照最终的布局截取数据中心和机器标识占用的位数。
+//
+// 主机数量较多时不同的主机仍然可能得到相同的标识，只适合用于没有显式配置时的默认值，生产环境应该明确地设置工作节点标识。
+// 与 WithWorkerID 一样，同时使用 WithDataCenter、WithMachine 时以该选项为准，之后通过 WithWorkerID 设置的标识会覆盖该选项。
+func WithHostWorkerID() Option {
+	return optionFunc(func(s *SnowFlake) error {
+		s.worker = int64(mix64(hostHash()) >> 1)
+		s.hasWorker = true
+		s.hostWorker = true
+		return nil
+	})
+}
+
+// hostHash 计算主机名和第一个非回环 IP 地址的哈希值
+func hostHash() uint64 {
+	var h = fnv.New64a()
+	var hostname, _ = os.Hostname()
+	h.Write([]byte(hostname))
+	if ip := hostIP(); ip != nil {
+		h.Write(ip)
+	}
+	return h.Sum64()
+}
+
+func hostIP() net.IP {
+	var addrs, err = net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && !ipNet.IP.IsLoopback() && !ipNet.IP.IsLinkLocalUnicast() {
+			return ipNet.IP
+		}
+	}
+	return nil
+}
+
+// newDefault 创建默认生成器，工作节点标识由主机名和 IP 地址计算得到，并通过 slog 的默认日志记录选择的标识
+func newDefault() (*SnowFlake, error) {
+	var s, err = New(WithHostWorkerID())
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("snowflake: default generator uses a worker id derived from the host, call Init to configure it explicitly",
+		slog.Int64("data_center", s.DataCenterID()),
+		slog.Int64("machine", s.MachineID()),
+	)
+	return s, nil
+}
+
+// Default 获取包级别的函数使用的默认生成器，可以通过 DataCenterID、MachineID 查看默认生成器使用的标识
+func Default() *SnowFlake {
+	return getDefault()
+}
@@ -0,0 +1,35 @@
+package snowflake
+
+import (
+	"testing"
+)
+
+func TestWithHostWorkerID(t *testing.T) {
+	var a, err = New(WithHostWorkerID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b, _ = New(WithHostWorkerID())
+	if a.WorkerID() != b.WorkerID() {
+		t.Fatalf("WorkerID() = %d, %d, want the same id on the same host", a.WorkerID(), b.WorkerID())
+	}
+	var want = int64(mix64(hostHash())>>1) & 1023
+	if a.WorkerID() != want {
+		t.Fatalf("WorkerID() = %d, want %d", a.WorkerID(), want)
+	}
+
+	// 按照最终的布局截取
+	var small, _ = New(WithHostWorkerID(), WithLayout(Layout{DataCenter: 1, Machine: 2, Sequence: 12}))
+	if small.WorkerID() != want&7 {
+		t.Fatalf("WorkerID() = %d, want %d", small.WorkerID(), want&7)
+	}
+
+	var explicit, _ = New(WithHostWorkerID(), WithWorkerID(5))
+	if explicit.WorkerID() != 5 {
+		t.Fatalf("WorkerID() = %d, want 5", explicit.WorkerID())
+	}
+
+	if Default().WorkerID() != want {
+		t.Fatalf("Default().WorkerID() = %d, want %d", Default().WorkerID(), want)
+	}
+}
@@ -0,0 +1,113 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidID = errors.New("snowflake: invalid id")
+)
+
+const (
+	kIDBinarySize = 8 // 二进制形式的长度
+)
+
+// ID snowflake id，用于在需要特定序列化方式的地方替代 int64
+type ID int64
+
+// String 实现 fmt.Stringer 接口，返回十进制形式的 id
+func (this ID) String() string {
+	return strconv.FormatInt(int64(this), 10)
+}
+
+// Format 实现 fmt.Formatter 接口，支持以下格式，宽度等标记与 int64 的用法一致：
+//
+//	%d、%v 十进制
+//	%x、%X 十六进制
+//	%s     base62 编码
+//	%q     带引号的十进制
+//	%+v    各组成部分的可读描述，格式与 Explain 相同，按照 DefaultLayout 和时间偏移量 0 解析，不会使用默认生成器
+//	%#v    snowflake.ID(146559593487814656)
+func (this ID) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if f.Flag('+') {
+			io.WriteString(f, explain(DefaultLayout, DefaultLayout.Decode(time.Time{}, int64(this))))
+			return
+		}
+		if f.Flag('#') {
+			fmt.Fprintf(f, "snowflake.ID(%d)", int64(this))
+			return
+		}
+		fmt.Fprintf(f, fmt.FormatString(f, 'd'), int64(this))
+	case 'd', 'x', 'X', 'o', 'O', 'b':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), int64(this))
+	case 's':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), Base62(int64(this)))
+	case 'q':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), this.String())
+	default:
+		fmt.Fprintf(f, "%%!%c(snowflake.ID=%d)", verb, int64(this))
+	}
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler 接口，使用固定 8 个字节的大端序表示 id，
+// 二进制形式的字节序与 id 的大小顺序一致，可以直接作为 BoltDB、Badger 等 KV 存储中的定长 key。
+func (this ID) MarshalBinary() ([]byte, error) {
+	var b = make([]byte, kIDBinarySize)
+	binary.BigEndian.PutUint64(b, uint64(this))
+	return b, nil
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler 接口，data 需要是 MarshalBinary 返回的 8 个字节
+func (this *ID) UnmarshalBinary(data []byte) error {
+	if len(data) != kIDBinarySize || data[0]&0x80 != 0 {
+		return ErrInvalidID
+	}
+	*this = ID(binary.BigEndian.Uint64(data))
+	return nil
+}
+
+// GobEncode 实现 gob.GobEncoder 接口，与 MarshalBinary 相同
+func (this ID) GobEncode() ([]byte, error) {
+	return this.MarshalBinary()
+}
+
+// GobDecode 实现 gob.GobDecoder 接口，与 UnmarshalBinary 相同
+func (this *ID) GobDecode(data []byte) error {
+	return this.UnmarshalBinary(data)
+}
+
+// MarshalGQL 实现 gqlgen 的 graphql.Marshaler 接口，使用字符串表示 id，避免 JavaScript 等客户端丢失精度
+func (this ID) MarshalGQL(w io.Writer) {
+	io.WriteString(w, strconv.Quote(strconv.FormatInt(int64(this), 10)))
+}
+
+// UnmarshalGQL 实现 gqlgen 的 graphql.Unmarshaler 接口，支持字符串和整数形式的 id
+func (this *ID) UnmarshalGQL(v interface{}) error {
+	var id int64
+	var err error
+	switch value := v.(type) {
+	case string:
+		id, err = strconv.ParseInt(value, 10, 64)
+	case json.Number:
+		id, err = value.Int64()
+	case int:
+		id = int64(value)
+	case int64:
+		id = value
+	default:
+		return ErrInvalidID
+	}
+	if err != nil || id < 0 {
+		return ErrInvalidID
+	}
+	*this = ID(id)
+	return nil
+}
@@ -0,0 +1,109 @@
+package snowflake
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestID_GQL(t *testing.T) {
+	var buf bytes.Buffer
+	ID(146559593487814656).MarshalGQL(&buf)
+	if buf.String() != `"146559593487814656"` {
+		t.Fatalf("MarshalGQL() = %s", buf.String())
+	}
+
+	var inputs = []interface{}{"146559593487814656", json.Number("146559593487814656"), int64(146559593487814656), int(146559593487814656)}
+	for _, v := range inputs {
+		var id ID
+		if err := id.UnmarshalGQL(v); err != nil || id != 146559593487814656 {
+			t.Fatalf("UnmarshalGQL(%#v) = %d, %v", v, id, err)
+		}
+	}
+
+	var invalid = []interface{}{"", "abc", "-1", "9223372036854775808", 1.5, nil, int64(-1)}
+	for _, v := range invalid {
+		var id ID
+		if err := id.UnmarshalGQL(v); err != ErrInvalidID {
+			t.Fatalf("UnmarshalGQL(%#v) error = %v, want %v", v, err, ErrInvalidID)
+		}
+	}
+}
+
+func TestID_Binary(t *testing.T) {
+	var s, _ = New()
+	var a, b = ID(s.Next()), ID(s.Next())
+
+	var da, _ = a.MarshalBinary()
+	var db, _ = b.MarshalBinary()
+	if len(da) != 8 || bytes.Compare(da, db) >= 0 {
+		t.Fatalf("MarshalBinary() = %x, %x, want 8 bytes in id order", da, db)
+	}
+
+	var got ID
+	if err := got.UnmarshalBinary(da); err != nil || got != a {
+		t.Fatalf("UnmarshalBinary(%x) = %d, %v, want %d", da, got, err, a)
+	}
+	var invalid = [][]byte{nil, da[:7], append(da, 0), {0x80, 0, 0, 0, 0, 0, 0, 1}}
+	for _, data := range invalid {
+		if err := got.UnmarshalBinary(data); err != ErrInvalidID {
+			t.Fatalf("UnmarshalBinary(%x) error = %v, want %v", data, err, ErrInvalidID)
+		}
+	}
+}
+
+func TestID_Gob(t *testing.T) {
+	type message struct {
+		ID   ID
+		Name string
+	}
+
+	var buf bytes.Buffer
+	var in = message{ID: 146559593487814656, Name: "order"}
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+	var out message
+	if err := gob.NewDecoder(&buf).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Fatalf("Decode() = %+v, want %+v", out, in)
+	}
+}
+
+func TestID_Format(t *testing.T) {
+	ResetDefault(t)
+	var s, _ = New()
+	var id = ID(s.Next())
+	var tests = []struct {
+		format string
+		want   string
+	}{
+		{"%d", strconv.FormatInt(int64(id), 10)},
+		{"%v", strconv.FormatInt(int64(id), 10)},
+		{"%x", strconv.FormatInt(int64(id), 16)},
+		{"%s", Base62(int64(id))},
+		{"%q", strconv.Quote(strconv.FormatInt(int64(id), 10))},
+		{"%+v", s.Explain(int64(id))},
+		{"%#v", "snowflake.ID(" + strconv.FormatInt(int64(id), 10) + ")"},
+		{"%25d", fmt.Sprintf("%25d", int64(id))},
+		{"%-14s|", fmt.Sprintf("%-14s|", Base62(int64(id)))},
+		{"%t", "%!t(snowflake.ID=" + strconv.FormatInt(int64(id), 10) + ")"},
+	}
+	for _, test := range tests {
+		if got := fmt.Sprintf(test.format, id); got != test.want {
+			t.Fatalf("Sprintf(%q) = %q, want %q", test.format, got, test.want)
+		}
+	}
+	// 输出 id 不会创建默认生成器
+	if loadedDefault() != nil {
+		t.Fatalf("Sprintf() created the default generator")
+	}
+	if id.String() != strconv.FormatInt(int64(id), 10) || fmt.Sprint(id) != id.String() {
+		t.Fatalf("String() = %q, Sprint() = %q", id.String(), fmt.Sprint(id))
+	}
+}
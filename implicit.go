@@ -0,0 +1,30 @@
+package snowflake
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+var (
+	ErrNotInitialized = errors.New("snowflake: default generator is not initialized, call Init first")
+)
+
+// requireInit 为 1 时不会隐式地创建默认生成器
+var requireInit = kRequireInit
+
+// RequireInit 禁止隐式地创建默认生成器，之后在调用 Init 之前使用 Next 等包级别的函数会 panic，NextID 会返回 ErrNotInitialized，
+// 用于避免生产环境意外地使用由主机计算得到的默认标识。
+//
+// 也可以在编译时使用 -tags snowflake_requireinit 启用，不需要修改代码。已经创建了默认生成器时不会有影响。
+func RequireInit() {
+	atomic.StoreInt32(&requireInit, 1)
+}
+
+// NextID 使用默认生成器生成新的 id，无法生成 id 时返回具体的错误
+func NextID() (int64, error) {
+	var s, err = loadDefault()
+	if err != nil {
+		return 0, err
+	}
+	return s.NextID()
+}
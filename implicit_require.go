@@ -0,0 +1,6 @@
+//go:build snowflake_requireinit
+// +build snowflake_requireinit
+
+package snowflake
+
+const kRequireInit int32 = 1
@@ -0,0 +1,30 @@
+package snowflake
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestRequireInit(t *testing.T) {
+	ResetDefault(t)
+	RequireInit()
+	defer atomic.StoreInt32(&requireInit, kRequireInit)
+
+	if _, err := NextID(); err != ErrNotInitialized {
+		t.Fatalf("NextID() error = %v, want %v", err, ErrNotInitialized)
+	}
+	func() {
+		defer func() {
+			if r := recover(); r != ErrNotInitialized {
+				t.Fatalf("Next() panic = %v, want %v", r, ErrNotInitialized)
+			}
+		}()
+		Next()
+	}()
+
+	MustInit(WithMachine(4))
+	var id, err = NextID()
+	if err != nil || Default().Decode(id).Machine != 4 {
+		t.Fatalf("NextID() = %d, %v", id, err)
+	}
+}
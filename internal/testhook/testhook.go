@@ -0,0 +1,5 @@
+// Package testhook 保存 snowflake 提供给 snowflaketest 使用的内部函数，避免在 snowflake 中导出只用于测试的函数
+package testhook
+
+// ResetDefault 清除默认生成器，返回恢复原来的默认生成器的函数，由 snowflake 在初始化时设置
+var ResetDefault func() (restore func())
@@ -0,0 +1,55 @@
+package snowflake
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+)
+
+// WithSequenceJitter 设置序列号每次增加一个随机的奇数步长，而不是加 1，同一毫秒内连续生成的 id 不再相邻，
+// 避免通过相邻的 id 推算出其它 id 或者业务量。
+//
+// 步长在创建生成器时随机选择，与序列号的数量（2 的幂）互质，一毫秒内仍然可以使用全部的序列号，不会重复。
+// 序列号按照步长取模之后不再单调增加，同一毫秒内的 id 不再递增，只有不同毫秒的 id 保持递增，与 WithRandomSequenceStart 相同。
+// 使用之后 NextBlock 返回 ErrBlockNotSupported，通常与 WithRandomSequenceStart 一起使用。
+func WithSequenceJitter() Option {
+	return optionFunc(func(s *SnowFlake) error {
+		var seed [8]byte
+		if _, err := crand.Read(seed[:]); err != nil {
+			return err
+		}
+		// 在 initStride 中按照最终的布局截取
+		s.jitter = binary.BigEndian.Uint64(seed[:]) | 1
+		return nil
+	})
+}
+
+// initStride 使用最终的布局计算序列号的步长以及步长的模逆元
+func (this *SnowFlake) initStride() {
+	this.stride = 1
+	this.strideInverse = 1
+	if this.jitter == 0 {
+		return
+	}
+	var max = uint64(this.bits.sequence.max)
+	var stride = this.jitter & max
+	if stride <= 1 && max >= 3 {
+		// 步长为 1 时与不使用 jitter 相同
+		stride = 3
+	}
+	if stride == 0 {
+		stride = 1
+	}
+	// 奇数在模 2^64 下的逆元，使用牛顿迭代，每次迭代正确的位数翻倍
+	var inverse = stride
+	for i := 0; i < 5; i++ {
+		inverse *= 2 - stride*inverse
+	}
+	this.stride = int64(stride)
+	this.strideInverse = int64(inverse)
+}
+
+// exhaustSequence 将当前毫秒的序列号设置为已经用完，调用方需要持有 mu
+func (this *SnowFlake) exhaustSequence() {
+	this.sequenceStart = 0
+	this.sequence = -this.stride & this.bits.sequence.max
+}
@@ -0,0 +1,59 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithSequenceJitter(t *testing.T) {
+	var s, _ = New(WithSequenceJitter(), WithRandomSequenceStart(), WithMaxWait(10*time.Millisecond))
+	if s.stride <= 1 || s.stride&1 == 0 || s.stride*s.strideInverse&s.bits.sequence.max != 1 {
+		t.Fatalf("stride = %d, inverse = %d", s.stride, s.strideInverse)
+	}
+	var now = s.anchor
+	s.clock = func() time.Time { return now }
+
+	// 一毫秒内可以使用全部的序列号，并且连续的 id 不相邻
+	var seen = make(map[int64]bool)
+	var last int64 = -1
+	for i := int64(0); i <= kMaxSequence; i++ {
+		var id, err = s.NextID()
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		var seq = Sequence(id)
+		if seen[seq] || last >= 0 && (seq-last == 1 || last-seq == 1) {
+			t.Fatalf("%d: sequence %d after %d", i, seq, last)
+		}
+		seen[seq] = true
+		last = seq
+	}
+	if info := s.Debug(); info.Utilization != 1 {
+		t.Fatalf("utilization = %v", info.Utilization)
+	}
+	if _, err := s.NextID(); !errors.Is(err, ErrWaitTimeout) {
+		t.Fatalf("NextID() after the sequence is used up: %v", err)
+	}
+	if _, err := s.NextBlock(10); err != ErrBlockNotSupported {
+		t.Fatalf("NextBlock() error = %v, want %v", err, ErrBlockNotSupported)
+	}
+}
+
+func TestWithSequenceJitter_ImportState(t *testing.T) {
+	var old, _ = New()
+	var last = old.TimeOf(old.Next())
+	var data, _ = old.ExportState()
+
+	// 步长不同时导入的这一毫秒不再使用
+	var s, _ = New(WithSequenceJitter())
+	if err := s.ImportState(data); err != nil {
+		t.Fatal(err)
+	}
+	if s.sequenceUsed() != kMaxSequence+1 {
+		t.Fatalf("sequence used after import = %d", s.sequenceUsed())
+	}
+	if id := s.Next(); !s.TimeOf(id).After(last) {
+		t.Fatalf("id %d reuses the imported millisecond", id)
+	}
+}
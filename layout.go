@@ -0,0 +1,349 @@
+package snowflake
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	kMinTimeHorizon int64 = 365 * 24 * 3600 * 1000 // 创建生成器时，时间戳部分至少还需要能够使用的时长（毫秒）
+)
+
+var (
+	ErrTimeBitsTooSmall  = errors.New("snowflake: time bits of the layout can't hold the time since the epoch for at least one year, use WithTimeOffset to set a later epoch")
+	ErrTimeOverflow      = errors.New("snowflake: epoch exhausted, time since the epoch overflows the time bits of the layout")
+	ErrEpochExhausted    = ErrTimeOverflow // 与 ErrTimeOverflow 相同
+	ErrEpochInFuture     = errors.New("snowflake: epoch is later than the current time")
+	ErrInvalidLayout     = errors.New("snowflake: invalid layout, sequence needs at least 1 bit, at least 1 bit must be left for time and the order must list every part with bits once")
+	ErrRegionNotAllowed  = errors.New("snowflake: region out of range")
+	ErrVersionNotAllowed = errors.New("snowflake: version out of range")
+)
+
+// Part id 的组成部分
+type Part uint8
+
+const (
+	PartShard Part = iota + 1
+	PartVersion
+	PartTime
+	PartEntity
+	PartRegion
+	PartDataCenter
+	PartMachine
+	PartSequence
+	PartBoot
+)
+
+// kDefaultOrder 默认的组成部分顺序，从高位到低位
+var kDefaultOrder = []Part{PartShard, PartVersion, PartTime, PartEntity, PartRegion, PartDataCenter, PartMachine, PartBoot, PartSequence}
+
+// Layout 描述 id 中各组成部分占用的位数，时间戳占用剩余的位数。
+//
+// 默认从高位到低位依次为：分片、版本、时间戳、实体类型、区域、数据中心、机器标识、启动次数、序列号，可以通过 Order 调整。
+type Layout struct {
+	Shard      uint8 // 分片占用的位数
+	Version    uint8 // 版本占用的位数
+	Entity     uint8 // 实体类型占用的位数
+	Region     uint8 // 区域占用的位数
+	DataCenter uint8 // 数据中心占用的位数
+	Machine    uint8 // 机器标识占用的位数
+	Boot       uint8 // 启动次数占用的位数
+	Sequence   uint8 // 序列号占用的位数
+
+	// Order 从高位到低位排列的组成部分，为空时使用默认的顺序。需要包含时间戳以及所有占用了位数的部分，如兼容序列号在机器标识之前的旧生成器：
+	//
+	//	Layout{DataCenter: 5, Machine: 5, Sequence: 12, Order: []Part{PartTime, PartSequence, PartDataCenter, PartMachine}}
+	//
+	// 序列号不在最低位时 NextBlock 无法返回连续的 id，会返回 ErrBlockNotSupported。
+	Order []Part
+}
+
+// DefaultLayout 默认的布局：41 位时间戳、5 位数据中心、5 位机器标识和 12 位序列号
+var DefaultLayout = Layout{
+	DataCenter: kDataCenterBits,
+	Machine:    kMachineBits,
+	Sequence:   kSequenceBits,
+}
+
+// Time 获取时间戳占用的位数
+func (l Layout) Time() uint8 {
+	var used = int(l.Shard) + int(l.Version) + int(l.Entity) + int(l.Region) + int(l.DataCenter) + int(l.Machine) + int(l.Boot) + int(l.Sequence)
+	if used >= int(kIDBits) {
+		return 0
+	}
+	return kIDBits - uint8(used)
+}
+
+func (l Layout) valid() bool {
+	if l.Sequence == 0 || l.Time() == 0 {
+		return false
+	}
+	if len(l.Order) == 0 {
+		return true
+	}
+
+	var seen = make(map[Part]bool, len(l.Order))
+	for _, p := range l.Order {
+		if p < PartShard || p > PartBoot || seen[p] {
+			return false
+		}
+		seen[p] = true
+	}
+	for _, p := range kDefaultOrder {
+		if !seen[p] && (p == PartTime || l.partBits(p) > 0) {
+			return false
+		}
+	}
+	return true
+}
+
+// order 获取从高位到低位排列的组成部分
+func (l Layout) order() []Part {
+	if len(l.Order) == 0 {
+		return kDefaultOrder
+	}
+	return l.Order
+}
+
+// partBits 获取组成部分占用的位数
+func (l Layout) partBits(p Part) uint8 {
+	switch p {
+	case PartShard:
+		return l.Shard
+	case PartVersion:
+		return l.Version
+	case PartTime:
+		return l.Time()
+	case PartEntity:
+		return l.Entity
+	case PartRegion:
+		return l.Region
+	case PartDataCenter:
+		return l.DataCenter
+	case PartMachine:
+		return l.Machine
+	case PartBoot:
+		return l.Boot
+	case PartSequence:
+		return l.Sequence
+	}
+	return 0
+}
+
+// field 描述 id 中的一个组成部分
+type field struct {
+	shift uint8
+	max   int64
+}
+
+func newField(shift, bits uint8) field {
+	return field{shift: shift, max: -1 ^ (-1 << bits)}
+}
+
+func (f field) get(s int64) int64 {
+	return s >> f.shift & f.max
+}
+
+func (f field) put(v int64) int64 {
+	return (v & f.max) << f.shift
+}
+
+func (f field) allow(v int64) bool {
+	return v >= 0 && v <= f.max
+}
+
+// layoutBits 由 Layout 计算出的各组成部分的偏移量和最大值
+type layoutBits struct {
+	shard      field
+	version    field
+	time       field
+	entity     field
+	region     field
+	dataCenter field
+	machine    field
+	boot       field
+	process    field // 通过 WithProcessSlots 从序列号中划分出的进程槽位
+	sequence   field
+}
+
+func (l Layout) bits() layoutBits {
+	var b layoutBits
+	var shift uint8
+	var order = l.order()
+	for i := len(order) - 1; i >= 0; i-- {
+		var bits = l.partBits(order[i])
+		*b.field(order[i]) = newField(shift, bits)
+		shift += bits
+	}
+	return b
+}
+
+func (b *layoutBits) field(p Part) *field {
+	switch p {
+	case PartShard:
+		return &b.shard
+	case PartVersion:
+		return &b.version
+	case PartTime:
+		return &b.time
+	case PartEntity:
+		return &b.entity
+	case PartRegion:
+		return &b.region
+	case PartDataCenter:
+		return &b.dataCenter
+	case PartMachine:
+		return &b.machine
+	case PartBoot:
+		return &b.boot
+	}
+	return &b.sequence
+}
+
+// WithLayout 设置 id 的布局
+func WithLayout(l Layout) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		if !l.valid() {
+			return ErrInvalidLayout
+		}
+		l.Order = append([]Part(nil), l.Order...)
+		s.layout = l
+		s.bits = l.bits()
+		return nil
+	})
+}
+
+// WithEntityBits 设置实体类型占用的位数，这部分位数会从时间戳中扣除，需要配合 NextFor 使用
+func WithEntityBits(bits uint8) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		var l = s.layout
+		l.Entity = bits
+		return WithLayout(l).Apply(s)
+	})
+}
+
+// WithRegionBits 设置区域占用的位数，这部分位数会从时间戳中扣除，需要配合 WithRegion 使用
+func WithRegionBits(bits uint8) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		var l = s.layout
+		l.Region = bits
+		return WithLayout(l).Apply(s)
+	})
+}
+
+// WithRegion 设置区域标识，用于区分跨地域部署的生成器，需要先通过 WithRegionBits 为区域预留位数
+func WithRegion(region int64) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		if !s.bits.region.allow(region) {
+			return rangeError("region", region, s.bits.region, ErrRegionNotAllowed)
+		}
+		s.region = region
+		return nil
+	})
+}
+
+// WithVersionBits 设置版本占用的位数，通常 1 到 2 位即可，这部分位数会从时间戳中扣除。
+//
+// 版本位于 id 的最高位，当以后调整时间偏移量或者布局时，可以通过版本区分新旧 id，需要配合 WithVersion 使用。
+func WithVersionBits(bits uint8) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		var l = s.layout
+		l.Version = bits
+		return WithLayout(l).Apply(s)
+	})
+}
+
+// WithShardBits 设置分片占用的位数，这部分位数会从时间戳中扣除，作用与 TiDB 的 AUTO_RANDOM 类似。
+//
+// 分片位于 id 的最高位，值由 id 的时间戳计算得到，同一毫秒内生成的 id 使用同一个分片，不同毫秒生成的 id 分散在不同的分片中，
+// 可以避免 TiDB 等使用聚簇索引的存储在写入时出现热点。设置之后 id 不再按照生成的时间排序，需要排序时可以使用 Parts 中的时间。
+func WithShardBits(bits uint8) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		var l = s.layout
+		l.Shard = bits
+		return WithLayout(l).Apply(s)
+	})
+}
+
+// WithVersion 设置布局版本，需要先通过 WithVersionBits 为版本预留位数
+func WithVersion(version int64) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		if !s.bits.version.allow(version) {
+			return rangeError("version", version, s.bits.version, ErrVersionNotAllowed)
+		}
+		s.version = version
+		return nil
+	})
+}
+
+// Parts id 的各组成部分
+type Parts struct {
+	ID         int64     `json:"id"`          // id
+	Shard      int64     `json:"shard"`       // 分片
+	Version    int64     `json:"version"`     // 布局版本
+	Timestamp  int64     `json:"timestamp"`   // 时间戳部分，单位是 millisecond，相对于时间偏移量
+	Time       time.Time `json:"time"`        // id 的生成时间
+	Entity     int64     `json:"entity"`      // 实体类型
+	Region     int64     `json:"region"`      // 区域标识
+	DataCenter int64     `json:"data_center"` // 数据中心标识
+	Machine    int64     `json:"machine"`     // 机器标识
+	Boot       int64     `json:"boot"`        // 启动次数
+	Sequence   int64     `json:"sequence"`    // 序列号
+}
+
+// Decode 按照生成器的布局和时间偏移量解析 id
+func (this *SnowFlake) Decode(s int64) Parts {
+	var p = this.bits.decode(this.timeOffset, s)
+	if this.timeCipher != nil {
+		p.Timestamp = this.timestampOf(s)
+		p.Time = millisecondToTime(p.Timestamp + this.timeOffset)
+	}
+	return p
+}
+
+// Decode 按照布局和时间偏移量解析 id，用于没有对应生成器的场景，如解析其它服务生成的 id
+func (l Layout) Decode(epoch time.Time, s int64) Parts {
+	var offset int64
+	if !epoch.IsZero() {
+		offset = epoch.UnixNano() / 1e6
+	}
+	return l.bits().decode(offset, s)
+}
+
+// ParseWithEpoch 按照默认的布局和 epochMillis 指定的时间偏移量（毫秒时间戳）解析 id，
+// 用于解析其它部署使用了不同时间偏移量的生成器生成的 id，不需要为每一种时间偏移量创建生成器，其它布局需要使用 Layout.Decode
+func ParseWithEpoch(epochMillis, id int64) Parts {
+	return DefaultLayout.bits().decode(epochMillis, id)
+}
+
+func (b layoutBits) decode(offset, s int64) Parts {
+	var p Parts
+	p.ID = s
+	p.Shard = b.shard.get(s)
+	p.Version = b.version.get(s)
+	p.Timestamp = b.time.get(s)
+	p.Time = millisecondToTime(p.Timestamp + offset)
+	p.Entity = b.entity.get(s)
+	p.Region = b.region.get(s)
+	p.DataCenter = b.dataCenter.get(s)
+	p.Machine = b.machine.get(s)
+	p.Boot = b.boot.get(s)
+	p.Sequence = b.sequence.get(s)
+	return p
+}
+
+// Layout 获取生成器使用的布局
+func (this *SnowFlake) Layout() Layout {
+	var l = this.layout
+	l.Order = append([]Part(nil), l.Order...)
+	return l
+}
+
+// Epoch 获取生成器的时间偏移量，即时间戳部分为 0 时对应的时间
+func (this *SnowFlake) Epoch() time.Time {
+	return millisecondToTime(this.timeOffset)
+}
+
+func millisecondToTime(mill int64) time.Time {
+	return time.Unix(mill/1e3, (mill%1e3)*1e6)
+}
@@ -0,0 +1,238 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var testEpoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// assertRecent 检查 id 解析出来的时间是否为刚刚生成的时间，用于发现时间戳溢出到其它部分的问题
+func assertRecent(t *testing.T, s *SnowFlake, id int64) {
+	t.Helper()
+	if tm := s.TimeOf(id); time.Since(tm) < 0 || time.Since(tm) > time.Second {
+		t.Fatalf("TimeOf(%d) = %v, want about %v", id, tm, time.Now())
+	}
+}
+
+func TestSnowFlake_NextFor(t *testing.T) {
+	var s, err = New(WithTimeOffset(testEpoch), WithEntityBits(3), WithDataCenter(2), WithMachine(9))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for entity := int64(0); entity < 8; entity++ {
+		var id = s.NextFor(entity)
+		var p = s.Decode(id)
+		if p.Entity != entity || p.DataCenter != 2 || p.Machine != 9 {
+			t.Fatalf("Decode(NextFor(%d)) = %+v", entity, p)
+		}
+		assertRecent(t, s, id)
+	}
+
+	if id := s.NextFor(8); id != -1 {
+		t.Fatalf("NextFor(8) = %d, want -1", id)
+	}
+	if layout := s.layout; layout.Time() != 38 {
+		t.Fatalf("Time() = %d, want 38", layout.Time())
+	}
+}
+
+func TestWithLayout(t *testing.T) {
+	if _, err := New(WithLayout(Layout{Machine: 10, Sequence: 12}), WithMachine(1000)); err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := New(WithLayout(Layout{Machine: 10}), WithMachine(1000)); err != ErrInvalidLayout {
+		t.Fatalf("New() error = %v, want %v", err, ErrInvalidLayout)
+	}
+	if _, err := New(WithLayout(Layout{Machine: 40, Sequence: 23})); err != ErrInvalidLayout {
+		t.Fatalf("New() error = %v, want %v", err, ErrInvalidLayout)
+	}
+	if _, err := New(WithMachine(1000)); !errors.Is(err, ErrWorkerNotAllowed) {
+		t.Fatalf("New() error = %v, want %v", err, ErrWorkerNotAllowed)
+	}
+}
+
+func TestNew_Validate(t *testing.T) {
+	var tests = []struct {
+		opts []Option
+		want error
+	}{
+		{[]Option{WithDataCenter(31), WithLayout(Layout{DataCenter: 1, Machine: 5, Sequence: 12})}, ErrDataCenterNotAllowed},
+		{[]Option{WithMachine(31), WithLayout(Layout{DataCenter: 5, Machine: 2, Sequence: 12})}, ErrWorkerNotAllowed},
+		{[]Option{WithTimeOffset(testEpoch), WithRegionBits(3), WithRegion(7), WithRegionBits(2)}, ErrRegionNotAllowed},
+		{[]Option{WithTimeOffset(testEpoch), WithVersionBits(2), WithVersion(3), WithVersionBits(1)}, ErrVersionNotAllowed},
+		{[]Option{WithEntityBits(3)}, ErrTimeBitsTooSmall},
+		{[]Option{WithVersionBits(2), WithVersion(1)}, ErrTimeBitsTooSmall},
+	}
+	for i, test := range tests {
+		if _, err := New(test.opts...); !errors.Is(err, test.want) {
+			t.Fatalf("%d: New() error = %v, want %v", i, err, test.want)
+		}
+	}
+}
+
+func TestNew_ValidateAll(t *testing.T) {
+	// 缩小布局之后数据中心和机器标识都超出了范围
+	var _, err = New(WithTimeOffset(testEpoch), WithDataCenter(20), WithMachine(20), WithLayout(Layout{DataCenter: 3, Machine: 3, Sequence: 12}))
+	if !errors.Is(err, ErrDataCenterNotAllowed) || !errors.Is(err, ErrWorkerNotAllowed) {
+		t.Fatalf("New() error = %v, want both %v and %v", err, ErrDataCenterNotAllowed, ErrWorkerNotAllowed)
+	}
+}
+
+func TestSnowFlake_TimeOverflow(t *testing.T) {
+	var s, _ = New(WithTimeOffset(testEpoch), WithEntityBits(3))
+	// 模拟时间戳部分已经用完
+	s.timeOffset = 0
+
+	if id := s.Next(); id != -1 {
+		t.Fatalf("Next() = %d, want -1", id)
+	}
+	if _, err := s.NextID(); err != ErrTimeOverflow {
+		t.Fatalf("NextID() error = %v, want %v", err, ErrTimeOverflow)
+	}
+	if _, err := s.NextBlock(10); err != ErrTimeOverflow {
+		t.Fatalf("NextBlock() error = %v, want %v", err, ErrTimeOverflow)
+	}
+}
+
+func TestWithRegion(t *testing.T) {
+	var s, err = New(WithTimeOffset(testEpoch), WithRegionBits(3), WithRegion(5), WithDataCenter(31), WithMachine(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var id = s.Next()
+	var p = s.Decode(id)
+	if p.Region != 5 || p.DataCenter != 31 || p.Machine != 1 {
+		t.Fatalf("Decode() = %+v", p)
+	}
+	assertRecent(t, s, id)
+
+	if _, err = New(WithRegion(1)); !errors.Is(err, ErrRegionNotAllowed) {
+		t.Fatalf("New() error = %v, want %v", err, ErrRegionNotAllowed)
+	}
+	if _, err = New(WithRegionBits(3), WithRegion(8)); !errors.Is(err, ErrRegionNotAllowed) {
+		t.Fatalf("New() error = %v, want %v", err, ErrRegionNotAllowed)
+	}
+}
+
+func TestWithVersion(t *testing.T) {
+	var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var v1, _ = New(WithTimeOffset(epoch), WithVersionBits(2), WithVersion(1))
+	var v2, _ = New(WithTimeOffset(epoch), WithVersionBits(2), WithVersion(2))
+
+	var id1, id2 = v1.Next(), v2.Next()
+	if id1 < 0 || id2 < 0 {
+		t.Fatalf("Next() = %d, %d, want non-negative", id1, id2)
+	}
+	assertRecent(t, v1, id1)
+	assertRecent(t, v2, id2)
+	if p := v2.Decode(id1); p.Version != 1 {
+		t.Fatalf("Decode(%d).Version = %d, want 1", id1, p.Version)
+	}
+	if p := v1.Decode(id2); p.Version != 2 {
+		t.Fatalf("Decode(%d).Version = %d, want 2", id2, p.Version)
+	}
+	if id1 >= id2 {
+		t.Fatalf("version 1 id %d should sort before version 2 id %d", id1, id2)
+	}
+
+	if _, err := New(WithVersionBits(1), WithVersion(2)); !errors.Is(err, ErrVersionNotAllowed) {
+		t.Fatalf("New() error = %v, want %v", err, ErrVersionNotAllowed)
+	}
+}
+
+func TestLayout_Decode(t *testing.T) {
+	var s, _ = New(WithTimeOffset(testEpoch), WithRegionBits(3), WithRegion(5), WithMachine(7))
+	var id = s.Next()
+
+	var p = s.Layout().Decode(s.Epoch(), id)
+	if p != s.Decode(id) {
+		t.Fatalf("Layout().Decode() = %+v, want %+v", p, s.Decode(id))
+	}
+	if !s.Epoch().Equal(testEpoch) || p.Region != 5 || p.Machine != 7 {
+		t.Fatalf("Epoch() = %v, Decode() = %+v", s.Epoch(), p)
+	}
+}
+
+func TestWithShardBits(t *testing.T) {
+	var s, err = New(WithTimeOffset(testEpoch), WithShardBits(4), WithMachine(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var shards = make(map[int64]bool)
+	var last int64
+	for i := 0; i < 200; i++ {
+		var id = s.Next()
+		var p = s.Decode(id)
+		if p.Machine != 3 || p.Shard != shardOf(p.Timestamp+s.timeOffset)&15 {
+			t.Fatalf("Decode(%d) = %+v", id, p)
+		}
+		assertRecent(t, s, id)
+		shards[p.Shard] = true
+		if p.Timestamp != last {
+			last = p.Timestamp
+			time.Sleep(time.Millisecond)
+		}
+	}
+	if len(shards) < 8 {
+		t.Fatalf("ids only used %d of 16 shards", len(shards))
+	}
+
+	// 同一段 id 使用同一个分片，仍然是连续的
+	var block, _ = s.NextBlock(10)
+	var first = s.Decode(block.Start)
+	var end = s.Decode(block.Start + block.Count - 1)
+	if first.Shard != end.Shard || end.Sequence != first.Sequence+block.Count-1 {
+		t.Fatalf("block %+v decodes to %+v and %+v", block, first, end)
+	}
+}
+
+func TestLayout_Order(t *testing.T) {
+	var legacy = Layout{DataCenter: 5, Machine: 5, Sequence: 12, Order: []Part{PartTime, PartSequence, PartDataCenter, PartMachine}}
+	var s, err = New(WithTimeOffset(testEpoch), WithLayout(legacy), WithDataCenter(3), WithMachine(17))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var a, b = s.Next(), s.Next()
+	var pa, pb = s.Decode(a), s.Decode(b)
+	if pa.DataCenter != 3 || pa.Machine != 17 || pb.DataCenter != 3 || pb.Machine != 17 {
+		t.Fatalf("Decode() = %+v, %+v", pa, pb)
+	}
+	assertRecent(t, s, a)
+	if a&(1<<10-1) != 3<<5|17 {
+		t.Fatalf("id %d should end with the worker bits", a)
+	}
+	if a >= b {
+		t.Fatalf("ids %d, %d are not increasing", a, b)
+	}
+	if _, err = s.NextBlock(10); err != ErrBlockNotSupported {
+		t.Fatalf("NextBlock() error = %v, want %v", err, ErrBlockNotSupported)
+	}
+
+	var invalid = []Layout{
+		{Machine: 5, Sequence: 12, Order: []Part{PartTime, PartSequence}},
+		{Machine: 5, Sequence: 12, Order: []Part{PartSequence, PartMachine}},
+		{Machine: 5, Sequence: 12, Order: []Part{PartTime, PartSequence, PartMachine, PartSequence}},
+		{Machine: 5, Sequence: 12, Order: []Part{PartTime, PartSequence, PartMachine, Part(0)}},
+	}
+	for i, l := range invalid {
+		if _, err = New(WithLayout(l)); err != ErrInvalidLayout {
+			t.Fatalf("%d: New() error = %v, want %v", i, err, ErrInvalidLayout)
+		}
+	}
+}
+
+func TestParseWithEpoch(t *testing.T) {
+	var s, _ = New(WithTimeOffset(testEpoch), WithDataCenter(4), WithMachine(21))
+	var id = s.Next()
+	var p = ParseWithEpoch(testEpoch.UnixNano()/1e6, id)
+	if p != s.Decode(id) || p.DataCenter != 4 || p.Machine != 21 {
+		t.Fatalf("ParseWithEpoch() = %+v, want %+v", p, s.Decode(id))
+	}
+	assertRecent(t, s, id)
+}
@@ -0,0 +1,57 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var (
+	ErrMachineLocked    = errors.New("snowflake: machine id is already used by another process")
+	ErrLockNotSupported = errors.New("snowflake: exclusive machine lock is not supported on this platform")
+)
+
+// WithExclusiveMachineLock 创建生成器时在 dir 目录下为区域、数据中心和机器标识加文件锁，
+// 同一台主机上的另一个进程已经使用了相同的标识时返回 ErrMachineLocked，避免多个服务复制了同一份配置导致生成重复的 id。
+//
+// 文件锁在进程退出或者调用 Close 之后释放，进程崩溃时由操作系统释放，不会残留。
+// 该文件锁不会与 WithProcessSlots 的槽位文件锁互斥，同一个机器标识只能使用其中一种方式。
+func WithExclusiveMachineLock(dir string) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		s.lockDir = dir
+		return nil
+	})
+}
+
+// lockMachine 使用最终的标识加文件锁
+func (this *SnowFlake) lockMachine() error {
+	if this.lockDir == "" {
+		return nil
+	}
+	var path = filepath.Join(this.lockDir, fmt.Sprintf("snowflake-r%d-dc%d-m%d.lock", this.region, this.dataCenter, this.machine))
+	var file, err = os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	if err = lockFile(file); err != nil {
+		file.Close()
+		return err
+	}
+	this.locks = append(this.locks, file)
+	return nil
+}
+
+// Close 释放通过 WithExclusiveMachineLock 和 WithProcessSlots 获取的文件锁，释放之后不应该再使用该生成器
+func (this *SnowFlake) Close() error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	var errs []error
+	for _, file := range this.locks {
+		if err := file.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	this.locks = nil
+	return errors.Join(errs...)
+}
@@ -0,0 +1,12 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package snowflake
+
+import (
+	"os"
+)
+
+func lockFile(file *os.File) error {
+	return ErrLockNotSupported
+}
@@ -0,0 +1,57 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package snowflake
+
+import (
+	"testing"
+)
+
+func TestWithExclusiveMachineLock(t *testing.T) {
+	var dir = t.TempDir()
+	var a, err = New(WithExclusiveMachineLock(dir), WithMachine(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = New(WithExclusiveMachineLock(dir), WithMachine(3)); err != ErrMachineLocked {
+		t.Fatalf("New() error = %v, want %v", err, ErrMachineLocked)
+	}
+
+	// 使用最终的标识
+	var b *SnowFlake
+	if b, err = New(WithMachine(3), WithExclusiveMachineLock(dir), WithWorkerID(4)); err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	if err = a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	var c *SnowFlake
+	if c, err = New(WithExclusiveMachineLock(dir), WithMachine(3)); err != nil {
+		t.Fatalf("New() error = %v after the lock was released", err)
+	}
+	c.Close()
+}
+
+func TestManager_RemoveReleasesLock(t *testing.T) {
+	var dir = t.TempDir()
+	var m = NewManager(nil)
+	m.Register("tenant", WithExclusiveMachineLock(dir), WithMachine(6))
+	if _, err := m.Get("tenant"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(WithExclusiveMachineLock(dir), WithMachine(6)); err != ErrMachineLocked {
+		t.Fatalf("New() error = %v, want %v", err, ErrMachineLocked)
+	}
+
+	// 移除之后文件锁被释放，可以再次获取
+	if err := m.Remove("tenant"); err != nil {
+		t.Fatal(err)
+	}
+	var s, err = New(WithExclusiveMachineLock(dir), WithMachine(6))
+	if err != nil {
+		t.Fatalf("New() after Remove error = %v", err)
+	}
+	s.Close()
+}
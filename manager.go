@@ -0,0 +1,166 @@
+package snowflake
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrGeneratorExists   = errors.New("snowflake: generator already exists")
+	ErrGeneratorNotFound = errors.New("snowflake: generator not found")
+)
+
+// Manager 管理多个命名的生成器，如：每个租户或者每个服务使用一个生成器，各个生成器可以使用不同的机器标识和时间偏移量。
+//
+// 生成器会在第一次使用的时候才创建。
+type Manager struct {
+	mu         sync.RWMutex
+	generators map[string]*SnowFlake
+	options    map[string][]Option
+	factory    func(name string) ([]Option, error)
+
+	quotas       map[string]*quotaState
+	defaultQuota Quota
+	now          func() time.Time
+}
+
+// NewManager 创建 Manager，factory 用于为没有通过 Register 注册的名称提供创建生成器的参数，可以为 nil
+func NewManager(factory func(name string) ([]Option, error)) *Manager {
+	var m = &Manager{}
+	m.generators = make(map[string]*SnowFlake)
+	m.options = make(map[string][]Option)
+	m.factory = factory
+	m.quotas = make(map[string]*quotaState)
+	m.now = time.Now
+	return m
+}
+
+// Register 注册生成器的参数，生成器会在第一次使用的时候创建
+func (this *Manager) Register(name string, opts ...Option) error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if _, ok := this.options[name]; ok {
+		return ErrGeneratorExists
+	}
+	if _, ok := this.generators[name]; ok {
+		return ErrGeneratorExists
+	}
+	this.options[name] = opts
+	return nil
+}
+
+// Get 获取指定名称的生成器，生成器不存在的时候会创建
+func (this *Manager) Get(name string) (*SnowFlake, error) {
+	this.mu.RLock()
+	var s = this.generators[name]
+	this.mu.RUnlock()
+
+	if s != nil {
+		return s, nil
+	}
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if s = this.generators[name]; s != nil {
+		return s, nil
+	}
+
+	var opts, ok = this.options[name]
+	if !ok {
+		if this.factory == nil {
+			return nil, ErrGeneratorNotFound
+		}
+		var err error
+		if opts, err = this.factory(name); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if s, err = New(opts...); err != nil {
+		return nil, err
+	}
+	this.generators[name] = s
+	return s, nil
+}
+
+// Next 使用指定名称的生成器生成 id，超出配额时返回 *QuotaExceededError
+func (this *Manager) Next(name string) (int64, error) {
+	var s, err = this.Get(name)
+	if err != nil {
+		return 0, err
+	}
+	var now = this.now()
+	var quota *quotaState
+	if quota, err = this.takeQuota(name, now); err != nil {
+		return 0, err
+	}
+
+	var id int64
+	if id, err = s.NextID(); err != nil {
+		// 没有生成 id，归还占用的配额
+		if quota != nil {
+			quota.giveBack(now)
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// Remove 移除指定名称的生成器及其参数，并关闭已经创建的生成器，释放其获取的文件锁，返回 Close 的错误
+func (this *Manager) Remove(name string) error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	var s = this.generators[name]
+	delete(this.generators, name)
+	delete(this.options, name)
+	delete(this.quotas, name)
+	if s != nil {
+		return s.Close()
+	}
+	return nil
+}
+
+// Names 获取已经创建的生成器名称
+func (this *Manager) Names() []string {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+
+	var names = make([]string, 0, len(this.generators))
+	for name := range this.generators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ManagerStats Manager 的统计信息
+type ManagerStats struct {
+	Total      Stats            // 所有生成器的统计信息之和
+	Generators map[string]Stats // 各个生成器的统计信息
+	Rejected   map[string]int64 // 各个生成器因为超出配额被拒绝的次数
+}
+
+// Stats 获取所有已经创建的生成器的统计信息
+func (this *Manager) Stats() ManagerStats {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+
+	var stats = ManagerStats{}
+	stats.Generators = make(map[string]Stats, len(this.generators))
+	for name, s := range this.generators {
+		var item = s.Stats()
+		stats.Generators[name] = item
+		stats.Total = stats.Total.add(item)
+	}
+	stats.Rejected = make(map[string]int64, len(this.quotas))
+	for name, state := range this.quotas {
+		stats.Rejected[name] = state.rejectedCount()
+	}
+	return stats
+}
@@ -0,0 +1,43 @@
+package snowflake
+
+import (
+	"testing"
+)
+
+func TestManager(t *testing.T) {
+	var m = NewManager(func(name string) ([]Option, error) {
+		if name == "unknown" {
+			return nil, ErrGeneratorNotFound
+		}
+		return []Option{WithMachine(2)}, nil
+	})
+	if err := m.Register("tenant-a", WithMachine(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Register("tenant-a"); err != ErrGeneratorExists {
+		t.Fatalf("Register() error = %v, want %v", err, ErrGeneratorExists)
+	}
+
+	for i := 0; i < 10; i++ {
+		var a, err = m.Next("tenant-a")
+		if err != nil || Machine(a) != 1 {
+			t.Fatalf("Next(tenant-a) = %d, %v", a, err)
+		}
+		var b, _ = m.Next("tenant-b")
+		if Machine(b) != 2 {
+			t.Fatalf("Next(tenant-b) = %d, want machine 2", b)
+		}
+	}
+
+	if _, err := m.Next("unknown"); err != ErrGeneratorNotFound {
+		t.Fatalf("Next(unknown) error = %v, want %v", err, ErrGeneratorNotFound)
+	}
+
+	var stats = m.Stats()
+	if stats.Total.Generated != 20 || stats.Generators["tenant-a"].Generated != 10 {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+	if names := m.Names(); len(names) != 2 || names[0] != "tenant-a" || names[1] != "tenant-b" {
+		t.Fatalf("Names() = %v", names)
+	}
+}
@@ -0,0 +1,36 @@
+package snowflake
+
+import (
+	"strconv"
+)
+
+const (
+	kMaskVisible     = 4
+	kMaskPlaceholder = "…"
+)
+
+// Mask 获取部分隐藏的 id，保留十进制形式的前 4 位和后 4 位，如 1465…4656，
+// 用于在面向用户的错误信息和公开的日志中展示 id，同时避免 id 被完整地获取。
+func Mask(s int64) string {
+	return MaskWith(s, kMaskVisible, kMaskVisible)
+}
+
+// MaskWith 获取部分隐藏的 id，保留十进制形式的前 prefix 位和后 suffix 位。
+//
+// id 的长度不超过 prefix+suffix 时只保留后 suffix 位，仍然不足时全部隐藏。
+func MaskWith(s int64, prefix, suffix int) string {
+	if prefix < 0 {
+		prefix = 0
+	}
+	if suffix < 0 {
+		suffix = 0
+	}
+	var text = strconv.FormatInt(s, 10)
+	if len(text) <= prefix+suffix {
+		prefix = 0
+	}
+	if len(text) <= suffix {
+		return kMaskPlaceholder
+	}
+	return text[:prefix] + kMaskPlaceholder + text[len(text)-suffix:]
+}
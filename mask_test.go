@@ -0,0 +1,28 @@
+package snowflake
+
+import (
+	"testing"
+)
+
+func TestMask(t *testing.T) {
+	var tests = []struct {
+		id     int64
+		prefix int
+		suffix int
+		want   string
+	}{
+		{1465371247374594656, 4, 4, "1465…4656"},
+		{1465371247374594656, 0, 6, "…594656"},
+		{1465371247374594656, 2, 0, "14…"},
+		{12345678, 4, 4, "…5678"},
+		{1234, 4, 4, "…"},
+	}
+	for _, test := range tests {
+		if got := MaskWith(test.id, test.prefix, test.suffix); got != test.want {
+			t.Fatalf("MaskWith(%d, %d, %d) = %q, want %q", test.id, test.prefix, test.suffix, got, test.want)
+		}
+	}
+	if got := Mask(1465371247374594656); got != "1465…4656" {
+		t.Fatalf("Mask() = %q", got)
+	}
+}
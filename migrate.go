@@ -0,0 +1,159 @@
+package snowflake
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrTimeBeforeEpoch    = errors.New("snowflake: time is before the epoch")
+	ErrSequenceNotAllowed = errors.New("snowflake: sequence out of range")
+	ErrMigrationOrder     = errors.New("snowflake: old ids must be migrated in increasing order")
+)
+
+// ComposeAt 使用生成器的布局、时间偏移量和工作节点标识，以 t 作为时间戳组成 id，用于为历史数据生成 id。
+//
+// ComposeAt 不会检查 id 是否已经被生成器使用过，同一个时间戳需要使用不同的 sequence，通常使用 Migrator 按顺序分配。
+func (this *SnowFlake) ComposeAt(t time.Time, sequence int64) (int64, error) {
+	var millisecond = t.UnixNano() / 1e6
+	if millisecond < this.timeOffset {
+		return 0, ErrTimeBeforeEpoch
+	}
+	if !this.bits.time.allow(millisecond - this.timeOffset) {
+		return 0, ErrTimeOverflow
+	}
+	if !this.bits.sequence.allow(sequence) {
+		return 0, rangeError("sequence", sequence, this.bits.sequence, ErrSequenceNotAllowed)
+	}
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	return this.compose(millisecond, 0, sequence), nil
+}
+
+// MigrationStats Migrator 的统计信息
+type MigrationStats struct {
+	Migrated int64 // 已经分配的 id 数量
+	Clamped  int64 // created_at 早于前一行，沿用前一行的时间戳的行数
+	Shifted  int64 // 同一毫秒内的行数超过了序列号的数量，顺延到下一毫秒的行数
+}
+
+// Migrator 为使用自增主键的历史数据按照 created_at 分配 id，并将旧 id 与新 id 的对应关系以 CSV 的格式（old_id,new_id）写入映射表。
+//
+// 需要按照旧 id 递增的顺序调用 Map，分配的新 id 同样是递增的，created_at 早于前一行时沿用前一行的时间戳，保证新旧 id 的顺序一致。
+// 迁移使用的生成器需要使用单独的工作节点标识，避免与在线的生成器在相同的时间戳上生成重复的 id；使用了分片时新 id 不保证递增。
+type Migrator struct {
+	generator   *SnowFlake
+	mapping     *csv.Writer
+	buffer      *bufio.Writer
+	started     bool
+	lastOld     int64
+	millisecond int64
+	sequence    int64
+	stats       MigrationStats
+}
+
+// NewMigrator 创建 Migrator，mapping 为写入映射表的目标，为 nil 时不写入映射表
+func NewMigrator(generator *SnowFlake, mapping io.Writer) *Migrator {
+	var m = &Migrator{}
+	m.generator = generator
+	if mapping != nil {
+		m.buffer = bufio.NewWriter(mapping)
+		m.mapping = csv.NewWriter(m.buffer)
+	}
+	return m
+}
+
+// Map 为旧 id 分配新 id，createdAt 为该行原有的创建时间
+func (this *Migrator) Map(oldID int64, createdAt time.Time) (int64, error) {
+	if this.started && oldID <= this.lastOld {
+		return 0, fmt.Errorf("%w: %d after %d", ErrMigrationOrder, oldID, this.lastOld)
+	}
+
+	var millisecond = createdAt.UnixNano() / 1e6
+	var sequence int64
+	switch {
+	case !this.started || millisecond > this.millisecond:
+	case millisecond < this.millisecond:
+		this.stats.Clamped++
+		millisecond = this.millisecond
+		sequence = this.sequence + 1
+	default:
+		sequence = this.sequence + 1
+	}
+	if sequence > this.generator.bits.sequence.max {
+		this.stats.Shifted++
+		millisecond++
+		sequence = 0
+	}
+
+	var id, err = this.generator.ComposeAt(millisecondToTime(millisecond), sequence)
+	if err != nil {
+		return 0, err
+	}
+	if this.mapping != nil {
+		if err = this.mapping.Write([]string{strconv.FormatInt(oldID, 10), strconv.FormatInt(id, 10)}); err != nil {
+			return 0, err
+		}
+	}
+
+	this.started = true
+	this.lastOld = oldID
+	this.millisecond = millisecond
+	this.sequence = sequence
+	this.stats.Migrated++
+	return id, nil
+}
+
+// Flush 将映射表缓存的内容写入目标
+func (this *Migrator) Flush() error {
+	if this.mapping == nil {
+		return nil
+	}
+	this.mapping.Flush()
+	if err := this.mapping.Error(); err != nil {
+		return err
+	}
+	return this.buffer.Flush()
+}
+
+// Stats 获取统计信息
+func (this *Migrator) Stats() MigrationStats {
+	return this.stats
+}
+
+// VerifyMapping 检查映射表中新 id 的顺序是否与旧 id 一致，返回检查的行数，顺序不一致时返回的错误包含所在的行
+func VerifyMapping(r io.Reader) (int64, error) {
+	var reader = csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.ReuseRecord = true
+
+	var lastOld, lastNew int64
+	var rows int64
+	for {
+		var record, err = reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return rows, err
+		}
+		var oldID, newID int64
+		if oldID, err = strconv.ParseInt(record[0], 10, 64); err != nil {
+			return rows, fmt.Errorf("snowflake: mapping line %d: %w", rows+1, err)
+		}
+		if newID, err = strconv.ParseInt(record[1], 10, 64); err != nil {
+			return rows, fmt.Errorf("snowflake: mapping line %d: %w", rows+1, err)
+		}
+		if rows > 0 && (oldID <= lastOld) != (newID <= lastNew) {
+			return rows, fmt.Errorf("%w: line %d maps %d to %d after %d to %d", ErrMigrationOrder, rows+1, oldID, newID, lastOld, lastNew)
+		}
+		lastOld, lastNew = oldID, newID
+		rows++
+	}
+}
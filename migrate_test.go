@@ -0,0 +1,99 @@
+package snowflake
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnowFlake_ComposeAt(t *testing.T) {
+	var s, _ = New(WithTimeOffset(testEpoch), WithDataCenter(2), WithMachine(9))
+	var at = testEpoch.Add(36 * time.Hour)
+
+	var id, err = s.ComposeAt(at, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := s.Decode(id); !p.Time.Equal(at) || p.Sequence != 7 || p.DataCenter != 2 || p.Machine != 9 {
+		t.Fatalf("Decode(ComposeAt()) = %+v", p)
+	}
+
+	if _, err = s.ComposeAt(testEpoch.Add(-time.Millisecond), 0); err != ErrTimeBeforeEpoch {
+		t.Fatalf("ComposeAt() error = %v, want %v", err, ErrTimeBeforeEpoch)
+	}
+	if _, err = s.ComposeAt(at, 4096); !errors.Is(err, ErrSequenceNotAllowed) {
+		t.Fatalf("ComposeAt() error = %v, want %v", err, ErrSequenceNotAllowed)
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	var s, _ = New(WithTimeOffset(testEpoch), WithMachine(30))
+	var mapping bytes.Buffer
+	var m = NewMigrator(s, &mapping)
+
+	var base = testEpoch.Add(time.Hour)
+	var rows = []struct {
+		old       int64
+		createdAt time.Time
+	}{
+		{1, base},
+		{2, base},
+		{5, base.Add(time.Second)},
+		{6, base.Add(time.Millisecond)}, // created_at 早于前一行
+		{9, base.Add(2 * time.Second)},
+	}
+	var ids []int64
+	for _, row := range rows {
+		var id, err = m.Map(row.old, row.createdAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+	if err := m.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("ids %v are not increasing", ids)
+		}
+	}
+	if p := s.Decode(ids[1]); !p.Time.Equal(base) || p.Sequence != 1 {
+		t.Fatalf("Decode(%d) = %+v", ids[1], p)
+	}
+	if p := s.Decode(ids[3]); !p.Time.Equal(base.Add(time.Second)) || p.Sequence != 1 {
+		t.Fatalf("Decode(%d) = %+v, want the clamped time", ids[3], p)
+	}
+	if stats := m.Stats(); stats.Migrated != 5 || stats.Clamped != 1 {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+
+	if _, err := m.Map(9, base); !errors.Is(err, ErrMigrationOrder) {
+		t.Fatalf("Map() error = %v, want %v", err, ErrMigrationOrder)
+	}
+
+	var n, err = VerifyMapping(bytes.NewReader(mapping.Bytes()))
+	if err != nil || n != 5 {
+		t.Fatalf("VerifyMapping() = %d, %v", n, err)
+	}
+	if _, err = VerifyMapping(strings.NewReader("1,100\n2,90\n")); !errors.Is(err, ErrMigrationOrder) {
+		t.Fatalf("VerifyMapping() error = %v, want %v", err, ErrMigrationOrder)
+	}
+}
+
+func TestMigrator_Shift(t *testing.T) {
+	var s, _ = New(WithTimeOffset(testEpoch), WithLayout(Layout{Machine: 5, Sequence: 1}))
+	var m = NewMigrator(s, nil)
+	var at = testEpoch.Add(time.Hour)
+	for old := int64(1); old <= 3; old++ {
+		if _, err := m.Map(old, at); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if stats := m.Stats(); stats.Shifted != 1 {
+		t.Fatalf("Stats() = %+v, want 1 shifted row", stats)
+	}
+}
@@ -0,0 +1,65 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+)
+
+var (
+	ErrInvalidObjectID = errors.New("snowflake: invalid object id")
+)
+
+// ObjectID 与 MongoDB ObjectID 格式兼容的 12 字节 id。
+//
+// 前 4 个字节与 MongoDB ObjectID 一样是大端序的秒级时间戳，后 8 个字节是大端序的 snowflake id，
+// 所以与 MongoDB 自己生成的 ObjectID 混合使用时仍然按时间排序，MongoDB 驱动中的 ObjectID 也可以直接通过 [12]byte 转换。
+type ObjectID [12]byte
+
+// ParseObjectID 解析 24 个字符的十六进制形式的 ObjectID
+func ParseObjectID(s string) (ObjectID, error) {
+	var oid ObjectID
+	if len(s) != 24 {
+		return oid, ErrInvalidObjectID
+	}
+	if _, err := hex.Decode(oid[:], []byte(s)); err != nil {
+		return oid, ErrInvalidObjectID
+	}
+	return oid, nil
+}
+
+// Hex 获取十六进制形式的 ObjectID
+func (this ObjectID) Hex() string {
+	return hex.EncodeToString(this[:])
+}
+
+func (this ObjectID) String() string {
+	return this.Hex()
+}
+
+// ObjectID 将 id 转换为 ObjectID，时间戳部分使用 id 的生成时间
+func (this *SnowFlake) ObjectID(id int64) ObjectID {
+	var oid ObjectID
+	binary.BigEndian.PutUint32(oid[:4], uint32(this.TimeOf(id).Unix()))
+	binary.BigEndian.PutUint64(oid[4:], uint64(id))
+	return oid
+}
+
+// IDFromObjectID 获取通过 ObjectID 转换的 ObjectID 中的 id，时间戳部分与 id 的生成时间不一致时（如由 MongoDB 生成的 ObjectID）返回 ErrInvalidObjectID
+func (this *SnowFlake) IDFromObjectID(oid ObjectID) (int64, error) {
+	var id = int64(binary.BigEndian.Uint64(oid[4:]))
+	if id < 0 || this.ObjectID(id) != oid {
+		return 0, ErrInvalidObjectID
+	}
+	return id, nil
+}
+
+// ObjectIDOf 将 id 转换为 ObjectID，使用默认生成器的时间偏移量
+func ObjectIDOf(id int64) ObjectID {
+	return getDecoder().ObjectID(id)
+}
+
+// IDFromObjectID 获取 ObjectID 中的 id，使用默认生成器的时间偏移量
+func IDFromObjectID(oid ObjectID) (int64, error) {
+	return getDecoder().IDFromObjectID(oid)
+}
@@ -0,0 +1,35 @@
+package snowflake
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSnowFlake_ObjectID(t *testing.T) {
+	var s, _ = New(WithTimeOffset(testEpoch))
+	var a, b = s.Next(), s.Next()
+
+	var oa, ob = s.ObjectID(a), s.ObjectID(b)
+	if bytes.Compare(oa[:], ob[:]) >= 0 {
+		t.Fatalf("ObjectID(%d) = %s should sort before ObjectID(%d) = %s", a, oa, b, ob)
+	}
+
+	parsed, err := ParseObjectID(oa.Hex())
+	if err != nil || parsed != oa {
+		t.Fatalf("ParseObjectID(%s) = %s, %v", oa.Hex(), parsed, err)
+	}
+	if id, err := s.IDFromObjectID(parsed); err != nil || id != a {
+		t.Fatalf("IDFromObjectID() = %d, %v, want %d", id, err, a)
+	}
+
+	// MongoDB 生成的 ObjectID
+	mongo, _ := ParseObjectID("507f1f77bcf86cd799439011")
+	if _, err = s.IDFromObjectID(mongo); err != ErrInvalidObjectID {
+		t.Fatalf("IDFromObjectID() error = %v, want %v", err, ErrInvalidObjectID)
+	}
+	for _, v := range []string{"", "507f1f77bcf86cd79943901", "507f1f77bcf86cd79943901g"} {
+		if _, err = ParseObjectID(v); err != ErrInvalidObjectID {
+			t.Fatalf("ParseObjectID(%q) error = %v, want %v", v, err, ErrInvalidObjectID)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package snowflake
+
+import (
+	"testing"
+)
+
+func TestOptimus(t *testing.T) {
+	var o = NewOptimusWithKey([]byte("snowflake"))
+	var ids = []int64{0, 1, 2, 1<<63 - 1}
+	for i := 0; i < 1000; i++ {
+		ids = append(ids, Next())
+	}
+
+	var seen = make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		var enc = o.Encode(id)
+		if enc < 0 {
+			t.Fatalf("Encode(%d) = %d, want non-negative", id, enc)
+		}
+		if dec := o.Decode(enc); dec != id {
+			t.Fatalf("Decode(%d) = %d, want %d", enc, dec, id)
+		}
+		seen[enc] = struct{}{}
+	}
+	if len(seen) != len(ids) {
+		t.Fatalf("Encode produced %d distinct values for %d ids", len(seen), len(ids))
+	}
+
+	if _, err := NewOptimus(2, 0); err != ErrInvalidOptimusPrime {
+		t.Fatalf("NewOptimus(2, 0) error = %v, want %v", err, ErrInvalidOptimusPrime)
+	}
+}
@@ -0,0 +1,110 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	kBase58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+)
+
+var (
+	ErrBadLength    = errors.New("snowflake: bad length")
+	ErrBadCharacter = errors.New("snowflake: bad character")
+	ErrOutOfRange   = errors.New("snowflake: out of range")
+
+	ErrInvalidDecimal = errors.New("snowflake: invalid decimal id")
+	ErrInvalidHex     = errors.New("snowflake: invalid hex id")
+	ErrInvalidBase58  = errors.New("snowflake: invalid base58 id")
+)
+
+var base58 = func() *Encoding {
+	var e, _ = NewEncoding(kBase58Alphabet)
+	e.name = "base58"
+	e.invalid = ErrInvalidBase58
+	return e
+}()
+
+// ParseError 解析字符串形式的 id 失败时返回的错误，可以通过 errors.Is 判断具体的原因，如 errors.Is(err, ErrBadCharacter)，
+// 也可以判断是哪一种编码的错误，如 errors.Is(err, ErrInvalidBase62)，便于 API 返回准确的 400 错误。
+type ParseError struct {
+	Encoding string // 编码的名称，如 decimal、hex、base62
+	Input    string // 解析的字符串
+	Offset   int    // Err 为 ErrBadCharacter 时为非法字符的位置，否则为 -1
+	Err      error  // ErrBadLength、ErrBadCharacter 或者 ErrOutOfRange
+
+	invalid error // 编码对应的错误，如 ErrInvalidBase62
+}
+
+func (this *ParseError) Error() string {
+	var reason = strings.TrimPrefix(this.Err.Error(), "snowflake: ")
+	if this.Offset >= 0 {
+		return fmt.Sprintf("snowflake: invalid %s id %q: %s at offset %d", this.Encoding, this.Input, reason, this.Offset)
+	}
+	return fmt.Sprintf("snowflake: invalid %s id %q: %s", this.Encoding, this.Input, reason)
+}
+
+func (this *ParseError) Unwrap() error {
+	return this.Err
+}
+
+func (this *ParseError) Is(target error) bool {
+	return this.invalid != nil && target == this.invalid
+}
+
+func parseError(encoding string, invalid error, input string, err error, offset int) error {
+	return &ParseError{Encoding: encoding, Input: input, Offset: offset, Err: err, invalid: invalid}
+}
+
+// Hex 获取 id 的小写十六进制形式，id 需要大于等于 0
+func Hex(s int64) string {
+	return strconv.FormatInt(s, 16)
+}
+
+// ParseHex 解析 Hex 生成的 id，只接受 1 到 16 个小写的十六进制字符，除了 0 以外不能以 0 开头
+func ParseHex(s string) (int64, error) {
+	if len(s) == 0 || len(s) > 16 {
+		return 0, parseError("hex", ErrInvalidHex, s, ErrBadLength, -1)
+	}
+	for i := 0; i < len(s); i++ {
+		var c = s[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') || (i == 0 && c == '0' && len(s) > 1) {
+			return 0, parseError("hex", ErrInvalidHex, s, ErrBadCharacter, i)
+		}
+	}
+	var n, err = strconv.ParseInt(s, 16, 64)
+	if err != nil {
+		return 0, parseError("hex", ErrInvalidHex, s, ErrOutOfRange, -1)
+	}
+	return n, nil
+}
+
+// ParseDecimal 解析十进制形式的 id，只接受 1 到 19 个数字，不能包含符号，除了 0 以外不能以 0 开头，补齐长度的形式需要使用 ParsePadded
+func ParseDecimal(s string) (int64, error) {
+	if len(s) == 0 || len(s) > kPaddedLength {
+		return 0, parseError("decimal", ErrInvalidDecimal, s, ErrBadLength, -1)
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' || (i == 0 && s[i] == '0' && len(s) > 1) {
+			return 0, parseError("decimal", ErrInvalidDecimal, s, ErrBadCharacter, i)
+		}
+	}
+	var n, err = strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, parseError("decimal", ErrInvalidDecimal, s, ErrOutOfRange, -1)
+	}
+	return n, nil
+}
+
+// Base58 获取 id 的 base58 编码，使用比特币的字母表
+func Base58(s int64) string {
+	return base58.Encode(s)
+}
+
+// ParseBase58 解析 Base58 生成的 id
+func ParseBase58(s string) (int64, error) {
+	return base58.Parse(s)
+}
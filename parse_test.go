@@ -0,0 +1,70 @@
+package snowflake
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func TestParse_Errors(t *testing.T) {
+	var tests = []struct {
+		parse   func(string) (int64, error)
+		input   string
+		invalid error
+		reason  error
+		offset  int
+	}{
+		{ParseDecimal, "", ErrInvalidDecimal, ErrBadLength, -1},
+		{ParseDecimal, "12345678901234567890", ErrInvalidDecimal, ErrBadLength, -1},
+		{ParseDecimal, "12a4", ErrInvalidDecimal, ErrBadCharacter, 2},
+		{ParseDecimal, "-12", ErrInvalidDecimal, ErrBadCharacter, 0},
+		{ParseDecimal, "012", ErrInvalidDecimal, ErrBadCharacter, 0},
+		{ParseDecimal, "9999999999999999999", ErrInvalidDecimal, ErrOutOfRange, -1},
+		{ParseHex, "", ErrInvalidHex, ErrBadLength, -1},
+		{ParseHex, "1234567890abcdef0", ErrInvalidHex, ErrBadLength, -1},
+		{ParseHex, "12AB", ErrInvalidHex, ErrBadCharacter, 2},
+		{ParseHex, "8000000000000000", ErrInvalidHex, ErrOutOfRange, -1},
+		{ParseBase62, "abc-", ErrInvalidBase62, ErrBadCharacter, 3},
+		{ParseBase62, "zzzzzzzzzzz", ErrInvalidBase62, ErrOutOfRange, -1},
+		{ParseBase58, "1I", ErrInvalidBase58, ErrBadCharacter, 1},
+		{ParseBase58, "", ErrInvalidBase58, ErrBadLength, -1},
+		{ParseBase58, "zzzzzzzzzzzz", ErrInvalidBase58, ErrOutOfRange, -1},
+		{ParseBase32, "0000000000000u", ErrInvalidBase32, ErrBadLength, -1},
+		{ParseBase32, "000000000u000", ErrInvalidBase32, ErrBadCharacter, 9},
+		{ParseBase32, "8000000000000", ErrInvalidBase32, ErrOutOfRange, -1},
+		{ParseBase64URL, "AAAAAAAAA+A", ErrInvalidBase64, ErrBadCharacter, 9},
+		{ParseBase64URL, "gAAAAAAAAAA", ErrInvalidBase64, ErrOutOfRange, -1},
+		{ParsePadded, "00000000000000004 2", ErrInvalidPadded, ErrBadCharacter, 17},
+	}
+	for _, test := range tests {
+		var _, err = test.parse(test.input)
+		var pe *ParseError
+		if !errors.As(err, &pe) || !errors.Is(err, test.invalid) || !errors.Is(err, test.reason) || pe.Offset != test.offset || pe.Input != test.input {
+			t.Fatalf("parse(%q) error = %#v, want %v and %v at %d", test.input, err, test.invalid, test.reason, test.offset)
+		}
+		if errors.Is(err, ErrInvalidLayout) {
+			t.Fatalf("parse(%q) error should not match unrelated errors", test.input)
+		}
+	}
+
+	var _, err = ParseDecimal("12a4")
+	if err.Error() != `snowflake: invalid decimal id "12a4": bad character at offset 2` {
+		t.Fatalf("Error() = %q", err.Error())
+	}
+}
+
+func TestParse_RoundTrip(t *testing.T) {
+	var ids = []int64{0, 1, 15, 16, 57, 58, 1<<63 - 1, Next()}
+	for _, id := range ids {
+		if got, err := ParseHex(Hex(id)); err != nil || got != id {
+			t.Fatalf("ParseHex(%q) = %d, %v, want %d", Hex(id), got, err, id)
+		}
+		if got, err := ParseBase58(Base58(id)); err != nil || got != id {
+			t.Fatalf("ParseBase58(%q) = %d, %v, want %d", Base58(id), got, err, id)
+		}
+		var decimal = strconv.FormatInt(id, 10)
+		if got, err := ParseDecimal(decimal); err != nil || got != id {
+			t.Fatalf("ParseDecimal(%q) = %d, %v, want %d", decimal, got, err, id)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package snowflake
+
+import (
+	"time"
+)
+
+// PartitionKey 按照 id 的哈希值计算分区，同一个 id 总是得到同一个分区，不同的 id 均匀分布在所有分区中。
+//
+// 适用于使用实体的 id 作为 Kafka 等消息队列的分区键，保证同一个实体的消息是有序的。partitions 小于等于 0 时返回 0。
+func PartitionKey(id int64, partitions int) int {
+	if partitions <= 0 {
+		return 0
+	}
+	return int(mix64(uint64(id)) % uint64(partitions))
+}
+
+// mix64 splitmix64 的最后一步，将 id 打散，避免 id 的低位（序列号）分布不均匀
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// PartitionKeyByMachine 按照生成 id 的数据中心和机器标识计算分区，同一个生成器生成的 id 总是在同一个分区
+func (this *SnowFlake) PartitionKeyByMachine(id int64, partitions int) int {
+	if partitions <= 0 {
+		return 0
+	}
+	return int(this.Worker(id) % int64(partitions))
+}
+
+// PartitionKeyByTime 按照 id 的生成时间计算分区，同一个时间段内生成的 id 在同一个分区，相邻的时间段依次使用下一个分区。
+//
+// bucket 为时间段的长度，小于等于 0 时为 1 毫秒。
+func (this *SnowFlake) PartitionKeyByTime(id int64, partitions int, bucket time.Duration) int {
+	if partitions <= 0 {
+		return 0
+	}
+	var size = int64(bucket / time.Millisecond)
+	if size <= 0 {
+		size = 1
+	}
+	var timestamp = this.timestampOf(id) + this.timeOffset
+	return int(timestamp / size % int64(partitions))
+}
+
+// PartitionKeyByMachine 按照生成 id 的数据中心和机器标识计算分区，使用默认生成器的布局
+func PartitionKeyByMachine(id int64, partitions int) int {
+	return getDecoder().PartitionKeyByMachine(id, partitions)
+}
+
+// PartitionKeyByTime 按照 id 的生成时间计算分区，使用默认生成器的布局和时间偏移量
+func PartitionKeyByTime(id int64, partitions int, bucket time.Duration) int {
+	return getDecoder().PartitionKeyByTime(id, partitions, bucket)
+}
@@ -0,0 +1,58 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPartitionKey(t *testing.T) {
+	var s, _ = New()
+	var counts = make([]int, 8)
+	for i := 0; i < 8000; i++ {
+		var id = s.Next()
+		var p = PartitionKey(id, 8)
+		if p != PartitionKey(id, 8) {
+			t.Fatalf("PartitionKey(%d) is not deterministic", id)
+		}
+		counts[p]++
+	}
+	for p, n := range counts {
+		if n < 800 || n > 1200 {
+			t.Fatalf("partition %d got %d of 8000 ids, counts = %v", p, n, counts)
+		}
+	}
+	if p := PartitionKey(1, 0); p != 0 {
+		t.Fatalf("PartitionKey(1, 0) = %d, want 0", p)
+	}
+}
+
+func TestSnowFlake_PartitionKeyByMachine(t *testing.T) {
+	var a, _ = New(WithDataCenter(1), WithMachine(2))
+	var b, _ = New(WithDataCenter(1), WithMachine(3))
+
+	var pa = a.PartitionKeyByMachine(a.Next(), 16)
+	for i := 0; i < 100; i++ {
+		if p := a.PartitionKeyByMachine(a.Next(), 16); p != pa {
+			t.Fatalf("PartitionKeyByMachine() = %d, want %d", p, pa)
+		}
+	}
+	if pa != (1<<5|2)%16 {
+		t.Fatalf("PartitionKeyByMachine() = %d, want %d", pa, (1<<5|2)%16)
+	}
+	if pb := a.PartitionKeyByMachine(b.Next(), 16); pb == pa {
+		t.Fatalf("ids of different machines got the same partition %d", pa)
+	}
+}
+
+func TestSnowFlake_PartitionKeyByTime(t *testing.T) {
+	var s, _ = New(WithTimeOffset(testEpoch))
+	// 时间戳为 1 小时 + 1 毫秒
+	var id = s.compose(s.timeOffset+3600*1000+1, 0, 0)
+	if p := s.PartitionKeyByTime(id, 24, time.Hour); p != int((testEpoch.Unix()/3600+1)%24) {
+		t.Fatalf("PartitionKeyByTime() = %d", p)
+	}
+	var next = s.compose(s.timeOffset+2*3600*1000, 0, 0)
+	if p, q := s.PartitionKeyByTime(id, 24, time.Hour), s.PartitionKeyByTime(next, 24, time.Hour); q != (p+1)%24 {
+		t.Fatalf("PartitionKeyByTime() = %d, %d, want adjacent partitions", p, q)
+	}
+}
@@ -0,0 +1,187 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	ErrDomainExists       = errors.New("snowflake: domain already registered")
+	ErrDomainNotFound     = errors.New("snowflake: domain not registered")
+	ErrWorkerRangeOverlap = errors.New("snowflake: worker range overlaps with another domain")
+	ErrInstanceOutOfRange = errors.New("snowflake: instance is out of the worker range of the domain")
+	ErrEntityNotAllowed   = errors.New("snowflake: entity out of range")
+)
+
+// Domain 业务域的 id 策略
+type Domain struct {
+	Name        string
+	FirstWorker int64 // 为业务域预留的工作节点标识的范围 [FirstWorker, LastWorker]，与 WithWorkerID 对应
+	LastWorker  int64
+	Entity      *int64   // 设置之后生成的 id 中包含实体类型，需要基础配置通过 WithEntityBits 预留位数
+	Options     []Option // 在基础配置之后生效的其它配置，如 WithRandomSequenceStart
+}
+
+// Pool 按照业务域管理生成器，集中管理每个业务域的工作节点标识范围和实体类型，如：
+//
+//	var pool = snowflake.NewPool(base, instance)
+//	pool.Register(snowflake.Domain{Name: "order", FirstWorker: 0, LastWorker: 31})
+//	pool.Register(snowflake.Domain{Name: "invoice", FirstWorker: 32, LastWorker: 63})
+//	id, err := pool.Next("order")
+//
+// 每个业务域的生成器通过 base 的配置派生，使用的工作节点标识为 FirstWorker + instance，instance 为当前进程的实例编号，
+// 如 StatefulSet 的序号。base 只作为配置的模板，不要直接使用 base 生成 id，其工作节点标识可能与业务域的范围重叠。
+//
+// 与 Manager 不同的是，Pool 的各个业务域使用相同的布局和时间偏移量，并且检查工作节点标识的范围是否重叠。
+type Pool struct {
+	mu         sync.RWMutex
+	base       *SnowFlake
+	instance   int64
+	domains    map[string]Domain
+	generators map[string]*DomainGenerator
+}
+
+// NewPool 创建 Pool，base 为基础配置，instance 为当前进程的实例编号
+func NewPool(base *SnowFlake, instance int64) *Pool {
+	var p = &Pool{}
+	p.base = base
+	p.instance = instance
+	p.domains = make(map[string]Domain)
+	p.generators = make(map[string]*DomainGenerator)
+	return p
+}
+
+// Register 注册业务域，生成器会在第一次使用的时候创建。
+//
+// 工作节点标识的范围与已经注册的业务域重叠时返回 ErrWorkerRangeOverlap，实体类型超出范围时返回 ErrEntityNotAllowed。
+func (this *Pool) Register(d Domain) error {
+	var max = this.base.bits.dataCenter.max<<this.base.layout.Machine | this.base.bits.machine.max
+	if d.FirstWorker < 0 || d.FirstWorker > d.LastWorker || d.LastWorker > max {
+		return &RangeError{Field: "worker id", Got: d.LastWorker, Max: max, Err: ErrWorkerIDNotAllowed}
+	}
+	if d.Entity != nil && !this.base.bits.entity.allow(*d.Entity) {
+		return rangeError("entity", *d.Entity, this.base.bits.entity, ErrEntityNotAllowed)
+	}
+	d.Options = append([]Option(nil), d.Options...)
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if _, ok := this.domains[d.Name]; ok {
+		return ErrDomainExists
+	}
+	for _, other := range this.domains {
+		if d.FirstWorker <= other.LastWorker && other.FirstWorker <= d.LastWorker {
+			return fmt.Errorf("%w: %s [%d, %d] and %s [%d, %d]", ErrWorkerRangeOverlap, d.Name, d.FirstWorker, d.LastWorker, other.Name, other.FirstWorker, other.LastWorker)
+		}
+	}
+	this.domains[d.Name] = d
+	return nil
+}
+
+// Get 获取业务域的生成器，生成器不存在的时候会创建，实例编号超出业务域的范围时返回 ErrInstanceOutOfRange
+func (this *Pool) Get(name string) (*DomainGenerator, error) {
+	this.mu.RLock()
+	var g = this.generators[name]
+	this.mu.RUnlock()
+
+	if g != nil {
+		return g, nil
+	}
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if g = this.generators[name]; g != nil {
+		return g, nil
+	}
+	var d, ok = this.domains[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrDomainNotFound, name)
+	}
+	var worker = d.FirstWorker + this.instance
+	if this.instance < 0 || worker > d.LastWorker {
+		return nil, fmt.Errorf("%w: instance %d, %s [%d, %d]", ErrInstanceOutOfRange, this.instance, name, d.FirstWorker, d.LastWorker)
+	}
+
+	var s, err = this.base.clone(append([]Option{WithWorkerID(worker)}, d.Options...)...)
+	if err != nil {
+		return nil, err
+	}
+	g = &DomainGenerator{name: name, generator: s}
+	if d.Entity != nil {
+		g.entity = *d.Entity
+	}
+	this.generators[name] = g
+	return g, nil
+}
+
+// Next 使用业务域的生成器生成 id
+func (this *Pool) Next(name string) (int64, error) {
+	var g, err = this.Get(name)
+	if err != nil {
+		return 0, err
+	}
+	return g.NextID()
+}
+
+// Domains 获取已经注册的业务域，按照名称排序
+func (this *Pool) Domains() []Domain {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+
+	var domains = make([]Domain, 0, len(this.domains))
+	for _, d := range this.domains {
+		domains = append(domains, d)
+	}
+	sort.Slice(domains, func(i, j int) bool { return domains[i].Name < domains[j].Name })
+	return domains
+}
+
+// Close 关闭所有已经创建的生成器，释放生成器获取的文件锁
+func (this *Pool) Close() error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	var errs []error
+	for name, g := range this.generators {
+		if err := g.generator.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(this.generators, name)
+	}
+	return errors.Join(errs...)
+}
+
+// DomainGenerator 业务域的生成器，生成的 id 包含业务域的实体类型
+type DomainGenerator struct {
+	name      string
+	entity    int64
+	generator *SnowFlake
+}
+
+// Name 获取业务域的名称
+func (this *DomainGenerator) Name() string {
+	return this.name
+}
+
+// Generator 获取业务域使用的生成器，可以用于解析业务域生成的 id
+func (this *DomainGenerator) Generator() *SnowFlake {
+	return this.generator
+}
+
+// Next 生成新的 id，无法生成 id 时返回 -1
+func (this *DomainGenerator) Next() int64 {
+	var id, err = this.NextID()
+	if err != nil {
+		return -1
+	}
+	return id
+}
+
+// NextID 生成新的 id，无法生成 id 时返回具体的错误
+func (this *DomainGenerator) NextID() (int64, error) {
+	return this.generator.next(this.entity)
+}
@@ -0,0 +1,59 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPool(t *testing.T) {
+	var base, _ = New(WithTimeOffset(testEpoch), WithEntityBits(3))
+	var pool = NewPool(base, 2)
+	defer pool.Close()
+
+	var invoice int64 = 5
+	if err := pool.Register(Domain{Name: "order", FirstWorker: 0, LastWorker: 31}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Register(Domain{Name: "invoice", FirstWorker: 32, LastWorker: 63, Entity: &invoice}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Register(Domain{Name: "refund", FirstWorker: 60, LastWorker: 70}); !errors.Is(err, ErrWorkerRangeOverlap) {
+		t.Fatalf("Register() with an overlapping range error = %v, want %v", err, ErrWorkerRangeOverlap)
+	}
+	if err := pool.Register(Domain{Name: "order", FirstWorker: 100, LastWorker: 101}); err != ErrDomainExists {
+		t.Fatalf("Register() twice error = %v, want %v", err, ErrDomainExists)
+	}
+	var bad int64 = 8
+	if err := pool.Register(Domain{Name: "refund", FirstWorker: 100, LastWorker: 101, Entity: &bad}); !errors.Is(err, ErrEntityNotAllowed) {
+		t.Fatalf("Register() with entity 8 error = %v, want %v", err, ErrEntityNotAllowed)
+	}
+	if err := pool.Register(Domain{Name: "refund", FirstWorker: 1000, LastWorker: 1024}); !errors.Is(err, ErrWorkerIDNotAllowed) {
+		t.Fatalf("Register() with worker 1024 error = %v, want %v", err, ErrWorkerIDNotAllowed)
+	}
+
+	var order, err = pool.Next("order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g, _ = pool.Get("invoice")
+	var id = g.Next()
+	if base.Worker(order) != 2 || base.Worker(id) != 34 || base.Decode(id).Entity != 5 || base.Decode(order).Entity != 0 {
+		t.Fatalf("order %s, invoice %s", base.Explain(order), base.Explain(id))
+	}
+	if same, _ := pool.Get("invoice"); same != g {
+		t.Fatal("Get() should return the same generator")
+	}
+	if _, err = pool.Next("payment"); !errors.Is(err, ErrDomainNotFound) {
+		t.Fatalf("Next(payment) error = %v, want %v", err, ErrDomainNotFound)
+	}
+
+	// 实例编号超出业务域的范围
+	var small = NewPool(base, 2)
+	small.Register(Domain{Name: "order", FirstWorker: 0, LastWorker: 1})
+	if _, err = small.Get("order"); !errors.Is(err, ErrInstanceOutOfRange) {
+		t.Fatalf("Get() error = %v, want %v", err, ErrInstanceOutOfRange)
+	}
+	if names := pool.Domains(); len(names) != 2 || names[0].Name != "invoice" {
+		t.Fatalf("Domains() = %+v", names)
+	}
+}
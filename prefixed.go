@@ -0,0 +1,62 @@
+package snowflake
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	kPrefixSeparator = "_"
+)
+
+var (
+	ErrInvalidPrefix  = errors.New("snowflake: prefix must be non-empty lowercase letters or digits")
+	ErrPrefixMismatch = errors.New("snowflake: id prefix mismatch")
+)
+
+// PrefixedID 将 id 格式化为 <prefix>_<base62> 形式的字符串，如：ord_7n42DGM5Tflk，便于区分不同类型实体的 id
+type PrefixedID struct {
+	prefix string
+}
+
+// NewPrefixedID 创建 PrefixedID，prefix 只能包含小写字母和数字
+func NewPrefixedID(prefix string) (*PrefixedID, error) {
+	if !validPrefix(prefix) {
+		return nil, ErrInvalidPrefix
+	}
+	var p = &PrefixedID{}
+	p.prefix = prefix
+	return p, nil
+}
+
+// Prefix 获取前缀
+func (this *PrefixedID) Prefix() string {
+	return this.prefix
+}
+
+// Format 获取 id 带前缀的字符串形式
+func (this *PrefixedID) Format(id int64) string {
+	return this.prefix + kPrefixSeparator + Base62(id)
+}
+
+// Parse 解析由 Format 生成的字符串，前缀不一致时返回 ErrPrefixMismatch
+func (this *PrefixedID) Parse(s string) (int64, error) {
+	var idx = strings.Index(s, kPrefixSeparator)
+	if idx < 0 || s[:idx] != this.prefix {
+		return 0, ErrPrefixMismatch
+	}
+	return ParseBase62(s[idx+1:])
+}
+
+func validPrefix(prefix string) bool {
+	if len(prefix) == 0 {
+		return false
+	}
+	for i := 0; i < len(prefix); i++ {
+		var c = prefix[i]
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,29 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPrefixedID(t *testing.T) {
+	var order, _ = NewPrefixedID("ord")
+	var user, _ = NewPrefixedID("usr")
+
+	var id = Next()
+	var s = order.Format(id)
+	if got, err := order.Parse(s); err != nil || got != id {
+		t.Fatalf("Parse(%q) = %d, %v, want %d", s, got, err, id)
+	}
+	if _, err := user.Parse(s); err != ErrPrefixMismatch {
+		t.Fatalf("Parse(%q) error = %v, want %v", s, err, ErrPrefixMismatch)
+	}
+	if _, err := order.Parse("ord_***"); !errors.Is(err, ErrInvalidBase62) {
+		t.Fatalf("Parse(%q) error = %v, want %v", "ord_***", err, ErrInvalidBase62)
+	}
+
+	for _, prefix := range []string{"", "Ord", "or_d"} {
+		if _, err := NewPrefixedID(prefix); err != ErrInvalidPrefix {
+			t.Fatalf("NewPrefixedID(%q) error = %v, want %v", prefix, err, ErrInvalidPrefix)
+		}
+	}
+}
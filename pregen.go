@@ -0,0 +1,154 @@
+package snowflake
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidUsedRanges = errors.New("snowflake: invalid used ranges file")
+)
+
+// PregenFormat Pregenerate 输出的格式
+type PregenFormat int
+
+const (
+	PregenDecimal PregenFormat = iota // 每行一个十进制的 id
+	PregenBinary                      // 每个 id 占用 8 个字节，大端序
+)
+
+const (
+	kPregenChunk = 4096
+)
+
+// UsedRange 离线生成 id 时使用过的时间范围，包含 [From, To]，通过 RecordUsedRange 保存，
+// 使用相同工作节点标识的生成器通过 WithUsedRanges 读取之后，不会再使用这段时间内的时间戳。
+type UsedRange struct {
+	Epoch      int64     `json:"epoch"` // 时间偏移量（毫秒）
+	Region     int64     `json:"region"`
+	Version    int64     `json:"version"`
+	DataCenter int64     `json:"data_center"`
+	Machine    int64     `json:"machine"`
+	From       time.Time `json:"from"`
+	To         time.Time `json:"to"`
+	Count      int64     `json:"count"`
+}
+
+// Pregenerate 生成 n 个 id 并按照 format 写入 w，用于需要离线为记录分配 id 的场景，返回使用过的时间范围。
+//
+// 返回的时间范围需要通过 RecordUsedRange 保存，在线的生成器如果使用了相同的工作节点标识，需要通过 WithUsedRanges 读取，
+// 最好为离线生成预留单独的工作节点标识。
+func (this *SnowFlake) Pregenerate(w io.Writer, n int64, format PregenFormat) (UsedRange, error) {
+	var r = UsedRange{}
+	r.Epoch = this.timeOffset
+	r.Region = this.region
+	r.Version = this.version
+	r.DataCenter = this.dataCenter
+	r.Machine = this.machine
+
+	var bw = bufio.NewWriter(w)
+	var ids = make([]int64, kPregenChunk)
+	var buf []byte
+	for r.Count < n {
+		var chunk = ids
+		if n-r.Count < int64(len(chunk)) {
+			chunk = chunk[:n-r.Count]
+		}
+		if err := this.Fill(chunk); err != nil {
+			return r, err
+		}
+		if r.Count == 0 {
+			r.From = this.TimeOf(chunk[0])
+		}
+		r.To = this.TimeOf(chunk[len(chunk)-1])
+		r.Count += int64(len(chunk))
+
+		for _, id := range chunk {
+			buf = buf[:0]
+			switch format {
+			case PregenBinary:
+				buf = binary.BigEndian.AppendUint64(buf, uint64(id))
+			default:
+				buf = strconv.AppendInt(buf, id, 10)
+				buf = append(buf, '\n')
+			}
+			if _, err := bw.Write(buf); err != nil {
+				return r, err
+			}
+		}
+	}
+	return r, bw.Flush()
+}
+
+// RecordUsedRange 将使用过的时间范围以 JSON Lines 的格式追加到 path 指定的文件中，文件不存在时创建
+func RecordUsedRange(path string, r UsedRange) error {
+	var data, err = json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WithUsedRanges 读取通过 RecordUsedRange 保存的时间范围，生成器只会使用比其中相同工作节点标识的时间范围更新的时间戳，文件不存在时忽略。
+//
+// 离线生成 id 的时间范围比当前的时间新时（如离线生成时的时钟偏快），会按照时钟回拨处理。
+func WithUsedRanges(path string) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		s.usedRanges = path
+		return nil
+	})
+}
+
+// loadUsedRanges 使用最终的配置读取使用过的时间范围
+func (this *SnowFlake) loadUsedRanges() error {
+	if this.usedRanges == "" {
+		return nil
+	}
+	var data, err = os.ReadFile(this.usedRanges)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var r UsedRange
+		if err = json.Unmarshal(line, &r); err != nil {
+			return fmt.Errorf("%w %s line %d: %w", ErrInvalidUsedRanges, this.usedRanges, i+1, err)
+		}
+		if r.Epoch != this.timeOffset || r.Region != this.region || r.Version != this.version ||
+			r.DataCenter != this.dataCenter || r.Machine != this.machine {
+			continue
+		}
+		// 将上一次生成 id 的时间戳设置为时间范围的结束，并且用完这一毫秒的序列号
+		if to := r.To.UnixNano() / 1e6; to > this.millisecond {
+			this.millisecond = to
+			this.exhaustSequence()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package snowflake
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnowFlake_Pregenerate(t *testing.T) {
+	var s, _ = New(WithTimeOffset(testEpoch), WithMachine(3))
+
+	var buf bytes.Buffer
+	var r, err = s.Pregenerate(&buf, 10000, PregenDecimal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lines = strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 10000 || r.Count != 10000 || r.Machine != 3 || r.Epoch != s.timeOffset {
+		t.Fatalf("Pregenerate() wrote %d lines, range %+v", len(lines), r)
+	}
+	var last int64
+	for _, line := range lines {
+		var id, _ = strconv.ParseInt(line, 10, 64)
+		if id <= last {
+			t.Fatalf("ids %d, %d are not increasing", last, id)
+		}
+		last = id
+	}
+	if !r.From.Equal(s.TimeOf(mustParse(t, lines[0]))) || !r.To.Equal(s.TimeOf(last)) {
+		t.Fatalf("range %+v does not match the generated ids", r)
+	}
+
+	buf.Reset()
+	if r, err = s.Pregenerate(&buf, 3, PregenBinary); err != nil || buf.Len() != 24 {
+		t.Fatalf("Pregenerate() = %+v, %v, wrote %d bytes", r, err, buf.Len())
+	}
+	if id := int64(binary.BigEndian.Uint64(buf.Bytes()[16:])); !s.TimeOf(id).Equal(r.To) || id <= last {
+		t.Fatalf("binary id %d does not match range %+v", id, r)
+	}
+}
+
+func TestWithUsedRanges(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "ranges.jsonl")
+
+	// 文件不存在时忽略
+	if _, err := New(WithUsedRanges(path)); err != nil {
+		t.Fatal(err)
+	}
+
+	var offline, _ = New(WithTimeOffset(testEpoch), WithMachine(3))
+	var used, _ = offline.Pregenerate(&bytes.Buffer{}, 100, PregenDecimal)
+	used.To = time.Now().Add(20 * time.Millisecond).Truncate(time.Millisecond)
+	if err := RecordUsedRange(path, used); err != nil {
+		t.Fatal(err)
+	}
+	var other = used
+	other.Machine = 4
+	other.To = time.Now().Add(time.Hour)
+	if err := RecordUsedRange(path, other); err != nil {
+		t.Fatal(err)
+	}
+
+	var online, err = New(WithTimeOffset(testEpoch), WithMachine(3), WithUsedRanges(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var id int64
+	if id, err = online.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	if tm := online.TimeOf(id); !tm.After(used.To) {
+		t.Fatalf("TimeOf(%d) = %v, want after the used range %v", id, tm, used.To)
+	}
+
+	// 其它工作节点标识的时间范围不影响当前的生成器
+	if tm := online.TimeOf(id); tm.After(time.Now().Add(time.Minute)) {
+		t.Fatalf("TimeOf(%d) = %v, used the range of another machine", id, tm)
+	}
+
+	if err = os.WriteFile(path, []byte("{\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = New(WithUsedRanges(path)); !errors.Is(err, ErrInvalidUsedRanges) {
+		t.Fatalf("New() error = %v, want %v", err, ErrInvalidUsedRanges)
+	}
+}
+
+func mustParse(t *testing.T, s string) int64 {
+	t.Helper()
+	var id, err = strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
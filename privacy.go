@@ -0,0 +1,53 @@
+package snowflake
+
+import (
+	"math/big"
+)
+
+// WithTimeEncryption 使用 key 对 id 中的时间戳部分进行加密，外部无法通过 id 推算出数据的创建时间，key 为 16、24 或者 32 字节的 AES 密钥。
+//
+// 时间戳部分使用 FF1 在时间戳占用的位数内加密，加密结果与原始时间戳一一对应，生成的 id 仍然不会重复，其它部分保持不变。
+// 加密之后 id 不再按照生成的时间排序，持有密钥的一方可以通过生成器的 Decode、TimeOf 解析出真实的时间，通过 SortKey 获取可以排序的值。
+//
+// 同一毫秒内生成的 id 只需要加密一次时间戳。
+func WithTimeEncryption(key []byte) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		var cipher, err = NewFF1(key, []byte("snowflake-time"))
+		if err != nil {
+			return err
+		}
+		s.timeCipher = cipher
+		s.cipherPlain = -1
+		return nil
+	})
+}
+
+// encryptTime 加密相对于时间偏移量的时间戳，调用方需要持有 mu
+func (this *SnowFlake) encryptTime(timestamp int64) int64 {
+	if this.timeCipher == nil {
+		return timestamp
+	}
+	if timestamp != this.cipherPlain {
+		var n = int(this.layout.Time())
+		this.cipherText = this.timeCipher.encrypt(2, n, big.NewInt(timestamp)).Int64()
+		this.cipherPlain = timestamp
+	}
+	return this.cipherText
+}
+
+// timestampOf 获取 id 中相对于时间偏移量的时间戳，使用了 WithTimeEncryption 时会先解密
+func (this *SnowFlake) timestampOf(s int64) int64 {
+	var timestamp = this.bits.time.get(s)
+	if this.timeCipher == nil {
+		return timestamp
+	}
+	return this.timeCipher.decrypt(2, int(this.layout.Time()), big.NewInt(timestamp)).Int64()
+}
+
+// SortKey 获取 id 按照生成时间排序时使用的值，即把时间戳部分替换为解密之后的时间戳，没有使用 WithTimeEncryption 时返回 id 本身
+func (this *SnowFlake) SortKey(s int64) int64 {
+	if this.timeCipher == nil {
+		return s
+	}
+	return s&^(this.bits.time.max<<this.bits.time.shift) | this.bits.time.put(this.timestampOf(s))
+}
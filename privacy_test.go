@@ -0,0 +1,53 @@
+package snowflake
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestWithTimeEncryption(t *testing.T) {
+	var key = []byte("0123456789abcdef")
+	var s, err = New(WithTimeOffset(testEpoch), WithMachine(3), WithTimeEncryption(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var plain, _ = New(WithTimeOffset(testEpoch), WithMachine(3))
+
+	var ids []int64
+	var seen = make(map[int64]bool)
+	for i := 0; i < 20; i++ {
+		var id = s.Next()
+		if id < 0 || seen[id] {
+			t.Fatalf("Next() = %d", id)
+		}
+		seen[id] = true
+		ids = append(ids, id)
+
+		var p = s.Decode(id)
+		if p.Machine != 3 || p.Sequence != plain.Decode(id).Sequence {
+			t.Fatalf("Decode(%d) = %+v", id, p)
+		}
+		assertRecent(t, s, id)
+		if !p.Time.Equal(s.TimeOf(id)) {
+			t.Fatalf("TimeOf(%d) = %v, want %v", id, s.TimeOf(id), p.Time)
+		}
+		if time.Since(plain.TimeOf(id)) < time.Minute && time.Since(plain.TimeOf(id)) > -time.Minute {
+			t.Fatalf("id %d exposes the time %v without the key", id, plain.TimeOf(id))
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// 持有密钥的一方可以按照生成的时间排序
+	var sorted = append([]int64(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return s.SortKey(sorted[i]) < s.SortKey(sorted[j]) })
+	for i := range ids {
+		if sorted[i] != ids[i] {
+			t.Fatalf("SortKey() order = %v, want %v", sorted, ids)
+		}
+	}
+
+	if _, err = New(WithTimeEncryption([]byte("short"))); err != ErrInvalidFF1Key {
+		t.Fatalf("New() error = %v, want %v", err, ErrInvalidFF1Key)
+	}
+}
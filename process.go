@@ -0,0 +1,76 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var (
+	ErrInvalidProcessBits = errors.New("snowflake: process bits must leave at least 1 bit for the sequence")
+	ErrNoProcessSlot      = errors.New("snowflake: all process slots of the machine id are in use")
+)
+
+// WithProcessSlots 让同一台主机上的多个进程共用一个机器标识，如 PHP-FPM 或者 prefork 模式下的多个工作进程。
+//
+// 序列号的高 bits 位作为进程槽位，创建生成器时在 dir 目录下依次尝试为每个槽位加文件锁，使用第一个空闲的槽位，
+// 每个进程只使用自己槽位内的序列号，所有槽位都被占用时返回 ErrNoProcessSlot。
+// 每个进程每毫秒可以生成的 id 数量相应地减少为原来的 1/2^bits，进程退出或者调用 Close 之后槽位会被释放。
+//
+// 槽位的文件锁与 WithExclusiveMachineLock 的文件锁是不同的文件，两者不会互斥：使用相同机器标识的一个进程设置了
+// WithExclusiveMachineLock、另一个进程设置了 WithProcessSlots 时都可以创建成功，并且会生成重复的 id。
+// 共用机器标识的所有进程都需要使用 WithProcessSlots 并且使用相同的 dir 和 bits，不需要再设置 WithExclusiveMachineLock。
+func WithProcessSlots(dir string, bits uint8) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		s.processDir = dir
+		s.processBits = bits
+		return nil
+	})
+}
+
+// ProcessSlot 获取生成器使用的进程槽位，没有使用 WithProcessSlots 时为 0
+func (this *SnowFlake) ProcessSlot() int64 {
+	return this.process
+}
+
+// splitProcessSlot 使用最终的布局从序列号中划分出进程槽位，需要在 initStride 之前调用，步长按照划分之后的序列号选择
+func (this *SnowFlake) splitProcessSlot() error {
+	if this.processDir == "" || this.processBits == 0 {
+		return nil
+	}
+	if this.processBits >= this.layout.Sequence {
+		return ErrInvalidProcessBits
+	}
+
+	var seq = this.bits.sequence
+	var seqBits = this.layout.Sequence - this.processBits
+	this.bits.sequence = newField(seq.shift, seqBits)
+	this.bits.process = newField(seq.shift+seqBits, this.processBits)
+	return nil
+}
+
+// lockProcessSlot 获取空闲的进程槽位
+func (this *SnowFlake) lockProcessSlot() error {
+	if this.processDir == "" || this.processBits == 0 {
+		return nil
+	}
+	for slot := int64(0); slot <= this.bits.process.max; slot++ {
+		var path = filepath.Join(this.processDir, fmt.Sprintf("snowflake-r%d-dc%d-m%d-p%d.lock", this.region, this.dataCenter, this.machine, slot))
+		var file, err = os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+		if err != nil {
+			return err
+		}
+		if err = lockFile(file); err != nil {
+			file.Close()
+			if err == ErrMachineLocked {
+				continue
+			}
+			return err
+		}
+		this.process = slot
+		this.locks = append(this.locks, file)
+		return nil
+	}
+	return ErrNoProcessSlot
+}
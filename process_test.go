@@ -0,0 +1,71 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package snowflake
+
+import (
+	"testing"
+)
+
+func TestWithProcessSlots(t *testing.T) {
+	var dir = t.TempDir()
+	var seen = make(map[int64]bool)
+	var gens []*SnowFlake
+	for i := 0; i < 4; i++ {
+		var s, err = New(WithMachine(5), WithProcessSlots(dir, 2))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+		if s.ProcessSlot() != int64(i) {
+			t.Fatalf("ProcessSlot() = %d, want %d", s.ProcessSlot(), i)
+		}
+		gens = append(gens, s)
+	}
+	if _, err := New(WithMachine(5), WithProcessSlots(dir, 2)); err != ErrNoProcessSlot {
+		t.Fatalf("New() error = %v, want %v", err, ErrNoProcessSlot)
+	}
+
+	for i := 0; i < 3000; i++ {
+		for _, s := range gens {
+			var id = s.Next()
+			if seen[id] {
+				t.Fatalf("duplicate id %d", id)
+			}
+			seen[id] = true
+			if p := s.Decode(id); p.Machine != 5 || p.Sequence > 1023 {
+				t.Fatalf("Decode(%d) = %+v", id, p)
+			}
+		}
+	}
+
+	// 释放之后槽位可以被新的进程使用
+	gens[1].Close()
+	var s, err = New(WithMachine(5), WithProcessSlots(dir, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if s.ProcessSlot() != 1 {
+		t.Fatalf("ProcessSlot() = %d, want 1", s.ProcessSlot())
+	}
+
+	if _, err = New(WithProcessSlots(dir, 12)); err != ErrInvalidProcessBits {
+		t.Fatalf("New() error = %v, want %v", err, ErrInvalidProcessBits)
+	}
+}
+
+func TestWithProcessSlots_Jitter(t *testing.T) {
+	// 步长按照划分进程槽位之后的序列号选择，不会被截取为 1
+	var dir = t.TempDir()
+	for i := 0; i < 32; i++ {
+		var s, err = New(WithProcessSlots(dir, 1), WithSequenceJitter())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.bits.sequence.max != kMaxSequence>>1 || s.stride <= 1 || s.stride > s.bits.sequence.max {
+			t.Fatalf("stride = %d, sequence max = %d", s.stride, s.bits.sequence.max)
+		}
+		s.Close()
+	}
+}
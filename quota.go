@@ -0,0 +1,142 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrQuotaExceeded = errors.New("snowflake: quota exceeded")
+)
+
+// Quota 生成器的配额，字段为 0 时表示不限制
+type Quota struct {
+	PerSecond int64 // 每秒最多可以生成的 id 数量
+	PerDay    int64 // 每天（UTC）最多可以生成的 id 数量
+}
+
+// QuotaExceededError 超出配额时返回的错误，可以通过 errors.Is(err, ErrQuotaExceeded) 判断
+type QuotaExceededError struct {
+	Name   string        // 生成器名称
+	Limit  int64         // 配额
+	Window time.Duration // 配额的统计周期
+}
+
+func (this *QuotaExceededError) Error() string {
+	return fmt.Sprintf("snowflake: quota exceeded for %q, limit %d per %s", this.Name, this.Limit, this.Window)
+}
+
+func (this *QuotaExceededError) Is(target error) bool {
+	return target == ErrQuotaExceeded
+}
+
+// quotaState 按照固定窗口统计生成器的用量
+type quotaState struct {
+	mu          sync.Mutex
+	quota       Quota
+	usesDefault bool  // 是否使用 Manager 的默认配额，默认配额在每次检查时获取，修改后立即生效
+	second      int64 // 当前统计的秒
+	perSec      int64 // 当前秒已经生成的数量
+	day         int64 // 当前天已经生成的数量对应的天
+	perDay      int64 // 当前天已经生成的数量
+	rejected    int64 // 因为超出配额被拒绝的次数
+}
+
+// take 占用一个配额，defaultQuota 为 Manager 当前的默认配额
+func (this *quotaState) take(name string, defaultQuota Quota, now time.Time) error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	var quota = this.quota
+	if this.usesDefault {
+		quota = defaultQuota
+	}
+
+	var second = now.Unix()
+	if second != this.second {
+		this.second = second
+		this.perSec = 0
+	}
+	var day = second / 86400
+	if day != this.day {
+		this.day = day
+		this.perDay = 0
+	}
+
+	if quota.PerSecond > 0 && this.perSec >= quota.PerSecond {
+		this.rejected++
+		return &QuotaExceededError{Name: name, Limit: quota.PerSecond, Window: time.Second}
+	}
+	if quota.PerDay > 0 && this.perDay >= quota.PerDay {
+		this.rejected++
+		return &QuotaExceededError{Name: name, Limit: quota.PerDay, Window: 24 * time.Hour}
+	}
+	this.perSec++
+	this.perDay++
+	return nil
+}
+
+// giveBack 归还通过 take 占用的配额，用于生成 id 失败的情况，now 为调用 take 时的时间
+func (this *quotaState) giveBack(now time.Time) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	var second = now.Unix()
+	if second == this.second && this.perSec > 0 {
+		this.perSec--
+	}
+	if second/86400 == this.day && this.perDay > 0 {
+		this.perDay--
+	}
+}
+
+func (this *quotaState) rejectedCount() int64 {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	return this.rejected
+}
+
+// SetQuota 设置指定名称的生成器的配额
+func (this *Manager) SetQuota(name string, quota Quota) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if state, ok := this.quotas[name]; ok {
+		state.mu.Lock()
+		state.quota = quota
+		state.usesDefault = false
+		state.mu.Unlock()
+		return
+	}
+	this.quotas[name] = &quotaState{quota: quota}
+}
+
+// SetDefaultQuota 设置没有通过 SetQuota 单独设置配额的生成器的配额，对已经在使用默认配额的生成器同样生效
+func (this *Manager) SetDefaultQuota(quota Quota) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.defaultQuota = quota
+}
+
+// takeQuota 占用指定名称的生成器的一个配额，返回的 quotaState 用于在生成 id 失败时归还配额，没有配额限制时为 nil
+func (this *Manager) takeQuota(name string, now time.Time) (*quotaState, error) {
+	this.mu.RLock()
+	var state = this.quotas[name]
+	var quota = this.defaultQuota
+	this.mu.RUnlock()
+
+	if state == nil {
+		if quota == (Quota{}) {
+			return nil, nil
+		}
+		this.mu.Lock()
+		if state = this.quotas[name]; state == nil {
+			state = &quotaState{usesDefault: true}
+			this.quotas[name] = state
+		}
+		this.mu.Unlock()
+	}
+	return state, state.take(name, quota, now)
+}
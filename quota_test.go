@@ -0,0 +1,97 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestManager_Quota(t *testing.T) {
+	var now = time.Date(2024, 6, 11, 23, 59, 58, 0, time.UTC)
+	var m = NewManager(func(name string) ([]Option, error) {
+		return nil, nil
+	})
+	m.now = func() time.Time { return now }
+	m.SetQuota("noisy", Quota{PerSecond: 2, PerDay: 3})
+	m.SetDefaultQuota(Quota{PerSecond: 100})
+
+	for i := 0; i < 2; i++ {
+		if _, err := m.Next("noisy"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var _, err = m.Next("noisy")
+	var quotaErr *QuotaExceededError
+	if !errors.Is(err, ErrQuotaExceeded) || !errors.As(err, &quotaErr) || quotaErr.Window != time.Second {
+		t.Fatalf("Next() error = %v, want per second quota error", err)
+	}
+
+	now = now.Add(time.Second)
+	if _, err = m.Next("noisy"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = m.Next("noisy"); !errors.As(err, &quotaErr) || quotaErr.Window != 24*time.Hour {
+		t.Fatalf("Next() error = %v, want per day quota error", err)
+	}
+
+	now = now.Add(time.Second)
+	if _, err = m.Next("noisy"); err != nil {
+		t.Fatalf("Next() on a new day error = %v", err)
+	}
+
+	if _, err = m.Next("quiet"); err != nil {
+		t.Fatal(err)
+	}
+	if rejected := m.Stats().Rejected["noisy"]; rejected != 2 {
+		t.Fatalf("Rejected = %d, want 2", rejected)
+	}
+}
+
+func TestManager_DefaultQuota(t *testing.T) {
+	var now = time.Date(2024, 6, 11, 8, 0, 0, 0, time.UTC)
+	var m = NewManager(func(name string) ([]Option, error) {
+		return nil, nil
+	})
+	m.now = func() time.Time { return now }
+	m.SetDefaultQuota(Quota{PerSecond: 1000})
+
+	if _, err := m.Next("tenant"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 修改默认配额之后，已经在使用默认配额的生成器立即生效
+	m.SetDefaultQuota(Quota{PerSecond: 1})
+	var allowed = 0
+	now = now.Add(time.Second)
+	for i := 0; i < 10; i++ {
+		if _, err := m.Next("tenant"); err == nil {
+			allowed++
+		}
+	}
+	if allowed != 1 {
+		t.Fatalf("allowed %d of 10 calls, want 1", allowed)
+	}
+}
+
+func TestManager_QuotaGiveBack(t *testing.T) {
+	var now = time.Date(2024, 6, 11, 8, 0, 0, 0, time.UTC)
+	var m = NewManager(nil)
+	m.now = func() time.Time { return now }
+	m.Register("tenant")
+	m.SetQuota("tenant", Quota{PerSecond: 1})
+
+	var s, _ = m.Get("tenant")
+	// 模拟时钟回拨，生成 id 失败时不应该占用配额
+	s.millisecond = s.getMillisecond() + 3600*1000
+	if _, err := m.Next("tenant"); err != ErrClockMovedBackwards {
+		t.Fatalf("Next() error = %v, want %v", err, ErrClockMovedBackwards)
+	}
+
+	s.mu.Lock()
+	s.millisecond = 0
+	s.mu.Unlock()
+	if _, err := m.Next("tenant"); err != nil {
+		t.Fatalf("Next() error = %v, the failed call should have given its quota back", err)
+	}
+}
@@ -0,0 +1,114 @@
+package snowflake
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+)
+
+var (
+	ErrIdentityChanged = errors.New("snowflake: config changes settings that affect the generated ids, a new generator is needed")
+)
+
+// Reload 在运行期间应用配置中不影响生成的 id 的部分，包括时钟回拨的容忍范围和处理方式、等待策略和最多等待的时长、
+// 每毫秒最多生成的 id 数量、随机的序列号起始值以及日志级别，
+// 这些字段没有设置时恢复为默认值。
+//
+// 配置中设置了时间偏移量、布局、区域、版本、数据中心或者机器标识，并且与生成器当前使用的不同时，返回 ErrIdentityChanged，不修改任何配置。
+// 启动次数、文件锁和进程槽位只在创建生成器时使用，Reload 会忽略这些字段。
+func (this *SnowFlake) Reload(cfg Config) error {
+	var opts, err = cfg.runtimeOptions()
+	if err != nil {
+		return err
+	}
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if err = this.checkIdentity(cfg); err != nil {
+		return err
+	}
+	for _, opt := range opts {
+		if err = opt.Apply(this); err != nil {
+			return err
+		}
+	}
+	this.recordEvent(EventReload, "")
+	return nil
+}
+
+// checkIdentity 检查配置中影响生成的 id 的字段是否与生成器当前使用的相同
+func (this *SnowFlake) checkIdentity(cfg Config) error {
+	if cfg.Epoch != "" {
+		var epoch, err = parseEpoch(cfg.Epoch)
+		if err != nil {
+			return configError("epoch", cfg.Epoch, err)
+		}
+		if epoch.UnixNano()/1e6 != this.timeOffset {
+			return ErrIdentityChanged
+		}
+	}
+	if cfg.Layout != "" {
+		var l, err = ParseLayout(cfg.Layout)
+		if err != nil {
+			return configError("layout", cfg.Layout, err)
+		}
+		if cfg.BootCounter != nil {
+			l.Boot = cfg.BootCounter.Bits
+		}
+		l.Order = this.layout.Order
+		if l.bits() != this.layout.bits() {
+			return ErrIdentityChanged
+		}
+	}
+	if cfg.WorkerID != nil {
+		if *cfg.WorkerID != this.dataCenter<<this.layout.Machine|this.machine {
+			return ErrIdentityChanged
+		}
+	} else if cfg.DataCenter != this.dataCenter || cfg.Machine != this.machine {
+		return ErrIdentityChanged
+	}
+	if cfg.Region != this.region || cfg.Version != this.version {
+		return ErrIdentityChanged
+	}
+	return nil
+}
+
+// ReloadOnSignal 收到信号时通过 load 读取配置并调用 Reload，sigs 为空时使用 SIGHUP，
+// 读取或者应用配置失败时会通过 WithLogger 设置的日志记录错误并继续使用原来的配置。
+//
+// 返回的函数用于停止监听信号。
+func (this *SnowFlake) ReloadOnSignal(load func() (Config, error), sigs ...os.Signal) (stop func()) {
+	if len(sigs) == 0 {
+		sigs = kReloadSignals
+	}
+	if len(sigs) == 0 {
+		// 当前平台不支持信号，如 js/wasm
+		return func() {}
+	}
+	var ch = make(chan os.Signal, 1)
+	var done = make(chan struct{})
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		for {
+			select {
+			case <-ch:
+				var cfg, err = load()
+				if err == nil {
+					err = this.Reload(cfg)
+				}
+				if err != nil && this.logger != nil {
+					this.logger.Error("snowflake: reload config", "error", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
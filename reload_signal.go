@@ -0,0 +1,12 @@
+//go:build !js
+// +build !js
+
+package snowflake
+
+import (
+	"os"
+	"syscall"
+)
+
+// kReloadSignals ReloadOnSignal 默认监听的信号
+var kReloadSignals = []os.Signal{syscall.SIGHUP}
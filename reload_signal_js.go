@@ -0,0 +1,11 @@
+//go:build js
+// +build js
+
+package snowflake
+
+import (
+	"os"
+)
+
+// kReloadSignals js/wasm 不支持信号，ReloadOnSignal 不会监听任何信号
+var kReloadSignals []os.Signal
@@ -0,0 +1,56 @@
+package snowflake
+
+import (
+	"log/slog"
+	"testing"
+)
+
+func TestSnowFlake_Reload(t *testing.T) {
+	var cfg = Config{Epoch: "2024-01-01T00:00:00Z", DataCenter: 3, Machine: 9}
+	var s, err = NewWithConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.BackwardsTolerance = "5s"
+	cfg.RollbackPolicy = "wait"
+	cfg.RandomSequenceStart = true
+	cfg.LogLevel = "debug"
+	cfg.MaxSequencePerMilli = 100
+	if err = s.Reload(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if s.tolerance != 5000 || s.rollbackPolicy != PolicyWait || s.random == nil || s.logLevel != slog.LevelDebug || s.sequenceCapacity() != 100 {
+		t.Fatalf("tolerance = %d, policy = %d, random = %v, log level = %v, capacity = %d", s.tolerance, s.rollbackPolicy, s.random, s.logLevel, s.sequenceCapacity())
+	}
+
+	// 没有设置的字段恢复为默认值
+	if err = s.Reload(Config{DataCenter: 3, Machine: 9}); err != nil {
+		t.Fatal(err)
+	}
+	if s.tolerance != kDefaultBackwardsTolerance || s.rollbackPolicy != PolicyError || s.random != nil || s.logLevel != slog.LevelWarn || s.sequenceCapacity() != kMaxSequence+1 {
+		t.Fatalf("tolerance = %d, policy = %d, random = %v, log level = %v, capacity = %d", s.tolerance, s.rollbackPolicy, s.random, s.logLevel, s.sequenceCapacity())
+	}
+
+	var worker int64 = 3<<5 | 9
+	var identity = []Config{
+		{DataCenter: 3, Machine: 10},
+		{DataCenter: 3, Machine: 9, Epoch: "2025-01-01T00:00:00Z"},
+		{DataCenter: 3, Machine: 9, Layout: "datacenter=4,machine=6,sequence=12"},
+		{DataCenter: 3, Machine: 9, Region: 1},
+		{WorkerID: &worker, BackwardsTolerance: "1ms"},
+	}
+	for i, c := range identity {
+		c.BackwardsTolerance = "2s"
+		var err = s.Reload(c)
+		if i < len(identity)-1 && err != ErrIdentityChanged {
+			t.Fatalf("%d: Reload() error = %v, want %v", i, err, ErrIdentityChanged)
+		}
+		if i == len(identity)-1 && (err != nil || s.tolerance != 2000) {
+			t.Fatalf("%d: Reload() error = %v, tolerance = %d", i, err, s.tolerance)
+		}
+		if i < len(identity)-1 && s.tolerance != kDefaultBackwardsTolerance {
+			t.Fatalf("%d: tolerance changed to %d", i, s.tolerance)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package snowflake
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSnowFlake_ReloadOnSignal(t *testing.T) {
+	var s, _ = New()
+	var loaded = make(chan struct{}, 1)
+	var stop = s.ReloadOnSignal(func() (Config, error) {
+		loaded <- struct{}{}
+		return Config{BackwardsTolerance: "3s"}, nil
+	}, syscall.SIGUSR1)
+	defer stop()
+
+	syscall.Kill(syscall.Getpid(), syscall.SIGUSR1)
+	select {
+	case <-loaded:
+	case <-time.After(time.Second):
+		t.Fatal("config is not loaded after the signal")
+	}
+	for i := 0; i < 100; i++ {
+		s.mu.Lock()
+		var tolerance = s.tolerance
+		s.mu.Unlock()
+		if tolerance == 3000 {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("config is not applied after the signal")
+}
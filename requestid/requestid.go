@@ -0,0 +1,91 @@
+// Package requestid 为请求生成 snowflake id，生成的 id 可以排序，也可以解析出生成的时间和机器，用于替代随机的 UUID。
+package requestid
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/smartwalle/snowflake"
+)
+
+const (
+	// Header 传递请求 id 的 HTTP 头
+	Header = "X-Request-ID"
+
+	// AttrKey 请求 id 在日志中的名称
+	AttrKey = "request_id"
+)
+
+type contextKey struct{}
+
+// NewContext 返回包含请求 id 的 context
+func NewContext(ctx context.Context, id int64) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext 获取 context 中的请求 id
+func FromContext(ctx context.Context) (int64, bool) {
+	var id, ok = ctx.Value(contextKey{}).(int64)
+	return id, ok
+}
+
+// Resolve 获取请求 id，incoming 为上游传递过来的请求 id，是有效的 id 时直接使用，否则使用 generator 生成新的 id
+func Resolve(generator snowflake.Generator, incoming string) (int64, error) {
+	if incoming != "" {
+		if id, err := strconv.ParseInt(incoming, 10, 64); err == nil && id > 0 {
+			return id, nil
+		}
+	}
+	return generator.NextID()
+}
+
+// Middleware 为每一个请求分配请求 id，请求 id 会写入响应的 X-Request-ID 头，并且可以通过 FromContext 从请求的 context 中获取。
+//
+// 请求中已经包含有效的 X-Request-ID 头时会沿用上游的请求 id，生成 id 失败时不会中断请求，只是没有请求 id。
+func Middleware(generator snowflake.Generator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var id, err = Resolve(generator, r.Header.Get(Header))
+			if err == nil {
+				w.Header().Set(Header, strconv.FormatInt(id, 10))
+				r = r.WithContext(NewContext(r.Context(), id))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Attr 返回请求 id 的日志属性
+func Attr(id int64) slog.Attr {
+	return slog.String(AttrKey, strconv.FormatInt(id, 10))
+}
+
+// LogHandler 包装 slog.Handler，为包含请求 id 的 context 输出的日志自动添加 request_id 属性，如：
+//
+//	var logger = slog.New(requestid.NewLogHandler(slog.NewJSONHandler(os.Stdout, nil)))
+//	logger.InfoContext(r.Context(), "order created")
+type LogHandler struct {
+	slog.Handler
+}
+
+// NewLogHandler 创建 LogHandler
+func NewLogHandler(h slog.Handler) *LogHandler {
+	return &LogHandler{Handler: h}
+}
+
+func (this *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id, ok := FromContext(ctx); ok {
+		record.AddAttrs(Attr(id))
+	}
+	return this.Handler.Handle(ctx, record)
+}
+
+func (this *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{Handler: this.Handler.WithAttrs(attrs)}
+}
+
+func (this *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{Handler: this.Handler.WithGroup(name)}
+}
@@ -0,0 +1,82 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/smartwalle/snowflake"
+)
+
+type failingGenerator struct{}
+
+func (failingGenerator) NextID() (int64, error) {
+	return 0, errors.New("generator unavailable")
+}
+
+func TestMiddleware(t *testing.T) {
+	var s, _ = snowflake.New()
+	var got int64
+	var h = Middleware(s)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = FromContext(r.Context())
+	}))
+
+	var w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got <= 0 || w.Header().Get(Header) != strconv.FormatInt(got, 10) {
+		t.Fatalf("request id = %d, header = %q", got, w.Header().Get(Header))
+	}
+
+	// 沿用上游的请求 id
+	var r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(Header, "12345")
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if got != 12345 || w.Header().Get(Header) != "12345" {
+		t.Fatalf("request id = %d, header = %q, want 12345", got, w.Header().Get(Header))
+	}
+
+	// 无效的请求 id 会被替换
+	r.Header.Set(Header, "not-a-number")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+	if got <= 0 || got == 12345 {
+		t.Fatalf("request id = %d, want a generated id", got)
+	}
+}
+
+func TestMiddleware_GeneratorError(t *testing.T) {
+	var called bool
+	var h = Middleware(failingGenerator{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		if _, ok := FromContext(r.Context()); ok {
+			t.Fatal("FromContext() should not find a request id")
+		}
+	}))
+	var w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !called || w.Header().Get(Header) != "" {
+		t.Fatalf("called = %v, header = %q", called, w.Header().Get(Header))
+	}
+}
+
+func TestLogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	var logger = slog.New(NewLogHandler(slog.NewTextHandler(&buf, nil))).With("service", "order")
+
+	logger.InfoContext(NewContext(context.Background(), 42), "created")
+	if line := buf.String(); !strings.Contains(line, "service=order") || !strings.Contains(line, "request_id=42") {
+		t.Fatalf("log = %q", line)
+	}
+
+	buf.Reset()
+	logger.InfoContext(context.Background(), "created")
+	if strings.Contains(buf.String(), AttrKey) {
+		t.Fatalf("log = %q, want no request id", buf.String())
+	}
+}
@@ -0,0 +1,25 @@
+package snowflake
+
+import (
+	"github.com/smartwalle/snowflake/internal/testhook"
+)
+
+func init() {
+	testhook.ResetDefault = resetDefault
+}
+
+// resetDefault 清除包级别的函数使用的默认生成器，返回恢复原来的默认生成器的函数，通过 snowflaketest.ResetDefault 在测试中使用。
+//
+// 默认生成器通过 Load 无锁地读取，所以清除时保存类型为 *SnowFlake 的 nil，而不是替换 defaultSnowFlake。
+func resetDefault() (restore func()) {
+	defaultMu.Lock()
+	var saved = loadedDefault()
+	defaultSnowFlake.Store((*SnowFlake)(nil))
+	defaultMu.Unlock()
+
+	return func() {
+		defaultMu.Lock()
+		defaultSnowFlake.Store(saved)
+		defaultMu.Unlock()
+	}
+}
@@ -0,0 +1,28 @@
+package snowflake
+
+import (
+	"time"
+)
+
+// Result NextResult 的结果，包含生成 id 时使用的时间戳和序列号，不需要再解析 id
+type Result struct {
+	ID       int64     `json:"id"`
+	Time     time.Time `json:"time"`     // 生成 id 使用的时间戳
+	Sequence int64     `json:"sequence"` // 生成 id 使用的序列号
+	Waited   bool      `json:"waited"`   // 当前毫秒的序列号已经用完，等待了下一毫秒或者提前使用了下一毫秒
+	Held     bool      `json:"held"`     // 处于容忍范围内的时钟回拨期间，沿用了上一次的时间戳
+}
+
+// NextResult 生成新的 id，同时返回生成 id 时使用的时间戳、序列号以及是否发生了等待，用于记录指标或者幂等处理等需要这些信息的场景
+func (this *SnowFlake) NextResult() (Result, error) {
+	var r Result
+	if _, err := this.nextResult(0, &r); err != nil {
+		return Result{}, err
+	}
+	return r, nil
+}
+
+// NextResult 使用默认生成器生成新的 id，同时返回生成 id 时使用的时间戳和序列号
+func NextResult() (Result, error) {
+	return getDefault().NextResult()
+}
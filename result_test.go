@@ -0,0 +1,33 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnowFlake_NextResult(t *testing.T) {
+	var s, _ = New(WithTimeOffset(testEpoch), WithDataCenter(3), WithMachine(5))
+	var now = time.Now().Add(time.Millisecond)
+	s.clock = func() time.Time { return now }
+
+	var r, err = s.NextResult()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p = s.Decode(r.ID)
+	if !r.Time.Equal(p.Time) || r.Sequence != p.Sequence || r.Waited || r.Held {
+		t.Fatalf("NextResult() = %+v, decoded %+v", r, p)
+	}
+
+	// 用完当前毫秒的序列号之后，在容忍范围内的时钟回拨期间提前使用下一毫秒
+	for i := int64(1); i <= kMaxSequence; i++ {
+		s.Next()
+	}
+	now = now.Add(-10 * time.Millisecond)
+	if r, err = s.NextResult(); err != nil {
+		t.Fatal(err)
+	}
+	if !r.Waited || !r.Held || r.Time.Sub(p.Time) != time.Millisecond || r.Sequence != 0 {
+		t.Fatalf("NextResult() after the sequence is used up = %+v", r)
+	}
+}
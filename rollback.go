@@ -0,0 +1,81 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	kRecentRollbacks = 16 // RecentRollbacks 保留的时钟回拨数量
+)
+
+// RollbackEvent 一次时钟回拨
+type RollbackEvent struct {
+	Time      time.Time     `json:"time"`      // 检测到时钟回拨时的系统时间
+	Magnitude time.Duration `json:"magnitude"` // 时钟回拨的时长，即上一次生成 id 的时间戳与当前时间戳的差
+	Tolerated bool          `json:"tolerated"` // 是否在容忍范围内，在容忍范围内时会继续生成 id
+}
+
+// WithLogger 设置日志，生成器默认使用 Warn 级别记录每一次时钟回拨，可以通过 Config 的 LogLevel 调整，为 nil 时不记录日志
+func WithLogger(logger *slog.Logger) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		s.logger = logger
+		return nil
+	})
+}
+
+// WithRollbackHook 设置检测到时钟回拨时的回调函数，一次连续的时钟回拨只会回调一次。
+//
+// 回调函数在生成 id 的时候同步调用，调用期间生成器处于锁定状态，不能在回调函数中调用生成器的方法，耗时较长的操作需要异步处理。
+func WithRollbackHook(hook func(RollbackEvent)) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		s.rollbackHook = hook
+		return nil
+	})
+}
+
+// observeRollback 记录时钟回拨，millisecond 为当前的时间戳，调用方需要持有 mu
+func (this *SnowFlake) observeRollback(millisecond int64) {
+	// 时钟回拨期间，时钟没有再次回拨时属于同一次时钟回拨
+	var last = this.lastClock
+	this.lastClock = millisecond
+	if this.rollingBack && millisecond >= last {
+		return
+	}
+	this.rollingBack = true
+
+	var event = RollbackEvent{}
+	event.Time = time.Now()
+	event.Magnitude = time.Duration(this.millisecond-millisecond) * time.Millisecond
+	event.Tolerated = this.millisecond-millisecond <= this.tolerance
+
+	this.stats.Rollbacks++
+	if event.Magnitude > this.stats.MaxRollback {
+		this.stats.MaxRollback = event.Magnitude
+	}
+	if len(this.rollbacks) == kRecentRollbacks {
+		this.rollbacks = append(this.rollbacks[:0], this.rollbacks[1:]...)
+	}
+	this.rollbacks = append(this.rollbacks, event)
+	this.recordEvent(EventRollback, fmt.Sprintf("magnitude=%v tolerated=%t", event.Magnitude, event.Tolerated))
+	if this.logger != nil {
+		this.logger.Log(context.Background(), this.logLevel, "snowflake: clock moved backwards",
+			slog.Duration("magnitude", event.Magnitude),
+			slog.Bool("tolerated", event.Tolerated),
+			slog.Int64("data_center", this.dataCenter),
+			slog.Int64("machine", this.machine),
+		)
+	}
+	if this.rollbackHook != nil {
+		this.rollbackHook(event)
+	}
+}
+
+// RecentRollbacks 获取最近检测到的时钟回拨，最多 16 个，按照检测到的时间排序
+func (this *SnowFlake) RecentRollbacks() []RollbackEvent {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	return append([]RollbackEvent(nil), this.rollbacks...)
+}
@@ -0,0 +1,55 @@
+package snowflake
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnowFlake_RollbackEvents(t *testing.T) {
+	var buf bytes.Buffer
+	var events []RollbackEvent
+	var s, _ = New(
+		WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+		WithRollbackHook(func(e RollbackEvent) { events = append(events, e) }),
+	)
+	var now = s.anchor
+	s.clock = func() time.Time { return now }
+	s.Next()
+
+	var moveBack = func(d time.Duration) {
+		now = now.Add(-d)
+	}
+
+	// 一次连续的时钟回拨只记录一次
+	moveBack(200 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		s.Next()
+		now = now.Add(time.Millisecond)
+	}
+	if len(events) != 1 || !events[0].Tolerated || events[0].Magnitude != 200*time.Millisecond {
+		t.Fatalf("events = %+v", events)
+	}
+
+	// 超过容忍范围
+	moveBack(5 * time.Second)
+	if _, err := s.NextID(); err != ErrClockMovedBackwards {
+		t.Fatalf("NextID() error = %v, want %v", err, ErrClockMovedBackwards)
+	}
+	if len(events) != 2 || events[1].Tolerated {
+		t.Fatalf("events = %+v", events)
+	}
+
+	var stats = s.Stats()
+	if stats.Rollbacks != 2 || stats.MaxRollback != events[1].Magnitude {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+	if recent := s.RecentRollbacks(); len(recent) != 2 || recent[0] != events[0] || recent[1] != events[1] {
+		t.Fatalf("RecentRollbacks() = %+v, want %+v", recent, events)
+	}
+	if n := strings.Count(buf.String(), "clock moved backwards"); n != 2 {
+		t.Fatalf("logged %d rollbacks, want 2: %s", n, buf.String())
+	}
+}
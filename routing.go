@@ -0,0 +1,104 @@
+package snowflake
+
+import (
+	"strconv"
+	"time"
+)
+
+// PartitionScheme 数据库分区（分表）的规则，用于根据 id 找到数据所在的分区，以及查询一段时间内的数据需要访问哪些分区
+type PartitionScheme interface {
+	// Partition 获取 id 所在分区的后缀，如按月分区时为 202406，表名为 order_202406
+	Partition(id int64) string
+
+	// PartitionsBetween 获取可能包含 [start, end] 时间范围内生成的 id 的所有分区的后缀，按分区的顺序排列
+	PartitionsBetween(start, end time.Time) []string
+}
+
+const (
+	kMonthlyPartitionLayout = "200601"
+)
+
+type monthlyScheme struct {
+	generator *SnowFlake
+	location  *time.Location
+}
+
+// NewMonthlyScheme 创建按 id 的生成时间所在的月份分区的规则，分区的后缀为 200601 格式的年月，loc 为计算月份使用的时区，为 nil 时使用 UTC
+func NewMonthlyScheme(generator *SnowFlake, loc *time.Location) PartitionScheme {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return &monthlyScheme{generator: generator, location: loc}
+}
+
+func (this *monthlyScheme) Partition(id int64) string {
+	return this.generator.TimeOf(id).In(this.location).Format(kMonthlyPartitionLayout)
+}
+
+func (this *monthlyScheme) PartitionsBetween(start, end time.Time) []string {
+	start, end = start.In(this.location), end.In(this.location)
+	if end.Before(start) {
+		return nil
+	}
+
+	var partitions []string
+	var month = time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, this.location)
+	for !month.After(end) {
+		partitions = append(partitions, month.Format(kMonthlyPartitionLayout))
+		month = month.AddDate(0, 1, 0)
+	}
+	return partitions
+}
+
+type machineScheme struct {
+	generator  *SnowFlake
+	partitions int
+}
+
+// NewMachineScheme 创建按生成 id 的数据中心和机器标识分区的规则，分区的后缀为 0 到 partitions-1，规则与 PartitionKeyByMachine 一致
+func NewMachineScheme(generator *SnowFlake, partitions int) PartitionScheme {
+	return &machineScheme{generator: generator, partitions: partitions}
+}
+
+func (this *machineScheme) Partition(id int64) string {
+	return strconv.Itoa(this.generator.PartitionKeyByMachine(id, this.partitions))
+}
+
+func (this *machineScheme) PartitionsBetween(start, end time.Time) []string {
+	if end.Before(start) {
+		return nil
+	}
+	return allPartitions(this.partitions)
+}
+
+type hashScheme struct {
+	partitions int
+}
+
+// NewHashScheme 创建按 id 的哈希值分区的规则，分区的后缀为 0 到 partitions-1，规则与 PartitionKey 一致
+func NewHashScheme(partitions int) PartitionScheme {
+	return &hashScheme{partitions: partitions}
+}
+
+func (this *hashScheme) Partition(id int64) string {
+	return strconv.Itoa(PartitionKey(id, this.partitions))
+}
+
+func (this *hashScheme) PartitionsBetween(start, end time.Time) []string {
+	if end.Before(start) {
+		return nil
+	}
+	return allPartitions(this.partitions)
+}
+
+// allPartitions 时间与分区无关，任意时间范围都需要访问所有的分区
+func allPartitions(partitions int) []string {
+	if partitions <= 0 {
+		partitions = 1
+	}
+	var names = make([]string, partitions)
+	for i := range names {
+		names[i] = strconv.Itoa(i)
+	}
+	return names
+}
@@ -0,0 +1,47 @@
+package snowflake
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMonthlyScheme(t *testing.T) {
+	var s, _ = New(WithTimeOffset(testEpoch))
+	var shanghai = time.FixedZone("Asia/Shanghai", 8*3600)
+	var scheme = NewMonthlyScheme(s, shanghai)
+
+	// UTC 时间 2024-05-31 20:00 在东八区已经是 6 月
+	var id = s.compose(time.Date(2024, 5, 31, 20, 0, 0, 0, time.UTC).UnixNano()/1e6, 0, 0)
+	if p := scheme.Partition(id); p != "202406" {
+		t.Fatalf("Partition() = %s, want 202406", p)
+	}
+	if p := NewMonthlyScheme(s, nil).Partition(id); p != "202405" {
+		t.Fatalf("Partition() = %s, want 202405", p)
+	}
+
+	var got = scheme.PartitionsBetween(time.Date(2024, 11, 15, 0, 0, 0, 0, shanghai), time.Date(2025, 2, 1, 0, 0, 0, 0, shanghai))
+	if want := []string{"202411", "202412", "202501", "202502"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("PartitionsBetween() = %v, want %v", got, want)
+	}
+	if got = scheme.PartitionsBetween(time.Now(), time.Now().Add(-time.Hour)); got != nil {
+		t.Fatalf("PartitionsBetween() = %v, want nil", got)
+	}
+}
+
+func TestMachineAndHashScheme(t *testing.T) {
+	var s, _ = New(WithDataCenter(1), WithMachine(3))
+	var id = s.Next()
+
+	if p := NewMachineScheme(s, 8).Partition(id); p != "3" {
+		t.Fatalf("Partition() = %s, want 3", p)
+	}
+	var hash = NewHashScheme(4)
+	if p := hash.Partition(id); p != hash.Partition(id) {
+		t.Fatalf("Partition() is not deterministic")
+	}
+	var now = time.Now()
+	if got := hash.PartitionsBetween(now, now); !reflect.DeepEqual(got, []string{"0", "1", "2", "3"}) {
+		t.Fatalf("PartitionsBetween() = %v", got)
+	}
+}
@@ -0,0 +1,148 @@
+package snowflake
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	kDefaultSamplerWindow = 3600 // 默认保留最近一小时的统计
+)
+
+// SequenceSample 一秒内序列号的使用情况
+type SequenceSample struct {
+	Second       time.Time `json:"second"`
+	IDs          int64     `json:"ids"`          // 生成的 id 数量
+	Milliseconds int64     `json:"milliseconds"` // 生成 id 使用的不同毫秒的数量
+	MaxSequence  int64     `json:"max_sequence"` // 单个毫秒内使用的序列号数量的最大值
+}
+
+// Percentiles 一组数值的分位数
+type Percentiles struct {
+	P50 int64 `json:"p50"`
+	P90 int64 `json:"p90"`
+	P99 int64 `json:"p99"`
+	Max int64 `json:"max"`
+}
+
+// SequenceReport SequenceSampler 的统计结果
+type SequenceReport struct {
+	Seconds      int         `json:"seconds"`      // 统计的秒数，只包含生成过 id 并且已经结束的秒
+	Capacity     int64       `json:"capacity"`     // 每毫秒可以使用的序列号数量
+	Exhausted    int         `json:"exhausted"`    // 出现过序列号用完的秒数
+	IDs          Percentiles `json:"ids"`          // 每秒生成的 id 数量
+	Milliseconds Percentiles `json:"milliseconds"` // 每秒使用的不同毫秒的数量
+	MaxSequence  Percentiles `json:"max_sequence"` // 每秒内单个毫秒使用的序列号数量的最大值
+}
+
+// SequenceSampler 按秒统计生成器序列号的使用情况，通过 WithSequenceSampler 设置，
+// 用于在调整布局之前确认序列号的位数是否足够，如 MaxSequence 的 P99 远小于 Capacity 时可以减少序列号的位数。
+type SequenceSampler struct {
+	mu       sync.Mutex
+	window   int
+	capacity int64
+	current  SequenceSample
+	second   int64 // current 对应的秒
+	lastMill int64 // 上一次统计的时间戳（毫秒）
+	samples  []SequenceSample
+	next     int // samples 已满之后下一个覆盖的位置
+}
+
+// NewSequenceSampler 创建 SequenceSampler，window 为保留的秒数，小于等于 0 时保留最近一小时
+func NewSequenceSampler(window int) *SequenceSampler {
+	if window <= 0 {
+		window = kDefaultSamplerWindow
+	}
+	var sampler = &SequenceSampler{}
+	sampler.window = window
+	sampler.second = -1
+	return sampler
+}
+
+// WithSequenceSampler 设置统计序列号使用情况的 SequenceSampler，统计在生成 id 的时候同步进行，开销为一次加锁
+func WithSequenceSampler(sampler *SequenceSampler) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		s.sampler = sampler
+		return nil
+	})
+}
+
+// observe 记录在 millisecond 内生成了 count 个 id，生成之后这一毫秒共使用了 used 个序列号，调用方需要持有生成器的 mu
+func (this *SequenceSampler) observe(millisecond, count, used, capacity int64) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	this.capacity = capacity
+	if second := millisecond / 1000; second != this.second {
+		this.flush()
+		this.second = second
+		this.current = SequenceSample{Second: time.Unix(second, 0)}
+	}
+	if millisecond != this.lastMill {
+		this.lastMill = millisecond
+		this.current.Milliseconds++
+	}
+	this.current.IDs += count
+	if used > this.current.MaxSequence {
+		this.current.MaxSequence = used
+	}
+}
+
+// flush 保存当前一秒的统计，调用方需要持有 mu
+func (this *SequenceSampler) flush() {
+	if this.current.IDs == 0 {
+		return
+	}
+	if len(this.samples) < this.window {
+		this.samples = append(this.samples, this.current)
+		return
+	}
+	this.samples[this.next] = this.current
+	this.next = (this.next + 1) % this.window
+}
+
+// Samples 获取保留的每秒的统计，按照时间排序，不包含当前还没有结束的一秒
+func (this *SequenceSampler) Samples() []SequenceSample {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	var samples = make([]SequenceSample, 0, len(this.samples))
+	samples = append(samples, this.samples[this.next:]...)
+	return append(samples, this.samples[:this.next]...)
+}
+
+// Report 计算保留的统计的分位数
+func (this *SequenceSampler) Report() SequenceReport {
+	var samples = this.Samples()
+
+	this.mu.Lock()
+	var report = SequenceReport{Seconds: len(samples), Capacity: this.capacity}
+	this.mu.Unlock()
+
+	var ids = make([]int64, len(samples))
+	var milliseconds = make([]int64, len(samples))
+	var sequences = make([]int64, len(samples))
+	for i, sample := range samples {
+		ids[i] = sample.IDs
+		milliseconds[i] = sample.Milliseconds
+		sequences[i] = sample.MaxSequence
+		if sample.MaxSequence >= report.Capacity {
+			report.Exhausted++
+		}
+	}
+	report.IDs = percentilesOf(ids)
+	report.Milliseconds = percentilesOf(milliseconds)
+	report.MaxSequence = percentilesOf(sequences)
+	return report
+}
+
+func percentilesOf(values []int64) Percentiles {
+	if len(values) == 0 {
+		return Percentiles{}
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	var at = func(p int) int64 {
+		return values[(len(values)-1)*p/100]
+	}
+	return Percentiles{P50: at(50), P90: at(90), P99: at(99), Max: values[len(values)-1]}
+}
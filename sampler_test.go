@@ -0,0 +1,56 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSequenceSampler(t *testing.T) {
+	var sampler = NewSequenceSampler(2)
+	var s, _ = New(WithTimeOffset(testEpoch), WithSequenceSampler(sampler))
+	var now = s.anchor.Truncate(time.Second)
+	s.clock = func() time.Time { return now }
+
+	// 第一秒使用 2 个毫秒，每个毫秒分别生成 3 个和 10 个 id
+	for i := 0; i < 3; i++ {
+		s.Next()
+	}
+	now = now.Add(time.Millisecond)
+	for i := 0; i < 10; i++ {
+		s.Next()
+	}
+	// 第二秒用完一个毫秒的序列号
+	now = now.Add(time.Second)
+	if b, _ := s.NextBlock(kMaxSequence + 1); b.Count != kMaxSequence+1 {
+		t.Fatalf("NextBlock count = %d", b.Count)
+	}
+	// 第三秒开始之后前两秒的统计才会保存
+	now = now.Add(time.Second)
+	s.Next()
+
+	var samples = sampler.Samples()
+	if len(samples) != 2 {
+		t.Fatalf("samples = %+v", samples)
+	}
+	if samples[0].IDs != 13 || samples[0].Milliseconds != 2 || samples[0].MaxSequence != 10 {
+		t.Fatalf("first sample = %+v", samples[0])
+	}
+	if samples[1].IDs != kMaxSequence+1 || samples[1].MaxSequence != kMaxSequence+1 {
+		t.Fatalf("second sample = %+v", samples[1])
+	}
+
+	var report = sampler.Report()
+	if report.Seconds != 2 || report.Capacity != kMaxSequence+1 || report.Exhausted != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+	if report.MaxSequence.P50 != 10 || report.MaxSequence.Max != kMaxSequence+1 || report.Milliseconds.Max != 2 {
+		t.Fatalf("report percentiles = %+v", report)
+	}
+
+	// 超过保留的秒数之后覆盖最早的统计
+	now = now.Add(time.Second)
+	s.Next()
+	if samples = sampler.Samples(); len(samples) != 2 || samples[0].MaxSequence != kMaxSequence+1 || samples[1].IDs != 1 {
+		t.Fatalf("samples after wrap = %+v", samples)
+	}
+}
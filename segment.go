@@ -0,0 +1,196 @@
+package snowflake
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	kSegmentRetryInterval = time.Second // 后台获取号段失败之后，再次尝试之前需要等待的时长
+)
+
+var (
+	ErrSegmentNotFound = errors.New("snowflake: segment biz tag not found")
+	ErrInvalidSegment  = errors.New("snowflake: segment store returned an invalid segment")
+)
+
+// Segment 号段，包含 [Start, End) 范围内的 id
+type Segment struct {
+	Start int64
+	End   int64
+}
+
+// SegmentStore 为 SegmentGenerator 分配号段，每次调用都需要返回一个新的、没有分配过的号段
+type SegmentStore interface {
+	NextSegment(bizTag string) (Segment, error)
+}
+
+// SQLSegmentStore 基于数据库表实现的 SegmentStore，表结构参考美团 Leaf：
+//
+//	CREATE TABLE leaf_alloc (
+//		biz_tag VARCHAR(128) NOT NULL PRIMARY KEY,
+//		max_id  BIGINT       NOT NULL DEFAULT 1,
+//		step    INT          NOT NULL
+//	);
+//
+// 每次分配号段时会在事务中将 max_id 增加 step，得到的号段为 [max_id - step, max_id)。
+type SQLSegmentStore struct {
+	db     *sql.DB
+	update string
+	query  string
+}
+
+// NewSQLSegmentStore 创建使用 ? 作为占位符的 SQLSegmentStore，适用于 MySQL、SQLite 等数据库
+func NewSQLSegmentStore(db *sql.DB, table string) *SQLSegmentStore {
+	var s = &SQLSegmentStore{}
+	s.db = db
+	s.update = fmt.Sprintf("UPDATE %s SET max_id = max_id + step WHERE biz_tag = ?", table)
+	s.query = fmt.Sprintf("SELECT max_id, step FROM %s WHERE biz_tag = ?", table)
+	return s
+}
+
+// NewPostgresSegmentStore 创建使用 $1 作为占位符的 SQLSegmentStore，适用于 PostgreSQL
+func NewPostgresSegmentStore(db *sql.DB, table string) *SQLSegmentStore {
+	var s = &SQLSegmentStore{}
+	s.db = db
+	s.update = fmt.Sprintf("UPDATE %s SET max_id = max_id + step WHERE biz_tag = $1", table)
+	s.query = fmt.Sprintf("SELECT max_id, step FROM %s WHERE biz_tag = $1", table)
+	return s
+}
+
+// NextSegment 分配新的号段
+func (this *SQLSegmentStore) NextSegment(bizTag string) (seg Segment, err error) {
+	var tx *sql.Tx
+	if tx, err = this.db.Begin(); err != nil {
+		return seg, err
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var result sql.Result
+	if result, err = tx.Exec(this.update, bizTag); err != nil {
+		return seg, err
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return seg, ErrSegmentNotFound
+	}
+
+	var maxID, step int64
+	if err = tx.QueryRow(this.query, bizTag).Scan(&maxID, &step); err != nil {
+		return seg, err
+	}
+	if err = tx.Commit(); err != nil {
+		return seg, err
+	}
+	seg.Start = maxID - step
+	seg.End = maxID
+	return seg, nil
+}
+
+// SegmentGenerator 使用号段模式生成 id，语义与美团 Leaf 的号段模式一致。
+//
+// 内部使用双缓冲，当前号段使用超过 10% 以后会在后台预先获取下一个号段，当前号段用完以后直接切换，
+// 避免获取号段时阻塞 id 的生成，并且可以容忍 SegmentStore 短暂的不可用。
+// 后台获取失败之后会等待一段时间再重试，避免 SegmentStore 不可用时每次生成 id 都触发一次获取。
+type SegmentGenerator struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	store    SegmentStore
+	bizTag   string
+	current  Segment
+	value    int64
+	next     *Segment
+	loading  bool
+	failedAt time.Time // 最近一次后台获取号段失败的时间
+	now      func() time.Time
+}
+
+// NewSegmentGenerator 创建 SegmentGenerator，创建的时候会同步获取第一个号段
+func NewSegmentGenerator(store SegmentStore, bizTag string) (*SegmentGenerator, error) {
+	var seg, err = loadSegment(store, bizTag)
+	if err != nil {
+		return nil, err
+	}
+	var g = &SegmentGenerator{}
+	g.cond = sync.NewCond(&g.mu)
+	g.store = store
+	g.bizTag = bizTag
+	g.current = seg
+	g.value = seg.Start
+	g.now = time.Now
+	return g, nil
+}
+
+// NextID 生成新的 id
+func (this *SegmentGenerator) NextID() (int64, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	for this.value >= this.current.End {
+		for this.loading {
+			this.cond.Wait()
+		}
+		if this.next != nil {
+			this.current = *this.next
+			this.value = this.current.Start
+			this.next = nil
+			continue
+		}
+
+		// 后台没有可用的号段，同步获取
+		var seg, err = loadSegment(this.store, this.bizTag)
+		if err != nil {
+			return 0, err
+		}
+		this.current = seg
+		this.value = seg.Start
+	}
+
+	var id = this.value
+	this.value++
+
+	var step = this.current.End - this.current.Start
+	if this.next == nil && !this.loading && (this.value-this.current.Start)*10 >= step && this.canPrefetch() {
+		this.loading = true
+		go this.prefetch()
+	}
+	return id, nil
+}
+
+// canPrefetch 判断是否可以在后台获取下一个号段，上一次获取失败之后需要等待 kSegmentRetryInterval
+func (this *SegmentGenerator) canPrefetch() bool {
+	return this.failedAt.IsZero() || this.now().Sub(this.failedAt) >= kSegmentRetryInterval
+}
+
+func (this *SegmentGenerator) prefetch() {
+	var seg, err = loadSegment(this.store, this.bizTag)
+
+	this.mu.Lock()
+	this.loading = false
+	// 后台获取失败时只记录失败的时间，当前号段用完以后会同步获取并返回错误
+	if err == nil {
+		this.next = &seg
+		this.failedAt = time.Time{}
+	} else {
+		this.failedAt = this.now()
+	}
+	this.cond.Broadcast()
+	this.mu.Unlock()
+}
+
+func loadSegment(store SegmentStore, bizTag string) (Segment, error) {
+	var seg, err = store.NextSegment(bizTag)
+	if err != nil {
+		return seg, err
+	}
+	if seg.Start < 0 || seg.End <= seg.Start {
+		return seg, ErrInvalidSegment
+	}
+	return seg, nil
+}
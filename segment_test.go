@@ -0,0 +1,133 @@
+package snowflake
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memorySegmentStore struct {
+	mu    sync.Mutex
+	maxID int64
+	step  int64
+	calls int
+	err   error // 不为 nil 时分配号段失败
+}
+
+func (this *memorySegmentStore) NextSegment(bizTag string) (Segment, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if bizTag != "order" {
+		return Segment{}, ErrSegmentNotFound
+	}
+	this.calls++
+	if this.err != nil {
+		return Segment{}, this.err
+	}
+	this.maxID += this.step
+	return Segment{Start: this.maxID - this.step, End: this.maxID}, nil
+}
+
+func TestSegmentGenerator(t *testing.T) {
+	var store = &memorySegmentStore{maxID: 1, step: 100}
+	var g, err = NewSegmentGenerator(store, "order")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var seen = make(map[int64]struct{})
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 500; j++ {
+				var id, err = g.NextID()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mu.Lock()
+				seen[id] = struct{}{}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(seen) != 4000 {
+		t.Fatalf("got %d distinct ids, want 4000", len(seen))
+	}
+	for id := int64(1); id <= 4000; id++ {
+		if _, ok := seen[id]; !ok {
+			t.Fatalf("id %d was skipped", id)
+		}
+	}
+
+	if _, err = NewSegmentGenerator(store, "unknown"); err != ErrSegmentNotFound {
+		t.Fatalf("NewSegmentGenerator() error = %v, want %v", err, ErrSegmentNotFound)
+	}
+}
+
+func TestSegmentGenerator_PrefetchBackoff(t *testing.T) {
+	var store = &memorySegmentStore{maxID: 1, step: 100}
+	var g, err = NewSegmentGenerator(store, "order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var now = time.Date(2024, 6, 11, 8, 0, 0, 0, time.UTC)
+	g.now = func() time.Time { return now }
+
+	store.mu.Lock()
+	store.err = errors.New("store unavailable")
+	store.mu.Unlock()
+
+	var calls = func() int {
+		// 等待后台的获取结束
+		for {
+			g.mu.Lock()
+			var loading = g.loading
+			g.mu.Unlock()
+			if !loading {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		return store.calls
+	}
+
+	for i := 0; i < 50; i++ {
+		if _, err = g.NextID(); err != nil {
+			t.Fatal(err)
+		}
+		calls()
+	}
+	if n := calls(); n != 2 {
+		t.Fatalf("NextSegment called %d times, want 2", n)
+	}
+
+	// 超过重试的间隔之后再次尝试获取
+	now = now.Add(kSegmentRetryInterval)
+	store.mu.Lock()
+	store.err = nil
+	store.mu.Unlock()
+	if _, err = g.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	if n := calls(); n != 3 {
+		t.Fatalf("NextSegment called %d times, want 3", n)
+	}
+	for i := 0; i < 50; i++ {
+		if _, err = g.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := calls(); n != 3 {
+		t.Fatalf("NextSegment called %d times, want 3", n)
+	}
+}
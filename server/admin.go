@@ -0,0 +1,112 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Admin 管理机器标识分配的接口，用于迁移等场景下由运维人员调整分配情况：
+//
+//	GET  /admin/workers                                         查看分配情况，返回 WorkerList
+//	POST /admin/workers/revoke?data_center=1&machine=2          撤销租约
+//	POST /admin/workers/reserve?data_center=1&first=0&last=3    预留机器标识，可以通过 note 参数添加说明
+//	POST /admin/datacenters/decommission?data_center=1          下线数据中心
+//
+// 管理接口需要与 id 服务的接口分开部署或者设置更严格的认证方式。
+type Admin struct {
+	workers *Workers
+	auth    Authenticator
+	mux     *http.ServeMux
+}
+
+// NewAdmin 创建 Admin，auth 为 nil 时不进行认证
+func NewAdmin(workers *Workers, auth Authenticator) *Admin {
+	var a = &Admin{}
+	a.workers = workers
+	a.auth = auth
+	a.mux = http.NewServeMux()
+	a.mux.HandleFunc("/admin/workers", a.handleList)
+	a.mux.HandleFunc("/admin/workers/revoke", a.handleRevoke)
+	a.mux.HandleFunc("/admin/workers/reserve", a.handleReserve)
+	a.mux.HandleFunc("/admin/datacenters/decommission", a.handleDecommission)
+	return a
+}
+
+func (this *Admin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if this.auth != nil {
+		if _, err := this.auth.Authenticate(r); err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+	}
+	this.mux.ServeHTTP(w, r)
+}
+
+func (this *Admin) handleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeJSON(w, http.StatusOK, this.workers.List())
+}
+
+func (this *Admin) handleRevoke(w http.ResponseWriter, r *http.Request) {
+	var values, ok = queryInts(w, r, "data_center", "machine")
+	if !ok {
+		return
+	}
+	if err := this.workers.Revoke(values[0], values[1]); err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (this *Admin) handleReserve(w http.ResponseWriter, r *http.Request) {
+	var values, ok = queryInts(w, r, "data_center", "first", "last")
+	if !ok {
+		return
+	}
+	switch err := this.workers.Reserve(values[0], values[1], values[2], r.URL.Query().Get("note")); err {
+	case nil:
+		writeJSON(w, http.StatusOK, struct{}{})
+	case ErrInvalidWorker:
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		writeError(w, http.StatusConflict, err.Error())
+	}
+}
+
+func (this *Admin) handleDecommission(w http.ResponseWriter, r *http.Request) {
+	var values, ok = queryInts(w, r, "data_center")
+	if !ok {
+		return
+	}
+	var revoked, err = this.workers.Decommission(values[0])
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Revoked int `json:"revoked"`
+	}{revoked})
+}
+
+// queryInts 读取 POST 请求中的整数参数，请求方法或者参数不正确时直接返回错误
+func queryInts(w http.ResponseWriter, r *http.Request, names ...string) ([]int64, bool) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return nil, false
+	}
+	var values = make([]int64, len(names))
+	for i, name := range names {
+		var v, err = strconv.ParseInt(r.URL.Query().Get(name), 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid "+name)
+			return nil, false
+		}
+		values[i] = v
+	}
+	return values, true
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+)
+
+func TestAdmin(t *testing.T) {
+	var workers = NewWorkers(snowflake.DefaultLayout, time.Minute)
+	var srv = httptest.NewServer(NewAdmin(workers, StaticTokens(map[string]string{"ops": "ops"})))
+	defer srv.Close()
+
+	var do = func(method, path string) *http.Response {
+		var req, _ = http.NewRequest(method, srv.URL+path, nil)
+		req.Header.Set("Authorization", "Bearer ops")
+		var rsp, err = http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rsp
+	}
+
+	var lease, _ = workers.Acquire("node-1")
+	var tests = []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodPost, "/admin/workers/reserve?data_center=1&first=0&last=3&note=fallback", http.StatusOK},
+		{http.MethodPost, "/admin/workers/reserve?data_center=0&first=0&last=0", http.StatusConflict},
+		{http.MethodPost, "/admin/workers/reserve?data_center=1&first=3&last=0", http.StatusBadRequest},
+		{http.MethodPost, "/admin/workers/revoke?data_center=0&machine=0", http.StatusOK},
+		{http.MethodPost, "/admin/workers/revoke?data_center=0&machine=0", http.StatusNotFound},
+		{http.MethodPost, "/admin/workers/revoke?data_center=x", http.StatusBadRequest},
+		{http.MethodGet, "/admin/workers/revoke?data_center=0&machine=0", http.StatusMethodNotAllowed},
+		{http.MethodPost, "/admin/datacenters/decommission?data_center=2", http.StatusOK},
+	}
+	for _, test := range tests {
+		var rsp = do(test.method, test.path)
+		rsp.Body.Close()
+		if rsp.StatusCode != test.status {
+			t.Fatalf("%s %s = %d, want %d", test.method, test.path, rsp.StatusCode, test.status)
+		}
+	}
+	if _, err := workers.Renew(lease.DataCenter, lease.Machine, "node-1"); err != ErrLeaseNotFound {
+		t.Fatalf("Renew() after revoke error = %v, want %v", err, ErrLeaseNotFound)
+	}
+
+	var rsp = do(http.MethodGet, "/admin/workers")
+	defer rsp.Body.Close()
+	var list WorkerList
+	if err := json.NewDecoder(rsp.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Reservations) != 1 || list.Reservations[0].Note != "fallback" || len(list.Decommissioned) != 1 || list.Decommissioned[0] != 2 {
+		t.Fatalf("GET /admin/workers = %+v", list)
+	}
+
+	if rsp, _ = http.Get(srv.URL + "/admin/workers"); rsp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("GET /admin/workers without token = %d, want 401", rsp.StatusCode)
+	}
+}
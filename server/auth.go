@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrUnauthenticated = errors.New("snowflake: client is not authenticated")
+)
+
+const (
+	AuthMTLS  = "mtls"  // 通过 mTLS 客户端证书认证
+	AuthToken = "token" // 通过 bearer token 认证
+)
+
+// Identity 调用 id 服务的客户端身份
+type Identity struct {
+	Name   string // 客户端名称，mTLS 为证书的 CommonName，token 为 token 对应的名称
+	Method string // 认证方式，AuthMTLS 或者 AuthToken
+}
+
+type identityKey struct{}
+
+// IdentityFromContext 获取通过认证的客户端身份，用于在配额、审计等处理中区分客户端
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	var identity, ok = ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
+// Authenticator 认证客户端，认证失败时返回错误
+type Authenticator interface {
+	Authenticate(r *http.Request) (Identity, error)
+}
+
+// AuthenticatorFunc 使用函数实现 Authenticator
+type AuthenticatorFunc func(r *http.Request) (Identity, error)
+
+func (f AuthenticatorFunc) Authenticate(r *http.Request) (Identity, error) {
+	return f(r)
+}
+
+// ClientCertAuth 使用 mTLS 客户端证书认证，客户端名称为证书的 CommonName，需要配合 MutualTLSConfig 使用
+func ClientCertAuth() Authenticator {
+	return AuthenticatorFunc(func(r *http.Request) (Identity, error) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+			return Identity{}, ErrUnauthenticated
+		}
+		var name = r.TLS.VerifiedChains[0][0].Subject.CommonName
+		if name == "" {
+			return Identity{}, ErrUnauthenticated
+		}
+		return Identity{Name: name, Method: AuthMTLS}, nil
+	})
+}
+
+// BearerAuth 使用 Authorization: Bearer <token> 认证，verify 用于校验 token 并返回客户端名称，
+// 如使用 OIDC 时可以通过 github.com/coreos/go-oidc 校验 ID Token 并返回其中的 subject。
+func BearerAuth(verify func(ctx context.Context, token string) (string, error)) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request) (Identity, error) {
+		var header = r.Header.Get("Authorization")
+		if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+			return Identity{}, ErrUnauthenticated
+		}
+		var name, err = verify(r.Context(), strings.TrimSpace(header[7:]))
+		if err != nil {
+			return Identity{}, err
+		}
+		return Identity{Name: name, Method: AuthToken}, nil
+	})
+}
+
+// StaticTokens 使用固定的 token 认证，tokens 的 key 为 token，value 为客户端名称
+func StaticTokens(tokens map[string]string) Authenticator {
+	var copied = make(map[string]string, len(tokens))
+	for token, name := range tokens {
+		copied[token] = name
+	}
+	return BearerAuth(func(ctx context.Context, token string) (string, error) {
+		// 逐个使用固定时间的比较，避免通过响应时间猜测 token
+		var found string
+		for t, name := range copied {
+			if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+				found = name
+			}
+		}
+		if found == "" {
+			return "", ErrUnauthenticated
+		}
+		return found, nil
+	})
+}
+
+// AnyAuth 依次尝试多种认证方式，任意一种认证成功即可，如同时支持 mTLS 和 token 的客户端
+func AnyAuth(auths ...Authenticator) Authenticator {
+	return AuthenticatorFunc(func(r *http.Request) (Identity, error) {
+		var err error = ErrUnauthenticated
+		for _, auth := range auths {
+			var identity Identity
+			if identity, err = auth.Authenticate(r); err == nil {
+				return identity, nil
+			}
+		}
+		return Identity{}, err
+	})
+}
+
+// MutualTLSConfig 创建要求客户端提供证书的 tls.Config，clientCAs 用于校验客户端证书
+func MutualTLSConfig(cert tls.Certificate, clientCAs *x509.CertPool) *tls.Config {
+	var config = &tls.Config{}
+	config.Certificates = []tls.Certificate{cert}
+	config.ClientCAs = clientCAs
+	config.ClientAuth = tls.RequireAndVerifyClientCert
+	config.MinVersion = tls.VersionTLS12
+	return config
+}
@@ -0,0 +1,163 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+	"github.com/smartwalle/snowflake/audit"
+)
+
+func TestServer_TokenAuth(t *testing.T) {
+	var generator, _ = snowflake.New()
+
+	var mu sync.Mutex
+	var labels = make(map[string]int)
+	var writer = audit.NewWriter(audit.SinkFunc(func(records []audit.Record) error {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, record := range records {
+			labels[record.Label]++
+		}
+		return nil
+	}))
+
+	var admitted []Identity
+	var srv = httptest.NewServer(New(generator,
+		WithAuth(StaticTokens(map[string]string{"order-secret": "order", "bad-secret": "bad"})),
+		WithAdmission(func(identity Identity, count int64) error {
+			admitted = append(admitted, identity)
+			if identity.Name == "bad" {
+				return errors.New("quota exceeded")
+			}
+			return nil
+		}),
+		WithAudit(writer),
+	))
+	defer srv.Close()
+
+	var post = func(path, token string) int {
+		var req, _ = http.NewRequest(http.MethodPost, srv.URL+path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		var rsp, err = http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsp.Body.Close()
+		return rsp.StatusCode
+	}
+
+	if status := post("/next", ""); status != http.StatusUnauthorized {
+		t.Fatalf("POST /next without token = %d, want 401", status)
+	}
+	if status := post("/next", "wrong"); status != http.StatusUnauthorized {
+		t.Fatalf("POST /next with wrong token = %d, want 401", status)
+	}
+	if status := post("/batch?count=10", "order-secret"); status != http.StatusOK {
+		t.Fatalf("POST /batch = %d, want 200", status)
+	}
+	if status := post("/next", "bad-secret"); status != http.StatusTooManyRequests {
+		t.Fatalf("POST /next over quota = %d, want 429", status)
+	}
+	if rsp, err := http.Get(srv.URL + "/health"); err != nil || rsp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /health = %v, %v, want 200 without auth", rsp, err)
+	}
+
+	writer.Close()
+	if labels["order"] != 10 || len(labels) != 1 {
+		t.Fatalf("audit labels = %v, want 10 records for order", labels)
+	}
+	if len(admitted) != 2 || admitted[0] != (Identity{Name: "order", Method: AuthToken}) {
+		t.Fatalf("admitted = %+v", admitted)
+	}
+}
+
+func TestServer_MutualTLS(t *testing.T) {
+	var ca, caKey = newCert(t, "test-ca", nil, nil)
+	var serverCert, _ = newCert(t, "127.0.0.1", ca, caKey)
+	var clientCert, _ = newCert(t, "billing", ca, caKey)
+
+	var pool = x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+
+	var generator, _ = snowflake.New()
+	var identity Identity
+	var srv = httptest.NewUnstartedServer(New(generator, WithAuth(ClientCertAuth()), WithAdmission(func(i Identity, count int64) error {
+		identity = i
+		return nil
+	})))
+	srv.TLS = MutualTLSConfig(*serverCert, pool)
+	srv.StartTLS()
+	defer srv.Close()
+
+	var transport = &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, Certificates: []tls.Certificate{*clientCert}}}
+	var client = &http.Client{Transport: transport}
+	var rsp, err = client.Post(srv.URL+"/next", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK || identity != (Identity{Name: "billing", Method: AuthMTLS}) {
+		t.Fatalf("POST /next = %d, identity = %+v", rsp.StatusCode, identity)
+	}
+
+	// 没有客户端证书时无法完成握手
+	var anonymous = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	if rsp, err = anonymous.Post(srv.URL+"/next", "application/json", nil); err == nil {
+		rsp.Body.Close()
+		t.Fatalf("POST /next without client certificate = %d, want handshake error", rsp.StatusCode)
+	}
+}
+
+// newCert 创建由 parent 签发的证书，parent 为 nil 时创建自签名的 CA 证书
+func newCert(t *testing.T, name string, parent *tls.Certificate, parentKey *ecdsa.PrivateKey) (*tls.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	var key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var template = &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	var signer, signerKey = template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		signer, signerKey = parent.Leaf, parentKey
+		if ip := net.ParseIP(name); ip != nil {
+			template.IPAddresses = []net.IP{ip}
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, key
+}
@@ -0,0 +1,38 @@
+package server
+
+import (
+	"net/http"
+)
+
+// Route id 服务的一个接口。client 包中 Client 的接口方法由 Routes 生成，修改接口之后需要在 client 目录下执行 go generate
+type Route struct {
+	Name     string  // 接口名称，同时作为 Client 的方法名
+	Method   string  // http 方法
+	Path     string  // 路径
+	Doc      string  // Client 方法的注释
+	Params   []Param // query 参数，按照顺序作为 Client 方法的参数
+	Response string  // 响应的类型，server 包以外的类型需要带包名，如 snowflake.Parts
+	Field    *Param  // 设置之后 Client 的方法只返回响应中的该字段
+}
+
+// Param 接口的 query 参数或者响应中的字段
+type Param struct {
+	Name string // query 参数的名称或者响应中字段的名称
+	Type string // Go 类型，如 int、int64、[]int64
+}
+
+// Routes 获取 id 服务提供的所有接口
+func Routes() []Route {
+	return []Route{
+		{Name: "Lease", Method: http.MethodPost, Path: "/lease", Doc: "租用 count 个连续的 id，需要在本地缓存 id 时使用 LeaseClient",
+			Params: []Param{{Name: "count", Type: "int64"}}, Response: "Lease"},
+		{Name: "Next", Method: http.MethodPost, Path: "/next", Doc: "获取一个 id",
+			Response: "ID", Field: &Param{Name: "ID", Type: "int64"}},
+		{Name: "Batch", Method: http.MethodPost, Path: "/batch", Doc: "获取 count 个 id，count 不能超过 1000",
+			Params: []Param{{Name: "count", Type: "int"}}, Response: "IDs", Field: &Param{Name: "IDs", Type: "[]int64"}},
+		{Name: "Decode", Method: http.MethodGet, Path: "/decode", Doc: "按照 id 服务的布局和时间偏移量解析 id",
+			Params: []Param{{Name: "id", Type: "int64"}}, Response: "snowflake.Parts"},
+		{Name: "Health", Method: http.MethodGet, Path: "/health", Doc: "获取 id 服务使用的数据中心和机器标识，可以用于检查服务是否可用",
+			Response: "Health"},
+	}
+}
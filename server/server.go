@@ -0,0 +1,271 @@
+// Package server 提供 id 服务的 HTTP 接口。
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+	"github.com/smartwalle/snowflake/audit"
+)
+
+const (
+	kDefaultLeaseTTL   = time.Minute
+	kDefaultLeaseCount = 1000
+	kMaxLeaseCount     = 1 << 16
+	kMaxBatchCount     = 1000
+)
+
+// Lease 租用的一段连续的 id，客户端需要在收到之后的 TTL 时间内用完，过期之后剩余的 id 不应该再使用。
+//
+// 有效期使用时长而不是时间点表示，避免客户端与服务端的时钟不一致导致租用的 id 无法使用。
+type Lease struct {
+	snowflake.Block
+	TTL time.Duration `json:"ttl"` // 有效期，单位是纳秒
+}
+
+// ID /next 接口返回的 id
+type ID struct {
+	ID int64 `json:"id"`
+}
+
+// IDs /batch 接口返回的 id
+type IDs struct {
+	IDs []int64 `json:"ids"`
+}
+
+// Health /health 接口返回的服务信息
+type Health struct {
+	DataCenter int64 `json:"data_center"`
+	Machine    int64 `json:"machine"`
+}
+
+// Error 接口返回的错误信息
+type Error struct {
+	Error string `json:"error"`
+}
+
+type Option func(s *Server)
+
+// WithLeaseTTL 设置租用的 id 的有效期，默认为 1 分钟
+func WithLeaseTTL(ttl time.Duration) Option {
+	return func(s *Server) {
+		if ttl > 0 {
+			s.leaseTTL = ttl
+		}
+	}
+}
+
+// WithAuth 设置客户端认证方式，设置之后除 /health 以外的接口都需要认证，认证失败时返回 401，
+// 通过认证的客户端身份可以在请求的 context 中通过 IdentityFromContext 获取
+func WithAuth(auth Authenticator) Option {
+	return func(s *Server) {
+		s.auth = auth
+	}
+}
+
+// WithAdmission 设置生成 id 之前的检查，如按照客户端限制配额，count 为本次请求的 id 数量，返回错误时拒绝请求并返回 429
+func WithAdmission(admit func(identity Identity, count int64) error) Option {
+	return func(s *Server) {
+		s.admit = admit
+	}
+}
+
+// WithAudit 记录生成的每一个 id，记录的 Label 为客户端名称，没有设置 WithAuth 时为空
+func WithAudit(writer *audit.Writer) Option {
+	return func(s *Server) {
+		s.audit = writer
+	}
+}
+
+// Server id 服务，提供以下接口，接口的定义见 Routes：
+//
+//	POST /lease?count=1000 租用一段连续的 id，返回 Lease
+//	POST /next             生成一个 id，返回 ID
+//	POST /batch?count=100  生成多个 id，返回 IDs，count 不能超过 1000
+//	GET  /decode?id=...    解析 id，返回 snowflake.Parts
+//	GET  /health           健康检查，返回 Health，用于注册中心和客户端判断服务是否可用
+//
+// 同一段 id 使用的是同一毫秒内连续的序列号，所以返回的数量不会超过一毫秒可以生成的 id 数量（默认布局下为 4096），
+// count 超过这个数量时只会返回当前毫秒剩余的部分，客户端需要以实际返回的 Count 为准。
+type Server struct {
+	generator *snowflake.SnowFlake
+	leaseTTL  time.Duration
+	auth      Authenticator
+	admit     func(identity Identity, count int64) error
+	audit     *audit.Writer
+	mux       *http.ServeMux
+}
+
+func New(generator *snowflake.SnowFlake, opts ...Option) *Server {
+	var s = &Server{}
+	s.generator = generator
+	s.leaseTTL = kDefaultLeaseTTL
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	// 只注册 Routes 中的接口，保证生成的 Client 与服务端一致
+	var handlers = map[string]http.HandlerFunc{
+		"Lease":  s.handleLease,
+		"Next":   s.handleNext,
+		"Batch":  s.handleBatch,
+		"Decode": s.handleDecode,
+		"Health": s.handleHealth,
+	}
+	s.mux = http.NewServeMux()
+	for _, route := range Routes() {
+		s.mux.HandleFunc(route.Path, handlers[route.Name])
+	}
+	return s
+}
+
+func (this *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if this.auth != nil && r.URL.Path != "/health" {
+		var identity, err = this.auth.Authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		r = r.WithContext(context.WithValue(r.Context(), identityKey{}, identity))
+	}
+	this.mux.ServeHTTP(w, r)
+}
+
+// admitted 检查是否允许为客户端生成 count 个 id，不允许时返回 429
+func (this *Server) admitted(w http.ResponseWriter, r *http.Request, count int64) bool {
+	if this.admit == nil {
+		return true
+	}
+	var identity, _ = IdentityFromContext(r.Context())
+	if err := this.admit(identity, count); err != nil {
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return false
+	}
+	return true
+}
+
+// record 将生成的 id 写入审计记录
+func (this *Server) record(r *http.Request, ids ...int64) {
+	if this.audit == nil {
+		return
+	}
+	var identity, _ = IdentityFromContext(r.Context())
+	for _, id := range ids {
+		this.audit.Record(id, identity.Name)
+	}
+}
+
+func (this *Server) handleLease(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var count int64 = kDefaultLeaseCount
+	if v := r.URL.Query().Get("count"); v != "" {
+		var err error
+		if count, err = strconv.ParseInt(v, 10, 64); err != nil || count <= 0 || count > kMaxLeaseCount {
+			writeError(w, http.StatusBadRequest, "invalid count")
+			return
+		}
+	}
+
+	if !this.admitted(w, r, count) {
+		return
+	}
+	var block, err = this.generator.NextBlock(count)
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	if this.audit != nil {
+		var ids = make([]int64, 0, block.Count)
+		for i := int64(0); i < block.Count; i++ {
+			ids = append(ids, block.Start+i)
+		}
+		this.record(r, ids...)
+	}
+
+	var lease = Lease{}
+	lease.Block = block
+	lease.TTL = this.leaseTTL
+	writeJSON(w, http.StatusOK, lease)
+}
+
+func (this *Server) handleNext(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if !this.admitted(w, r, 1) {
+		return
+	}
+	var id, err = this.generator.NextID()
+	if err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	this.record(r, id)
+	writeJSON(w, http.StatusOK, ID{ID: id})
+}
+
+func (this *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var count, err = strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil || count <= 0 || count > kMaxBatchCount {
+		writeError(w, http.StatusBadRequest, "invalid count")
+		return
+	}
+	if !this.admitted(w, r, int64(count)) {
+		return
+	}
+	var ids = make([]int64, count)
+	if err = this.generator.Fill(ids); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	this.record(r, ids...)
+	writeJSON(w, http.StatusOK, IDs{IDs: ids})
+}
+
+func (this *Server) handleDecode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var id, err = snowflake.ParseDecimal(r.URL.Query().Get("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, this.generator.Decode(id))
+}
+
+func (this *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var health = Health{}
+	health.DataCenter = this.generator.DataCenterID()
+	health.Machine = this.generator.MachineID()
+	writeJSON(w, http.StatusOK, health)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, Error{Error: message})
+}
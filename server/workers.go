@@ -0,0 +1,213 @@
+package server
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+)
+
+var (
+	ErrNoWorker       = errors.New("snowflake: no free worker id")
+	ErrLeaseNotFound  = errors.New("snowflake: worker lease not found or revoked")
+	ErrWorkerInUse    = errors.New("snowflake: worker id is leased")
+	ErrInvalidWorker  = errors.New("snowflake: worker id out of range")
+	ErrDecommissioned = errors.New("snowflake: data center is decommissioned")
+)
+
+// WorkerLease 租用的机器标识，租约需要在 ExpiresAt 之前通过 Renew 续期，否则会被分配给其它实例
+type WorkerLease struct {
+	DataCenter int64     `json:"data_center"`
+	Machine    int64     `json:"machine"`
+	Owner      string    `json:"owner"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Reservation 预留的机器标识范围，包含 [First, Last]，预留的机器标识不会通过 Acquire 分配，如留给降级使用的本地生成器
+type Reservation struct {
+	DataCenter int64  `json:"data_center"`
+	First      int64  `json:"first"`
+	Last       int64  `json:"last"`
+	Note       string `json:"note"`
+}
+
+// WorkerList Workers 的当前状态
+type WorkerList struct {
+	Leases         []WorkerLease `json:"leases"`
+	Reservations   []Reservation `json:"reservations"`
+	Decommissioned []int64       `json:"decommissioned"`
+}
+
+type workerKey struct {
+	dataCenter int64
+	machine    int64
+}
+
+// Workers 在内存中管理数据中心标识和机器标识的分配，id 服务的实例启动时通过 Acquire 获取机器标识，运行期间定期 Renew。
+//
+// 运维人员可以通过 Admin 查看分配情况、撤销租约、预留机器标识以及下线数据中心。
+type Workers struct {
+	maxDataCenter int64
+	maxMachine    int64
+	ttl           time.Duration
+
+	mu             sync.Mutex
+	leases         map[workerKey]WorkerLease
+	reservations   []Reservation
+	decommissioned map[int64]bool
+	now            func() time.Time
+}
+
+// NewWorkers 创建 Workers，layout 决定数据中心标识和机器标识的范围，ttl 为租约的有效期
+func NewWorkers(layout snowflake.Layout, ttl time.Duration) *Workers {
+	var w = &Workers{}
+	w.maxDataCenter = -1 ^ (-1 << layout.DataCenter)
+	w.maxMachine = -1 ^ (-1 << layout.Machine)
+	w.ttl = ttl
+	w.leases = make(map[workerKey]WorkerLease)
+	w.decommissioned = make(map[int64]bool)
+	w.now = time.Now
+	return w
+}
+
+// Acquire 为 owner 分配一个空闲的机器标识，owner 已经持有租约时续期并返回该租约
+func (this *Workers) Acquire(owner string) (WorkerLease, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	var now = this.now()
+	for key, lease := range this.leases {
+		if lease.Owner == owner && now.Before(lease.ExpiresAt) {
+			lease.ExpiresAt = now.Add(this.ttl)
+			this.leases[key] = lease
+			return lease, nil
+		}
+	}
+
+	for dc := int64(0); dc <= this.maxDataCenter; dc++ {
+		if this.decommissioned[dc] {
+			continue
+		}
+		for m := int64(0); m <= this.maxMachine; m++ {
+			var key = workerKey{dataCenter: dc, machine: m}
+			if lease, ok := this.leases[key]; (ok && now.Before(lease.ExpiresAt)) || this.reserved(dc, m) {
+				continue
+			}
+			var lease = WorkerLease{DataCenter: dc, Machine: m, Owner: owner, ExpiresAt: now.Add(this.ttl)}
+			this.leases[key] = lease
+			return lease, nil
+		}
+	}
+	return WorkerLease{}, ErrNoWorker
+}
+
+// Renew 续期 owner 持有的租约，租约已经过期、被撤销或者所在的数据中心已经下线时返回 ErrLeaseNotFound，实例需要停止使用该机器标识
+func (this *Workers) Renew(dataCenter, machine int64, owner string) (WorkerLease, error) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	var now = this.now()
+	var key = workerKey{dataCenter: dataCenter, machine: machine}
+	var lease, ok = this.leases[key]
+	if !ok || lease.Owner != owner || !now.Before(lease.ExpiresAt) {
+		return WorkerLease{}, ErrLeaseNotFound
+	}
+	lease.ExpiresAt = now.Add(this.ttl)
+	this.leases[key] = lease
+	return lease, nil
+}
+
+// Revoke 撤销租约，持有租约的实例下一次 Renew 时会失败
+func (this *Workers) Revoke(dataCenter, machine int64) error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	var key = workerKey{dataCenter: dataCenter, machine: machine}
+	if _, ok := this.leases[key]; !ok {
+		return ErrLeaseNotFound
+	}
+	delete(this.leases, key)
+	return nil
+}
+
+// Reserve 预留数据中心 dataCenter 中 [first, last] 范围内的机器标识，范围内有未过期的租约时返回 ErrWorkerInUse
+func (this *Workers) Reserve(dataCenter, first, last int64, note string) error {
+	if dataCenter < 0 || dataCenter > this.maxDataCenter || first < 0 || last > this.maxMachine || first > last {
+		return ErrInvalidWorker
+	}
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if this.decommissioned[dataCenter] {
+		return ErrDecommissioned
+	}
+	var now = this.now()
+	for key, lease := range this.leases {
+		if key.dataCenter == dataCenter && key.machine >= first && key.machine <= last && now.Before(lease.ExpiresAt) {
+			return ErrWorkerInUse
+		}
+	}
+	this.reservations = append(this.reservations, Reservation{DataCenter: dataCenter, First: first, Last: last, Note: note})
+	return nil
+}
+
+// Decommission 下线数据中心，撤销该数据中心所有的租约并且不再分配其中的机器标识，返回撤销的租约数量
+func (this *Workers) Decommission(dataCenter int64) (int, error) {
+	if dataCenter < 0 || dataCenter > this.maxDataCenter {
+		return 0, ErrInvalidWorker
+	}
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	var revoked int
+	for key := range this.leases {
+		if key.dataCenter == dataCenter {
+			delete(this.leases, key)
+			revoked++
+		}
+	}
+	this.decommissioned[dataCenter] = true
+	return revoked, nil
+}
+
+// List 获取未过期的租约、预留的机器标识和已经下线的数据中心
+func (this *Workers) List() WorkerList {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	var now = this.now()
+	var list = WorkerList{}
+	list.Leases = make([]WorkerLease, 0, len(this.leases))
+	for _, lease := range this.leases {
+		if now.Before(lease.ExpiresAt) {
+			list.Leases = append(list.Leases, lease)
+		}
+	}
+	sort.Slice(list.Leases, func(i, j int) bool {
+		var a, b = list.Leases[i], list.Leases[j]
+		return a.DataCenter < b.DataCenter || (a.DataCenter == b.DataCenter && a.Machine < b.Machine)
+	})
+	list.Reservations = append([]Reservation{}, this.reservations...)
+	list.Decommissioned = make([]int64, 0, len(this.decommissioned))
+	for dc := range this.decommissioned {
+		list.Decommissioned = append(list.Decommissioned, dc)
+	}
+	sort.Slice(list.Decommissioned, func(i, j int) bool {
+		return list.Decommissioned[i] < list.Decommissioned[j]
+	})
+	return list
+}
+
+// reserved 机器标识是否被预留，调用方需要持有 mu
+func (this *Workers) reserved(dataCenter, machine int64) bool {
+	for _, r := range this.reservations {
+		if r.DataCenter == dataCenter && machine >= r.First && machine <= r.Last {
+			return true
+		}
+	}
+	return false
+}
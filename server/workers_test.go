@@ -0,0 +1,70 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/smartwalle/snowflake"
+)
+
+func TestWorkers(t *testing.T) {
+	var workers = NewWorkers(snowflake.Layout{DataCenter: 1, Machine: 2, Sequence: 12}, time.Minute)
+	var now = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	workers.now = func() time.Time { return now }
+
+	if err := workers.Reserve(0, 0, 1, "fallback"); err != nil {
+		t.Fatal(err)
+	}
+	var a, err = workers.Acquire("a")
+	if err != nil || a.DataCenter != 0 || a.Machine != 2 {
+		t.Fatalf("Acquire(a) = %+v, %v, want data center 0 machine 2", a, err)
+	}
+	if again, _ := workers.Acquire("a"); again.Machine != a.Machine {
+		t.Fatalf("Acquire(a) again = %+v, want the same lease %+v", again, a)
+	}
+	if err = workers.Reserve(0, 2, 3, ""); err != ErrWorkerInUse {
+		t.Fatalf("Reserve() error = %v, want %v", err, ErrWorkerInUse)
+	}
+
+	// 过期的租约可以分配给其它实例
+	now = now.Add(2 * time.Minute)
+	if _, err = workers.Renew(a.DataCenter, a.Machine, "a"); err != ErrLeaseNotFound {
+		t.Fatalf("Renew() of an expired lease error = %v, want %v", err, ErrLeaseNotFound)
+	}
+	b, _ := workers.Acquire("b")
+	if b.Machine != a.Machine {
+		t.Fatalf("Acquire(b) = %+v, want the expired machine %d", b, a.Machine)
+	}
+	if _, err = workers.Renew(b.DataCenter, b.Machine, "b"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = workers.Revoke(b.DataCenter, b.Machine); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = workers.Renew(b.DataCenter, b.Machine, "b"); err != ErrLeaseNotFound {
+		t.Fatalf("Renew() of a revoked lease error = %v, want %v", err, ErrLeaseNotFound)
+	}
+
+	workers.Acquire("c")
+	workers.Acquire("d")
+	if revoked, _ := workers.Decommission(0); revoked != 2 {
+		t.Fatalf("Decommission(0) revoked %d leases, want 2", revoked)
+	}
+	for _, owner := range []string{"e", "f", "g", "h"} {
+		if lease, _ := workers.Acquire(owner); lease.DataCenter != 1 {
+			t.Fatalf("Acquire(%s) = %+v, want data center 1", owner, lease)
+		}
+	}
+	if _, err = workers.Acquire("i"); err != ErrNoWorker {
+		t.Fatalf("Acquire() error = %v, want %v", err, ErrNoWorker)
+	}
+
+	var list = workers.List()
+	if len(list.Leases) != 4 || len(list.Reservations) != 1 || len(list.Decommissioned) != 1 {
+		t.Fatalf("List() = %+v", list)
+	}
+	if err = workers.Reserve(0, 0, 4, ""); err != ErrInvalidWorker {
+		t.Fatalf("Reserve() error = %v, want %v", err, ErrInvalidWorker)
+	}
+}
@@ -0,0 +1,61 @@
+package snowflake
+
+import (
+	"errors"
+)
+
+var (
+	ErrNoShard = errors.New("snowflake: shard router needs at least one shard")
+)
+
+// Shard 使用 Jump Consistent Hash 计算 id 所在的分片，shards 为分片的数量，小于等于 0 时返回 0。
+//
+// 与 id % shards 相比，分片的数量从 n 增加到 n+1 时，只有大约 1/(n+1) 的 id 会迁移到新的分片，其它 id 所在的分片保持不变。
+func Shard(id int64, shards int) int {
+	if shards <= 0 {
+		return 0
+	}
+	var key = mix64(uint64(id))
+	var b, j int64 = -1, 0
+	for j < int64(shards) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+// ShardRouter 将 id 路由到指定名称的分片，如数据库实例、表名等。
+//
+// 只支持在末尾增加分片，这样扩容时已有的 id 要么保持在原来的分片，要么迁移到新增加的分片。
+type ShardRouter struct {
+	shards []string
+}
+
+// NewShardRouter 创建 ShardRouter，shards 为分片的名称
+func NewShardRouter(shards ...string) (*ShardRouter, error) {
+	if len(shards) == 0 {
+		return nil, ErrNoShard
+	}
+	var r = &ShardRouter{}
+	r.shards = append([]string(nil), shards...)
+	return r, nil
+}
+
+// Route 获取 id 所在的分片的序号和名称
+func (this *ShardRouter) Route(id int64) (int, string) {
+	var i = Shard(id, len(this.shards))
+	return i, this.shards[i]
+}
+
+// Shards 获取所有分片的名称
+func (this *ShardRouter) Shards() []string {
+	return append([]string(nil), this.shards...)
+}
+
+// Grow 返回在末尾增加了 shards 的 ShardRouter，原 ShardRouter 保持不变，可以用于在扩容期间判断 id 是否需要迁移
+func (this *ShardRouter) Grow(shards ...string) *ShardRouter {
+	var r = &ShardRouter{}
+	r.shards = append(append([]string(nil), this.shards...), shards...)
+	return r
+}
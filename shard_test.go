@@ -0,0 +1,61 @@
+package snowflake
+
+import (
+	"testing"
+)
+
+func TestShard(t *testing.T) {
+	var s, _ = New()
+	var ids = make([]int64, 10000)
+	s.Fill(ids)
+
+	var counts = make([]int, 10)
+	var moved = 0
+	for _, id := range ids {
+		var before, after = Shard(id, 10), Shard(id, 11)
+		counts[before]++
+		if before != after {
+			if after != 10 {
+				t.Fatalf("id %d moved from shard %d to existing shard %d", id, before, after)
+			}
+			moved++
+		}
+	}
+	for i, n := range counts {
+		if n < 800 || n > 1200 {
+			t.Fatalf("shard %d got %d of 10000 ids, counts = %v", i, n, counts)
+		}
+	}
+	// 大约 1/11 的 id 迁移到新的分片
+	if moved < 700 || moved > 1150 {
+		t.Fatalf("moved %d of 10000 ids, want about 909", moved)
+	}
+	if Shard(1, 0) != 0 || Shard(1, 1) != 0 {
+		t.Fatal("Shard() with less than 2 shards should return 0")
+	}
+}
+
+func TestShardRouter(t *testing.T) {
+	if _, err := NewShardRouter(); err != ErrNoShard {
+		t.Fatalf("NewShardRouter() error = %v, want %v", err, ErrNoShard)
+	}
+
+	var r, _ = NewShardRouter("db0", "db1")
+	var grown = r.Grow("db2")
+	if len(r.Shards()) != 2 || len(grown.Shards()) != 3 {
+		t.Fatalf("Shards() = %v, %v", r.Shards(), grown.Shards())
+	}
+
+	var s, _ = New()
+	for i := 0; i < 1000; i++ {
+		var id = s.Next()
+		var i1, n1 = r.Route(id)
+		var i2, n2 = grown.Route(id)
+		if n1 != r.Shards()[i1] || n2 != grown.Shards()[i2] {
+			t.Fatalf("Route(%d) = %d %s, %d %s", id, i1, n1, i2, n2)
+		}
+		if i1 != i2 && n2 != "db2" {
+			t.Fatalf("id %d moved from %s to %s", id, n1, n2)
+		}
+	}
+}
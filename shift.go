@@ -0,0 +1,31 @@
+package snowflake
+
+import (
+	"time"
+)
+
+// WithTime 将 id 的时间戳替换为 t，其它部分保持不变，用于构造时间边界上的 id，如测试和按照时间范围扫描，t 超出时间戳部分的范围时返回 -1。
+//
+// t 精确到毫秒，使用了分片时会按照新的时间戳重新计算分片。
+func (this *SnowFlake) WithTime(s int64, t time.Time) int64 {
+	var millisecond = t.UnixNano() / 1e6
+	var timestamp = millisecond - this.timeOffset
+	if !this.bits.time.allow(timestamp) {
+		return -1
+	}
+
+	this.mu.Lock()
+	var encrypted = this.encryptTime(timestamp)
+	this.mu.Unlock()
+
+	var id = s&^(this.bits.time.max<<this.bits.time.shift) | this.bits.time.put(encrypted)
+	if this.bits.shard.max > 0 {
+		id = id&^(this.bits.shard.max<<this.bits.shard.shift) | this.bits.shard.put(shardOf(millisecond))
+	}
+	return id
+}
+
+// AddTime 将 id 的时间戳增加 d，d 可以为负数，其它部分保持不变，结果超出时间戳部分的范围时返回 -1
+func (this *SnowFlake) AddTime(s int64, d time.Duration) int64 {
+	return this.WithTime(s, this.TimeOf(s).Add(d))
+}
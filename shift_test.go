@@ -0,0 +1,46 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnowFlake_AddTime(t *testing.T) {
+	var generators []*SnowFlake
+	for _, opts := range [][]Option{
+		{WithTimeOffset(testEpoch), WithDataCenter(3), WithMachine(7)},
+		{WithTimeOffset(testEpoch), WithShardBits(3), WithMachine(7)},
+		{WithTimeOffset(testEpoch), WithTimeEncryption([]byte("0123456789abcdef")), WithMachine(7)},
+	} {
+		var s, err = New(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		generators = append(generators, s)
+	}
+
+	for i, s := range generators {
+		var id = s.Next()
+		var p = s.Decode(id)
+
+		var later = s.AddTime(id, time.Hour)
+		var q = s.Decode(later)
+		if !q.Time.Equal(p.Time.Add(time.Hour)) || q.Machine != p.Machine || q.DataCenter != p.DataCenter || q.Sequence != p.Sequence {
+			t.Fatalf("%d: Decode(AddTime()) = %+v, want %+v an hour later", i, q, p)
+		}
+		if q.Shard != shardOf(q.Timestamp+s.timeOffset)&s.bits.shard.max {
+			t.Fatalf("%d: Decode(AddTime()).Shard = %d, not recomputed", i, q.Shard)
+		}
+		if back := s.AddTime(later, -time.Hour); back != id {
+			t.Fatalf("%d: AddTime(AddTime(id, 1h), -1h) = %d, want %d", i, back, id)
+		}
+
+		var boundary = s.WithTime(id, testEpoch.Add(24*time.Hour))
+		if !s.TimeOf(boundary).Equal(testEpoch.Add(24 * time.Hour)) {
+			t.Fatalf("%d: TimeOf(WithTime()) = %v", i, s.TimeOf(boundary))
+		}
+		if s.WithTime(id, testEpoch.Add(-time.Millisecond)) != -1 || s.AddTime(id, 100*365*24*time.Hour) != -1 {
+			t.Fatalf("%d: out of range times should return -1", i)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package snowflake
+
+import (
+	"time"
+)
+
+const (
+	kRecentEvents = 32 // Snapshot 保留的事件数量
+)
+
+// Event 的类型
+const (
+	EventRollback       = "rollback"        // 检测到时钟回拨
+	EventWaitTimeout    = "wait_timeout"    // 等待时钟前进的时长超过了 WithMaxWait
+	EventEpochExhausted = "epoch_exhausted" // 时间戳部分用完
+	EventReload         = "reload"          // 通过 Reload 更新了配置
+	EventStateImported  = "state_imported"  // 通过 ImportState 导入了状态
+)
+
+// Event 生成器运行期间的重要事件
+type Event struct {
+	Time   time.Time `json:"time"`
+	Kind   string    `json:"kind"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// Snapshot 生成器的快照，包含配置、统计信息和最近的重要事件，可以序列化为 JSON 附加到故障报告中
+type Snapshot struct {
+	Taken time.Time `json:"taken"` // 生成快照的时间
+	DebugInfo
+	Events []Event `json:"events"` // 最近的重要事件，最多 32 个，按照时间排序
+}
+
+// Snapshot 获取生成器的快照，配置、统计信息和事件在同一次加锁中获取，相互之间是一致的
+func (this *SnowFlake) Snapshot() Snapshot {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	var snapshot = Snapshot{}
+	snapshot.Taken = time.Now()
+	snapshot.DebugInfo = this.debugLocked()
+	snapshot.Events = append([]Event{}, this.events...)
+	return snapshot
+}
+
+// recordEvent 记录重要事件，调用方需要持有 mu
+func (this *SnowFlake) recordEvent(kind, detail string) {
+	if len(this.events) == kRecentEvents {
+		this.events = append(this.events[:0], this.events[1:]...)
+	}
+	this.events = append(this.events, Event{Time: time.Now(), Kind: kind, Detail: detail})
+}
@@ -0,0 +1,53 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSnowFlake_Snapshot(t *testing.T) {
+	var s, _ = New(WithTimeOffset(testEpoch), WithDataCenter(3), WithMachine(5), WithMaxWait(5*time.Millisecond))
+	var now = time.Now().Add(time.Millisecond)
+	s.clock = func() time.Time { return now }
+	s.Next()
+	now = now.Add(-10 * time.Millisecond)
+	s.Next()
+	if err := s.Reload(Config{DataCenter: 3, Machine: 5, MaxWait: "5ms"}); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(20 * time.Millisecond)
+	for i := int64(0); i <= kMaxSequence+1; i++ {
+		s.Next()
+	}
+
+	var snapshot = s.Snapshot()
+	var kinds []string
+	for _, event := range snapshot.Events {
+		kinds = append(kinds, event.Kind)
+	}
+	if len(kinds) < 3 || kinds[0] != EventRollback || kinds[1] != EventReload || kinds[2] != EventWaitTimeout {
+		t.Fatalf("events = %+v", snapshot.Events)
+	}
+	if snapshot.DataCenter != 3 || snapshot.Stats.Rollbacks != 1 || len(snapshot.RecentRollbacks) != 1 {
+		t.Fatalf("snapshot = %+v", snapshot)
+	}
+
+	// 诊断信息的字段与快照的字段在同一层
+	var data, _ = json.Marshal(snapshot)
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["data_center"] != float64(3) || m["taken"] == nil || m["events"] == nil {
+		t.Fatalf("json = %s", data)
+	}
+
+	// 只保留最近的事件
+	for i := 0; i < kRecentEvents*2; i++ {
+		s.Reload(Config{DataCenter: 3, Machine: 5})
+	}
+	if n := len(s.Snapshot().Events); n != kRecentEvents {
+		t.Fatalf("events = %d, want %d", n, kRecentEvents)
+	}
+}
@@ -1,13 +1,20 @@
 package snowflake
 
 import (
+	crand "crypto/rand"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math/rand"
+	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 const (
+	kIDBits         uint8 = 63 // id 可以使用的总位数，最高位为符号位，固定为 0
 	kSequenceBits   uint8 = 12 // 序列号占用的位数
 	kDataCenterBits uint8 = 5  // 数据中心占用的位数
 	kMachineBits    uint8 = 5  // 机器标识占用的位数
@@ -22,11 +29,18 @@ const (
 
 	kDataCenterMask = kMaxDataCenter << kDataCenterShift
 	kMachineMask    = kMaxMachine << kSequenceBits
+
+	kExplainTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+	kDefaultBackwardsTolerance int64 = 1000 // 默认可以容忍的时钟回拨（毫秒），可以覆盖闰秒导致的时钟回拨
 )
 
 var (
-	ErrDataCenterNotAllowed = errors.New(fmt.Sprintf("snowflake: data center can't be greater than %d or less than 0", kMaxDataCenter))
-	ErrWorkerNotAllowed     = errors.New(fmt.Sprintf("snowflake: worker can't be greater than %d or less than 0", kMaxMachine))
+	ErrDataCenterNotAllowed = errors.New("snowflake: data center out of range")
+	ErrWorkerNotAllowed     = errors.New("snowflake: machine out of range")
+	ErrClockMovedBackwards  = errors.New("snowflake: clock moved backwards")
+	ErrWorkerIDNotAllowed   = errors.New("snowflake: worker id out of the range of data center and machine bits")
+	ErrAlreadyInitialized   = errors.New("snowflake: default generator is already initialized")
 )
 
 type Option interface {
@@ -42,8 +56,8 @@ func (f optionFunc) Apply(s *SnowFlake) error {
 // WithDataCenter 设置数据中心标识
 func WithDataCenter(dataCenter int64) Option {
 	return optionFunc(func(s *SnowFlake) error {
-		if dataCenter < 0 || dataCenter > kMaxDataCenter {
-			return ErrDataCenterNotAllowed
+		if !s.bits.dataCenter.allow(dataCenter) {
+			return rangeError("data center", dataCenter, s.bits.dataCenter, ErrDataCenterNotAllowed)
 		}
 		s.dataCenter = dataCenter
 		return nil
@@ -53,14 +67,61 @@ func WithDataCenter(dataCenter int64) Option {
 // WithMachine 设置机器标识
 func WithMachine(machine int64) Option {
 	return optionFunc(func(s *SnowFlake) error {
-		if machine < 0 || machine > kMaxMachine {
-			return ErrWorkerNotAllowed
+		if !s.bits.machine.allow(machine) {
+			return rangeError("machine", machine, s.bits.machine, ErrWorkerNotAllowed)
 		}
 		s.machine = machine
 		return nil
 	})
 }
 
+// WithWorkerID 设置工作节点标识，将数据中心和机器标识作为一个整体使用，默认布局下的范围为 0-1023。
+//
+// 高位作为数据中心标识、低位作为机器标识，与 WithDataCenter、WithMachine 同时使用时以 WithWorkerID 为准。
+func WithWorkerID(worker int64) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		if worker < 0 {
+			return &RangeError{Field: "worker id", Got: worker, Max: s.bits.dataCenter.max<<s.layout.Machine | s.bits.machine.max, Err: ErrWorkerIDNotAllowed}
+		}
+		// 在 validate 中按照最终的布局拆分
+		s.worker = worker
+		s.hasWorker = true
+		s.hostWorker = false
+		return nil
+	})
+}
+
+// WithBackwardsTolerance 设置可以容忍的时钟回拨的时长，默认为 1 秒，设置为 0 时不容忍任何时钟回拨。
+//
+// 时钟回拨（如闰秒、NTP 校时）不超过该时长时，会继续使用上一次的时间戳并消耗序列号，序列号用完之后使用下一毫秒的时间戳，
+// 生成的 id 不会重复而且仍然是递增的，超过该时长时返回 ErrClockMovedBackwards。
+func WithBackwardsTolerance(d time.Duration) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		if d < 0 {
+			d = 0
+		}
+		s.tolerance = int64(d / time.Millisecond)
+		return nil
+	})
+}
+
+// RollbackPolicy 时钟回拨超过 WithBackwardsTolerance 设置的容忍范围时的处理方式
+type RollbackPolicy int
+
+const (
+	PolicyError RollbackPolicy = iota // 返回 ErrClockMovedBackwards，Next 返回 -1，默认的处理方式
+	PolicyPanic                       // 直接 panic，适用于不允许出现任何异常 id 的服务
+	PolicyWait                        // 等待时钟追上上一次的时间戳之后继续生成，等待期间其它调用也会被阻塞
+)
+
+// WithRollbackPolicy 设置时钟回拨超过容忍范围时的处理方式
+func WithRollbackPolicy(policy RollbackPolicy) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		s.rollbackPolicy = policy
+		return nil
+	})
+}
+
 // WithTimeOffset 设置时间偏移量
 func WithTimeOffset(t time.Time) Option {
 	return optionFunc(func(s *SnowFlake) error {
@@ -72,13 +133,106 @@ func WithTimeOffset(t time.Time) Option {
 	})
 }
 
+// WithRandomSequenceStart 设置每一毫秒的序列号从随机的位置开始，避免通过连续的 id 推算出业务量。
+//
+// 序列号到达最大值之后会回到 0 继续使用，所以同一毫秒内后生成的 id 可能小于先生成的 id，只有不同毫秒的 id 保持递增，
+// 需要 id 严格递增的场景（如作为 B+ 树主键追加写入、按照 id 分页或者使用 Streams）不能使用该选项。
+func WithRandomSequenceStart() Option {
+	return optionFunc(func(s *SnowFlake) error {
+		// 使用 crypto/rand 生成种子，避免种子被推测出来，也避免同时创建的生成器得到相同的序列
+		var seed [8]byte
+		if _, err := crand.Read(seed[:]); err != nil {
+			return err
+		}
+		s.random = rand.New(rand.NewSource(int64(binary.BigEndian.Uint64(seed[:]))))
+		return nil
+	})
+}
+
+// WithMaxSequencePerMilli 设置每毫秒最多生成 n 个 id，达到之后提前使用下一毫秒，避免通过一毫秒内的 id 推算出业务量的上限，
+// 通常与 WithRandomSequenceStart 一起使用。n 小于等于 0 或者不小于序列号的数量时不限制，限制之后生成器的吞吐量最多为每秒 n*1000 个 id
+func WithMaxSequencePerMilli(n int64) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		if n < 0 {
+			n = 0
+		}
+		s.perMilli = n
+		return nil
+	})
+}
+
 type SnowFlake struct {
-	mu          sync.Mutex
-	millisecond int64 // 上一次生成 id 的时间戳（毫秒）
-	dataCenter  int64 // 数据中心 id
-	machine     int64 // 机器标识 id
-	sequence    int64 // 当前毫秒已经生成的 id 序列号
-	timeOffset  int64
+	mu             sync.Mutex
+	millisecond    int64 // 上一次生成 id 的时间戳（毫秒）
+	version        int64 // 布局版本
+	region         int64 // 区域 id
+	dataCenter     int64 // 数据中心 id
+	machine        int64 // 机器标识 id
+	boot           int64 // 启动次数
+	worker         int64 // 通过 WithWorkerID 设置的工作节点标识
+	hasWorker      bool
+	hostWorker     bool   // 工作节点标识是否由 WithHostWorkerID 计算得到
+	sequence       int64  // 当前毫秒已经生成的 id 序列号
+	sequenceStart  int64  // 当前毫秒序列号的起始值
+	jitter         uint64 // 通过 WithSequenceJitter 设置的随机数，用于计算步长
+	stride         int64  // 序列号每次增加的步长
+	strideInverse  int64  // 步长的模逆元，用于计算已经使用的序列号数量
+	timeOffset     int64
+	tolerance      int64 // 可以容忍的时钟回拨（毫秒）
+	rollbackPolicy RollbackPolicy
+	waitStrategy   WaitStrategy
+	maxWait        time.Duration // 通过 WithMaxWait 设置的一次生成 id 最多等待的时长，为 0 时不限制
+	perMilli       int64         // 通过 WithMaxSequencePerMilli 设置的每毫秒最多使用的序列号数量，为 0 时不限制
+	ahead          int64         // 通过 WithBorrowAhead 设置的可以提前使用的时间戳的范围（毫秒）
+	borrowedUntil  int64         // 提前使用的最新的时间戳
+	rollbackHook   func(RollbackEvent)
+	exhaustedHook  func(end time.Time)
+	exhausted      bool            // 是否已经调用过 exhaustedHook
+	rollingBack    bool            // 是否处于一次连续的时钟回拨中
+	lastClock      int64           // 时钟回拨期间最近一次读取的时间戳
+	rollbacks      []RollbackEvent // 最近的时钟回拨，最多保留 kRecentRollbacks 个
+	events         []Event         // 最近的重要事件，最多保留 kRecentEvents 个
+	logger         *slog.Logger
+	timeCipher     *FF1  // 通过 WithTimeEncryption 设置的时间戳加密
+	cipherPlain    int64 // 最近一次加密的时间戳
+	cipherText     int64 // 最近一次加密的时间戳的密文
+	logLevel       slog.Level
+	lockDir        string
+	processDir     string
+	processBits    uint8
+	usedRanges     string // 通过 WithUsedRanges 设置的离线生成 id 使用过的时间范围文件
+	sampler        *SequenceSampler
+	process        int64      // 通过 WithProcessSlots 获取的进程槽位
+	locks          []*os.File // 通过 WithExclusiveMachineLock 和 WithProcessSlots 获取的文件锁
+	clock          func() time.Time
+	anchor         time.Time  // 创建生成器时的时间，包含单调时钟的读数
+	anchorMill     int64      // anchor 对应的时间戳（毫秒）
+	random         *rand.Rand // 用于生成序列号的起始值，为 nil 时序列号从 0 开始
+	layout         Layout
+	bits           layoutBits
+	stats          Stats
+}
+
+// Stats 生成器的统计信息
+type Stats struct {
+	Generated      int64         `json:"generated"`       // 已经生成的 id 数量
+	SequenceWaits  int64         `json:"sequence_waits"`  // 因为当前毫秒的序列号耗尽而等待下一毫秒的次数
+	ClockBackwards int64         `json:"clock_backwards"` // 检测到超过容忍范围的时钟回拨的次数
+	ClockHolds     int64         `json:"clock_holds"`     // 在容忍范围内的时钟回拨期间，沿用上一次的时间戳生成 id 的次数
+	Rollbacks      int64         `json:"rollbacks"`       // 检测到的时钟回拨的次数，一次连续的时钟回拨只记录一次
+	MaxRollback    time.Duration `json:"max_rollback"`    // 检测到的时钟回拨的最大时长
+}
+
+func (s Stats) add(o Stats) Stats {
+	s.Generated += o.Generated
+	s.SequenceWaits += o.SequenceWaits
+	s.ClockBackwards += o.ClockBackwards
+	s.ClockHolds += o.ClockHolds
+	s.Rollbacks += o.Rollbacks
+	if o.MaxRollback > s.MaxRollback {
+		s.MaxRollback = o.MaxRollback
+	}
+	return s
 }
 
 func New(opts ...Option) (*SnowFlake, error) {
@@ -88,6 +242,14 @@ func New(opts ...Option) (*SnowFlake, error) {
 	sf.timeOffset = 0
 	sf.dataCenter = 0
 	sf.machine = 0
+	sf.layout = DefaultLayout
+	sf.bits = DefaultLayout.bits()
+	sf.tolerance = kDefaultBackwardsTolerance
+	sf.ahead = int64(clockGranularity() / time.Millisecond)
+	sf.logLevel = slog.LevelWarn
+	sf.clock = time.Now
+	sf.anchor = sf.clock()
+	sf.anchorMill = sf.anchor.UnixNano() / 1e6
 
 	var err error
 	for _, opt := range opts {
@@ -95,44 +257,300 @@ func New(opts ...Option) (*SnowFlake, error) {
 			return nil, err
 		}
 	}
+	if err = sf.validate(); err != nil {
+		return nil, err
+	}
+	if err = sf.splitProcessSlot(); err != nil {
+		return nil, err
+	}
+	sf.initStride()
+	if err = sf.loadUsedRanges(); err != nil {
+		return nil, err
+	}
+	if err = sf.lockMachine(); err != nil {
+		return nil, err
+	}
+	if err = sf.lockProcessSlot(); err != nil {
+		sf.Close()
+		return nil, err
+	}
 	return sf, nil
 }
 
+// validate 使用最终的布局校验所有的配置，避免先设置的标识在后续调整布局之后超出范围。
+//
+// 有多个配置不符合布局时会返回所有的错误，可以通过 errors.Is 判断具体的错误。
+func (this *SnowFlake) validate() error {
+	var errs []error
+	if this.hostWorker {
+		// 主机的哈希值需要截取为最终布局的位数
+		this.worker &= this.bits.dataCenter.max<<this.layout.Machine | this.bits.machine.max
+	}
+	if this.hasWorker {
+		if max := this.bits.dataCenter.max<<this.layout.Machine | this.bits.machine.max; this.worker > max {
+			errs = append(errs, &RangeError{Field: "worker id", Got: this.worker, Max: max, Err: ErrWorkerIDNotAllowed})
+		} else {
+			this.dataCenter = this.worker >> this.layout.Machine
+			this.machine = this.worker & this.bits.machine.max
+		}
+	}
+	if !this.bits.version.allow(this.version) {
+		errs = append(errs, rangeError("version", this.version, this.bits.version, ErrVersionNotAllowed))
+	}
+	if !this.bits.region.allow(this.region) {
+		errs = append(errs, rangeError("region", this.region, this.bits.region, ErrRegionNotAllowed))
+	}
+	if !this.bits.dataCenter.allow(this.dataCenter) {
+		errs = append(errs, rangeError("data center", this.dataCenter, this.bits.dataCenter, ErrDataCenterNotAllowed))
+	}
+	if !this.bits.machine.allow(this.machine) {
+		errs = append(errs, rangeError("machine", this.machine, this.bits.machine, ErrWorkerNotAllowed))
+	}
+	if now := this.getMillisecond(); now < this.timeOffset {
+		// 时间戳部分会是负数，生成的 id 也会是负数
+		errs = append(errs, ErrEpochInFuture)
+	} else if !this.bits.time.allow(now - this.timeOffset + kMinTimeHorizon) {
+		errs = append(errs, ErrTimeBitsTooSmall)
+	}
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
 func (this *SnowFlake) Next() int64 {
-	this.mu.Lock()
+	var id, err = this.next(0)
+	if err != nil {
+		return -1
+	}
+	return id
+}
 
-	var millisecond = this.getMillisecond()
-	if millisecond < this.millisecond {
-		this.mu.Unlock()
+// NextFor 生成指定实体类型的 id，需要先通过 WithEntityBits 为实体类型预留位数，实体类型超出范围时返回 -1
+func (this *SnowFlake) NextFor(entity int64) int64 {
+	if !this.bits.entity.allow(entity) {
 		return -1
 	}
+	var id, err = this.next(entity)
+	if err != nil {
+		return -1
+	}
+	return id
+}
+
+// next 生成新的 id，当前毫秒的序列号用完时在锁外等待下一毫秒，等待期间其它 goroutine 仍然可以获取锁
+func (this *SnowFlake) next(entity int64) (int64, error) {
+	return this.nextResult(entity, nil)
+}
+
+// nextResult 与 next 相同，r 不为 nil 时记录生成 id 使用的时间戳和序列号
+func (this *SnowFlake) nextResult(entity int64, r *Result) (int64, error) {
+	var w waiter
+	for {
+		var id, after, wait, err = this.tryNext(entity, &w, r)
+		if !wait {
+			return id, err
+		}
+		if err = this.waitAfter(after, &w); err != nil {
+			this.mu.Lock()
+			this.recordEvent(EventWaitTimeout, "")
+			this.mu.Unlock()
+			return 0, err
+		}
+	}
+}
+
+// tryNext 加锁之后调用 generate，第一次需要等待时在持有锁的时候获取等待使用的配置
+func (this *SnowFlake) tryNext(entity int64, w *waiter, r *Result) (int64, int64, bool, error) {
+	this.mu.Lock()
+	// PolicyPanic 会在持有锁的时候 panic，需要通过 defer 释放锁
+	defer this.mu.Unlock()
+	var waits, holds = this.stats.SequenceWaits, this.stats.ClockHolds
+	var id, after, wait, err = this.generate(entity)
+	if wait && !w.ready {
+		*w = this.newWaiter()
+	}
+	if r != nil {
+		r.Waited = r.Waited || this.stats.SequenceWaits > waits
+		if !wait && err == nil {
+			r.ID = id
+			r.Time = millisecondToTime(this.millisecond)
+			r.Sequence = this.sequence
+			r.Held = this.stats.ClockHolds > holds
+		}
+	}
+	return id, after, wait, err
+}
+
+// nextLocked 生成新的 id，当前毫秒的序列号用完时持有锁等待下一毫秒，用于需要在一次加锁中生成多个 id 的场景，调用方需要持有 mu
+func (this *SnowFlake) nextLocked(entity int64) (int64, error) {
+	var w = this.newWaiter()
+	for {
+		var id, after, wait, err = this.generate(entity)
+		if !wait {
+			return id, err
+		}
+		if err = this.waitAfter(after, &w); err != nil {
+			this.recordEvent(EventWaitTimeout, "")
+			return 0, err
+		}
+	}
+}
+
+// generate 生成新的 id，当前毫秒的序列号已经用完，并且不能使用下一毫秒时不修改任何状态，
+// 返回 wait 为 true 以及需要等待时钟超过的时间戳，调用方需要持有 mu
+func (this *SnowFlake) generate(entity int64) (id int64, after int64, wait bool, err error) {
+	var millisecond int64
+	if millisecond, err = this.tick(); err != nil {
+		return 0, 0, false, err
+	}
 
 	if this.millisecond == millisecond {
-		this.sequence = (this.sequence + 1) & kMaxSequence
-		if this.sequence == 0 {
-			millisecond = this.getNextMillisecond()
+		var sequence = (this.sequence + this.stride) & this.bits.sequence.max
+		if sequence == this.sequenceStart || this.sequenceUsed() >= this.sequenceCapacity() {
+			this.stats.SequenceWaits++
+			var ok bool
+			if millisecond, ok = this.borrowMillisecond(); !ok {
+				return 0, this.millisecond, true, nil
+			}
+			this.resetSequence()
+		} else {
+			this.sequence = sequence
 		}
 	} else {
-		this.sequence = 0
+		this.resetSequence()
+	}
+	if err = this.checkEpoch(millisecond); err != nil {
+		return 0, 0, false, err
 	}
 	this.millisecond = millisecond
-	var sequence = this.sequence
-	this.mu.Unlock()
+	this.stats.Generated++
+	if this.sampler != nil {
+		this.sampler.observe(millisecond, 1, this.sequenceUsed(), this.sequenceCapacity())
+	}
 
-	var id = (millisecond-this.timeOffset)<<kTimeShift | (this.dataCenter << kDataCenterShift) | (this.machine << kMachineShift) | (sequence)
-	return id
+	return this.compose(millisecond, entity, this.sequence), 0, false, nil
+}
+
+// compose 使用生成器的配置组装 id
+func (this *SnowFlake) compose(millisecond, entity, sequence int64) int64 {
+	return this.bits.shard.put(shardOf(millisecond)) | this.bits.version.put(this.version) | this.bits.time.put(this.encryptTime(millisecond-this.timeOffset)) | this.bits.entity.put(entity) | this.bits.region.put(this.region) | this.bits.dataCenter.put(this.dataCenter) | this.bits.machine.put(this.machine) | this.bits.boot.put(this.boot) | this.bits.process.put(this.process) | this.bits.sequence.put(sequence)
+}
+
+// DataCenterID 获取生成器的数据中心标识
+func (this *SnowFlake) DataCenterID() int64 {
+	return this.dataCenter
 }
 
-func (this *SnowFlake) getNextMillisecond() int64 {
+// MachineID 获取生成器的机器标识
+func (this *SnowFlake) MachineID() int64 {
+	return this.machine
+}
+
+// WorkerID 获取生成器的工作节点标识，即数据中心和机器标识组成的整体，与 WithWorkerID 对应
+func (this *SnowFlake) WorkerID() int64 {
+	return this.dataCenter<<this.layout.Machine | this.machine
+}
+
+// shardOf 计算时间戳对应的分片，同一毫秒内的 id 使用同一个分片，保证 NextBlock 返回的 id 仍然是连续的
+func shardOf(millisecond int64) int64 {
+	return int64(mix64(uint64(millisecond)) >> 1)
+}
+
+// Stats 获取生成器的统计信息
+func (this *SnowFlake) Stats() Stats {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	return this.stats
+}
+
+// resetSequence 重置当前毫秒的序列号
+func (this *SnowFlake) resetSequence() {
+	this.sequenceStart = 0
+	if this.random != nil {
+		this.sequenceStart = this.random.Int63n(this.bits.sequence.max + 1)
+	}
+	this.sequence = this.sequenceStart
+}
+
+// sequenceUsed 获取当前毫秒已经使用的序列号数量，调用方需要持有 mu
+func (this *SnowFlake) sequenceUsed() int64 {
+	// 使用随机的起始值时序列号可能从最大值回绕到 0，使用 jitter 时需要除以步长，即乘以步长的模逆元
+	return (this.sequence-this.sequenceStart)*this.strideInverse&this.bits.sequence.max + 1
+}
+
+// sequenceCapacity 获取每毫秒可以使用的序列号数量，会考虑 WithMaxSequencePerMilli 的限制
+func (this *SnowFlake) sequenceCapacity() int64 {
+	if this.perMilli > 0 && this.perMilli <= this.bits.sequence.max {
+		return this.perMilli
+	}
+	return this.bits.sequence.max + 1
+}
+
+// tick 获取生成 id 使用的时间戳，时钟回拨在容忍范围内时沿用上一次的时间戳，调用方需要持有 mu
+func (this *SnowFlake) tick() (int64, error) {
+	var millisecond = this.getMillisecond()
+	if millisecond >= this.millisecond {
+		this.rollingBack = false
+		return millisecond, nil
+	}
+	if this.heldAhead(millisecond) {
+		return this.millisecond, nil
+	}
+	this.observeRollback(millisecond)
+	if this.millisecond-millisecond > this.tolerance {
+		this.stats.ClockBackwards++
+		switch this.rollbackPolicy {
+		case PolicyPanic:
+			panic(ErrClockMovedBackwards)
+		case PolicyWait:
+			var w = this.newWaiter()
+			if err := this.waitAfter(this.millisecond-1, &w); err != nil {
+				this.recordEvent(EventWaitTimeout, "policy=wait")
+				return 0, err
+			}
+			return this.getMillisecond(), nil
+		}
+		return 0, ErrClockMovedBackwards
+	}
+	this.stats.ClockHolds++
+	return this.millisecond, nil
+}
+
+func (this *SnowFlake) getNextMillisecond() (int64, error) {
+	if mill, ok := this.borrowMillisecond(); ok {
+		return mill, nil
+	}
+	var w = this.newWaiter()
+	if err := this.waitAfter(this.millisecond, &w); err != nil {
+		this.recordEvent(EventWaitTimeout, "")
+		return 0, err
+	}
+	return this.getMillisecond(), nil
+}
+
+// borrowMillisecond 时钟回拨期间不等待时钟追上，直接使用下一毫秒，只要不超过容忍范围，调用方需要持有 mu
+func (this *SnowFlake) borrowMillisecond() (int64, bool) {
 	var mill = this.getMillisecond()
-	for mill < this.millisecond {
-		mill = this.getMillisecond()
+	if next, ok := this.borrowAhead(mill); ok {
+		return next, true
+	}
+	// 提前使用的时间戳超过了范围时需要等待时钟前进，不能按照时钟回拨继续使用下一毫秒
+	if mill < this.millisecond && this.millisecond > this.borrowedUntil && this.millisecond+1-mill <= this.tolerance {
+		return this.millisecond + 1, true
 	}
-	return mill
+	return 0, false
 }
 
+// getMillisecond 获取当前的时间戳（毫秒）。
+//
+// 时间戳由创建生成器时的系统时间加上单调时钟经过的时长得到，运行期间的 NTP 校时等调整系统时间的操作不会导致时间戳回退。
 func (this *SnowFlake) getMillisecond() int64 {
-	return time.Now().UnixNano() / 1e6
+	return this.anchorMill + int64(this.clock().Sub(this.anchor)/time.Millisecond)
 }
 
 // Time 获取 id 的时间，单位是 millisecond
@@ -150,29 +568,153 @@ func Machine(s int64) int64 {
 	return s & kMachineMask >> kMachineShift
 }
 
+// Worker 获取 id 的工作节点标识，即数据中心和机器标识组成的整体
+func Worker(s int64) int64 {
+	return s >> kMachineShift & (1<<(kDataCenterBits+kMachineBits) - 1)
+}
+
 //  Sequence 获取 id 的序列号
 func Sequence(s int64) int64 {
 	return s & kMaxSequence
 }
 
-var defaultSnowFlake *SnowFlake
-var once sync.Once
+// Worker 按照生成器的布局获取 id 的工作节点标识，与 WithWorkerID 对应
+func (this *SnowFlake) Worker(s int64) int64 {
+	return this.bits.dataCenter.get(s)<<this.layout.Machine | this.bits.machine.get(s)
+}
+
+// TimeOf 获取 id 的生成时间，会考虑设置的时间偏移量
+func (this *SnowFlake) TimeOf(s int64) time.Time {
+	return millisecondToTime(this.timestampOf(s) + this.timeOffset)
+}
 
-func Next() int64 {
-	once.Do(func() {
-		defaultSnowFlake, _ = New()
-	})
-	return defaultSnowFlake.Next()
+// Age 获取 id 从生成到现在经过的时长，会考虑设置的时间偏移量
+func (this *SnowFlake) Age(s int64) time.Duration {
+	return time.Since(this.TimeOf(s))
+}
+
+// Explain 获取 id 各组成部分的可读描述，如：id=146559593487814656 time=2024-06-11T08:33:12.345Z dc=3 machine=17 seq=42
+func (this *SnowFlake) Explain(s int64) string {
+	return explain(this.layout, this.Decode(s))
+}
+
+// explain 按照布局输出 Parts 中各组成部分的可读描述
+func explain(layout Layout, p Parts) string {
+	var extra string
+	if layout.Shard > 0 {
+		extra += fmt.Sprintf(" shard=%d", p.Shard)
+	}
+	if layout.Version > 0 {
+		extra += fmt.Sprintf(" version=%d", p.Version)
+	}
+	if layout.Entity > 0 {
+		extra += fmt.Sprintf(" entity=%d", p.Entity)
+	}
+	if layout.Region > 0 {
+		extra += fmt.Sprintf(" region=%d", p.Region)
+	}
+	var boot string
+	if layout.Boot > 0 {
+		boot = fmt.Sprintf(" boot=%d", p.Boot)
+	}
+	return fmt.Sprintf("id=%d time=%s%s dc=%d machine=%d%s seq=%d", p.ID, p.Time.UTC().Format(kExplainTimeLayout), extra, p.DataCenter, p.Machine, boot, p.Sequence)
 }
 
-func Init(opts ...Option) (err error) {
-	once.Do(func() {
-		defaultSnowFlake, err = New(opts...)
+var defaultSnowFlake atomic.Value // *SnowFlake
+var defaultMu sync.Mutex
+var defaultDecoder *SnowFlake // 没有默认生成器时包级别的解析函数使用，不会作为默认生成器
+var defaultDecoderOnce sync.Once
+
+// getDefault 获取默认生成器，通过 RequireInit 禁止了隐式地创建默认生成器并且没有调用 Init 时 panic
+func getDefault() *SnowFlake {
+	var s, err = loadDefault()
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// loadedDefault 获取已经创建的默认生成器，没有创建或者已经被 resetDefault 清除时返回 nil
+func loadedDefault() *SnowFlake {
+	var s, _ = defaultSnowFlake.Load().(*SnowFlake)
+	return s
+}
+
+func loadDefault() (*SnowFlake, error) {
+	if s := loadedDefault(); s != nil {
+		return s, nil
+	}
+
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if s := loadedDefault(); s != nil {
+		return s, nil
+	}
+	if atomic.LoadInt32(&requireInit) == 1 {
+		return nil, ErrNotInitialized
+	}
+	var s, err = newDefault()
+	if err != nil {
+		return nil, err
+	}
+	defaultSnowFlake.Store(s)
+	return s, nil
+}
+
+// getDecoder 获取包级别的解析函数使用的生成器，已经创建了默认生成器时使用默认生成器，
+// 否则使用 DefaultLayout 和时间偏移量 0 解析，不会创建默认生成器，之后仍然可以调用 Init
+func getDecoder() *SnowFlake {
+	if s := loadedDefault(); s != nil {
+		return s
+	}
+	defaultDecoderOnce.Do(func() {
+		defaultDecoder, _ = New()
 	})
+	return defaultDecoder
+}
+
+func Next() int64 {
+	return getDefault().Next()
+}
 
+// TimeOf 获取 id 的生成时间，使用默认生成器的时间偏移量
+func TimeOf(s int64) time.Time {
+	return getDecoder().TimeOf(s)
+}
+
+// Age 获取 id 从生成到现在经过的时长，使用默认生成器的时间偏移量
+func Age(s int64) time.Duration {
+	return getDecoder().Age(s)
+}
+
+// Explain 获取 id 各组成部分的可读描述，使用默认生成器的时间偏移量
+func Explain(s int64) string {
+	return getDecoder().Explain(s)
+}
+
+// Init 使用 opts 创建包级别的函数使用的默认生成器，只能调用一次。
+//
+// 已经调用过 Init，或者在调用 Init 之前已经使用了默认生成器（如调用了 Next）时返回 ErrAlreadyInitialized，默认生成器保持不变。
+// TimeOf、Explain 等只解析 id 的包级别的函数不会创建默认生成器，可以在 Init 之前调用。
+// 创建生成器失败时返回对应的错误，可以修改配置之后再次调用。
+func Init(opts ...Option) error {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+
+	if loadedDefault() != nil {
+		return ErrAlreadyInitialized
+	}
+	var s, err = New(opts...)
 	if err != nil {
-		once = sync.Once{}
+		return err
 	}
+	defaultSnowFlake.Store(s)
+	return nil
+}
 
-	return err
+// MustInit 与 Init 相同，失败时 panic，适用于在 main 或者 init 函数中初始化
+func MustInit(opts ...Option) {
+	if err := Init(opts...); err != nil {
+		panic(err)
+	}
 }
@@ -1,8 +1,12 @@
 package snowflake
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestSnowFlake_Next(t *testing.T) {
@@ -16,3 +20,279 @@ func BenchmarkSnowFlake_Next(b *testing.B) {
 		Next()
 	}
 }
+
+func BenchmarkParallel(b *testing.B) {
+	var s, _ = New()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			s.Next()
+		}
+	})
+}
+
+func TestSnowFlake_NextConcurrent(t *testing.T) {
+	// 序列号很少，大部分调用都需要等待下一毫秒
+	var s, _ = New(WithLayout(Layout{DataCenter: 5, Machine: 5, Sequence: 2}))
+	var wg sync.WaitGroup
+	var results = make([][]int64, 8)
+	for g := range results {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				results[g] = append(results[g], s.Next())
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	var seen = make(map[int64]bool)
+	for _, ids := range results {
+		for i, id := range ids {
+			if id < 0 || seen[id] || (i > 0 && id <= ids[i-1]) {
+				t.Fatalf("Next() = %d is negative, duplicate or not increasing", id)
+			}
+			seen[id] = true
+		}
+	}
+	if stats := s.Stats(); stats.Generated != 1600 || stats.SequenceWaits == 0 {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+}
+
+func TestSnowFlake_Age(t *testing.T) {
+	var s, _ = New(WithTimeOffset(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+	var id = s.Next()
+	time.Sleep(time.Millisecond * 20)
+
+	if age := s.Age(id); age < time.Millisecond*20 || age > time.Second {
+		t.Fatalf("unexpected age %v", age)
+	}
+}
+
+func TestSnowFlake_TimeOf(t *testing.T) {
+	var s, _ = New(WithTimeOffset(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)))
+	var before = time.Now().Truncate(time.Millisecond)
+	var id = s.Next()
+	var after = time.Now()
+
+	if tm := s.TimeOf(id); tm.Before(before) || tm.After(after) {
+		t.Fatalf("TimeOf(%d) = %v, want between %v and %v", id, tm, before, after)
+	}
+}
+
+func TestSnowFlake_Explain(t *testing.T) {
+	var s, _ = New(WithDataCenter(3), WithMachine(17))
+	var id = int64(1718094792345)<<22 | 3<<17 | 17<<12 | 42
+
+	var want = "id=7206211859912265770 time=2024-06-11T08:33:12.345Z dc=3 machine=17 seq=42"
+	if got := s.Explain(id); got != want {
+		t.Fatalf("Explain() = %q, want %q", got, want)
+	}
+}
+
+func TestSnowFlake_Unique(t *testing.T) {
+	var tests = map[string][]Option{
+		"default":               nil,
+		"random sequence start": {WithRandomSequenceStart()},
+	}
+	for name, opts := range tests {
+		var s, _ = New(opts...)
+		var seen = make(map[int64]struct{})
+		for i := 0; i < 50000; i++ {
+			var id = s.Next()
+			if _, ok := seen[id]; ok {
+				t.Fatalf("%s: duplicate id %d", name, id)
+			}
+			seen[id] = struct{}{}
+		}
+	}
+}
+
+func TestWithRandomSequenceStart_Seed(t *testing.T) {
+	var a, _ = New(WithRandomSequenceStart())
+	var b, _ = New(WithRandomSequenceStart())
+
+	var same = 0
+	for i := 0; i < 8; i++ {
+		if a.random.Int63() == b.random.Int63() {
+			same++
+		}
+	}
+	if same == 8 {
+		t.Fatal("generators created together should not share a random sequence")
+	}
+}
+
+func TestWithWorkerID(t *testing.T) {
+	var s, err = New(WithWorkerID(1000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var id = s.Next()
+	if Worker(id) != 1000 || s.Worker(id) != 1000 || DataCenter(id) != 31 || Machine(id) != 8 {
+		t.Fatalf("Worker(%d) = %d, DataCenter = %d, Machine = %d", id, Worker(id), DataCenter(id), Machine(id))
+	}
+
+	// 按照最终的布局拆分
+	s, err = New(WithWorkerID(4095), WithLayout(Layout{DataCenter: 2, Machine: 10, Sequence: 10}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id = s.Next(); s.Worker(id) != 4095 {
+		t.Fatalf("Worker(%d) = %d, want 4095", id, s.Worker(id))
+	}
+
+	for _, worker := range []int64{-1, 1024} {
+		if _, err = New(WithWorkerID(worker)); !errors.Is(err, ErrWorkerIDNotAllowed) {
+			t.Fatalf("New(WithWorkerID(%d)) error = %v, want %v", worker, err, ErrWorkerIDNotAllowed)
+		}
+	}
+}
+
+func TestSnowFlake_Getters(t *testing.T) {
+	var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var l = Layout{DataCenter: 4, Machine: 6, Sequence: 12, Order: []Part{PartTime, PartSequence, PartDataCenter, PartMachine}}
+	var s, err = New(WithTimeOffset(epoch), WithLayout(l), WithDataCenter(3), WithMachine(40))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.DataCenterID() != 3 || s.MachineID() != 40 || s.WorkerID() != 3<<6|40 {
+		t.Fatalf("DataCenterID() = %d, MachineID() = %d, WorkerID() = %d", s.DataCenterID(), s.MachineID(), s.WorkerID())
+	}
+	if !s.Epoch().Equal(epoch) {
+		t.Fatalf("Epoch() = %v, want %v", s.Epoch(), epoch)
+	}
+
+	var got = s.Layout()
+	if got.DataCenter != 4 || got.Machine != 6 || got.Sequence != 12 || len(got.Order) != 4 {
+		t.Fatalf("Layout() = %+v", got)
+	}
+	// 修改返回的布局不会影响生成器
+	got.Order[0] = PartSequence
+	if s.Layout().Order[0] != PartTime {
+		t.Fatal("Layout() should return a copy of the order")
+	}
+}
+
+func TestSnowFlake_BackwardsTolerance(t *testing.T) {
+	var s, _ = New()
+	var last = s.Next()
+
+	// 模拟 500 毫秒的时钟回拨，在默认的容忍范围内
+	s.mu.Lock()
+	s.millisecond = s.getMillisecond() + 500
+	s.mu.Unlock()
+	for i := 0; i < 10000; i++ {
+		var id, err = s.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= last {
+			t.Fatalf("NextID() = %d, not greater than %d", id, last)
+		}
+		last = id
+	}
+	if stats := s.Stats(); stats.ClockHolds == 0 || stats.ClockBackwards != 0 {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+	if block, err := s.NextBlock(10); err != nil || block.Start <= last {
+		t.Fatalf("NextBlock() = %+v, %v", block, err)
+	}
+
+	// 超过容忍范围
+	s, _ = New(WithBackwardsTolerance(0))
+	s.Next()
+	s.mu.Lock()
+	s.millisecond += 500
+	s.mu.Unlock()
+	if _, err := s.NextID(); err != ErrClockMovedBackwards {
+		t.Fatalf("NextID() error = %v, want %v", err, ErrClockMovedBackwards)
+	}
+	if stats := s.Stats(); stats.ClockBackwards != 1 {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+}
+
+func TestSnowFlake_MonotonicClock(t *testing.T) {
+	var s, _ = New()
+	var now = s.anchor
+	s.clock = func() time.Time { return now }
+
+	// 时间戳由创建时的系统时间加上经过的时长得到
+	now = s.anchor.Add(5 * time.Millisecond)
+	var id = s.Next()
+	if got := Time(id); got != s.anchorMill+5 {
+		t.Fatalf("Time(%d) = %d, want %d", id, got, s.anchorMill+5)
+	}
+
+	// 经过的时长由 time.Time 的单调时钟读数计算，调整系统时间不会影响 time.Since
+	if !strings.Contains(s.anchor.String(), "m=") {
+		t.Fatalf("anchor %v has no monotonic clock reading", s.anchor)
+	}
+}
+
+func TestWithRollbackPolicy(t *testing.T) {
+	var moveBack = func(s *SnowFlake, d int64) {
+		s.Next()
+		s.mu.Lock()
+		s.millisecond += d
+		s.mu.Unlock()
+	}
+
+	var s, _ = New(WithBackwardsTolerance(0), WithRollbackPolicy(PolicyWait))
+	moveBack(s, 50)
+	var start = time.Now()
+	if _, err := s.NextID(); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("NextID() returned after %v, want it to wait for the clock", elapsed)
+	}
+
+	s, _ = New(WithBackwardsTolerance(0), WithRollbackPolicy(PolicyPanic))
+	moveBack(s, 50)
+	func() {
+		defer func() {
+			if r := recover(); r != ErrClockMovedBackwards {
+				t.Fatalf("recover() = %v, want %v", r, ErrClockMovedBackwards)
+			}
+		}()
+		s.NextID()
+	}()
+	// panic 之后锁已经释放
+	s.mu.Lock()
+	s.millisecond = 0
+	s.mu.Unlock()
+	if _, err := s.NextID(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWithMaxSequencePerMilli(t *testing.T) {
+	var s, _ = New(WithMaxSequencePerMilli(40), WithRandomSequenceStart())
+	var seen = make(map[int64]bool)
+	var perMilli = make(map[int64]int)
+	for i := 0; i < 400; i++ {
+		var id = s.Next()
+		seen[id] = true
+		perMilli[s.timestampOf(id)]++
+	}
+	if len(seen) != 400 {
+		t.Fatalf("got %d unique ids", len(seen))
+	}
+	var max int
+	for _, n := range perMilli {
+		if n > max {
+			max = n
+		}
+	}
+	if max != 40 {
+		t.Fatalf("max ids per millisecond = %d, want 40", max)
+	}
+
+	// NextBlock 同样受到限制
+	if b, _ := s.NextBlock(100); b.Count > 40 {
+		t.Fatalf("NextBlock(100).Count = %d", b.Count)
+	}
+}
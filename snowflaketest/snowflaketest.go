@@ -0,0 +1,18 @@
+// Package snowflaketest 提供测试 snowflake 时使用的辅助函数，只应该在测试中导入
+package snowflaketest
+
+import (
+	"testing"
+
+	_ "github.com/smartwalle/snowflake" // 由 snowflake 的 init 设置 testhook
+	"github.com/smartwalle/snowflake/internal/testhook"
+)
+
+// ResetDefault 清除包级别的函数使用的默认生成器，之后可以再次调用 snowflake.Init，用于在同一个测试进程中使用不同的配置测试 Init。
+//
+// tb 对应的测试结束时会恢复原来的默认生成器。
+// 重置期间其它 goroutine 仍然在使用默认生成器时，可能会生成与原来的默认生成器重复的 id。
+func ResetDefault(tb testing.TB) {
+	tb.Helper()
+	tb.Cleanup(testhook.ResetDefault())
+}
@@ -0,0 +1,21 @@
+package snowflaketest
+
+import (
+	"testing"
+
+	"github.com/smartwalle/snowflake"
+)
+
+func TestResetDefault(t *testing.T) {
+	var before = snowflake.Default()
+	t.Run("Reset", func(t *testing.T) {
+		ResetDefault(t)
+		snowflake.MustInit(snowflake.WithMachine(9))
+		if snowflake.Default() == before || snowflake.Default().MachineID() != 9 {
+			t.Fatalf("Default() is not replaced after ResetDefault")
+		}
+	})
+	if snowflake.Default() != before {
+		t.Fatalf("Default() is not restored after the test")
+	}
+}
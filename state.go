@@ -0,0 +1,88 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+var (
+	ErrStateMismatch = errors.New("snowflake: state was exported by a generator with a different layout, epoch or worker id")
+)
+
+// State 生成器的状态，用于在进程之间交接同一个工作节点标识，如蓝绿部署时新的进程接替旧的进程
+type State struct {
+	Millisecond   int64  `json:"millisecond"`      // 上一次生成 id 的时间戳（毫秒），不是相对于时间偏移量的值
+	Sequence      int64  `json:"sequence"`         // 上一次生成 id 使用的序列号
+	SequenceStart int64  `json:"sequence_start"`   // 上一次生成 id 的毫秒内序列号的起始值
+	Stride        int64  `json:"stride,omitempty"` // 序列号的步长，通过 WithSequenceJitter 设置，为 0 时表示 1
+	Epoch         int64  `json:"epoch"`            // 时间偏移量（毫秒）
+	Layout        Layout `json:"layout"`
+	Version       int64  `json:"version"`
+	Region        int64  `json:"region"`
+	DataCenter    int64  `json:"data_center"`
+	Machine       int64  `json:"machine"`
+	Boot          int64  `json:"boot"`
+	Process       int64  `json:"process"`
+}
+
+// ExportState 导出生成器当前的状态，格式为 JSON。
+//
+// 交接时旧的进程需要先停止生成 id 再导出状态，新的进程通过 ImportState 导入之后，不会使用旧的进程已经使用过的时间戳和序列号。
+func (this *SnowFlake) ExportState() ([]byte, error) {
+	this.mu.Lock()
+	var state = State{
+		Millisecond:   this.millisecond,
+		Sequence:      this.sequence,
+		SequenceStart: this.sequenceStart,
+		Stride:        this.stride,
+		Epoch:         this.timeOffset,
+		Layout:        this.Layout(),
+		Version:       this.version,
+		Region:        this.region,
+		DataCenter:    this.dataCenter,
+		Machine:       this.machine,
+		Boot:          this.boot,
+		Process:       this.process,
+	}
+	this.mu.Unlock()
+	return json.Marshal(state)
+}
+
+// ImportState 导入通过 ExportState 导出的状态，导出状态的生成器需要与当前的生成器使用相同的布局、时间偏移量和工作节点标识，否则返回 ErrStateMismatch。
+//
+// 导出的时间戳比当前生成器上一次生成 id 的时间戳新时才会生效，导出的时间戳比当前的时间新时，会按照时钟回拨处理，
+// 在 WithBackwardsTolerance 设置的容忍范围内时继续生成递增的 id。
+func (this *SnowFlake) ImportState(data []byte) error {
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if state.Epoch != this.timeOffset || state.Layout.bits() != this.layout.bits() ||
+		state.Version != this.version || state.Region != this.region || state.DataCenter != this.dataCenter ||
+		state.Machine != this.machine || state.Boot != this.boot || state.Process != this.process {
+		return ErrStateMismatch
+	}
+	if !this.bits.sequence.allow(state.Sequence) || !this.bits.sequence.allow(state.SequenceStart) {
+		return ErrStateMismatch
+	}
+
+	if state.Millisecond > this.millisecond {
+		this.millisecond = state.Millisecond
+		this.sequence = state.Sequence
+		this.sequenceStart = state.SequenceStart
+		if state.Stride == 0 {
+			state.Stride = 1
+		}
+		if state.Stride != this.stride {
+			// 步长不同时无法计算已经使用的序列号，直接用完这一毫秒的序列号
+			this.exhaustSequence()
+		}
+		this.recordEvent(EventStateImported, "millisecond="+strconv.FormatInt(state.Millisecond, 10))
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnowFlake_ImportState(t *testing.T) {
+	var old, _ = New(WithTimeOffset(testEpoch), WithDataCenter(1), WithMachine(2))
+	var last int64
+	for i := 0; i < 100; i++ {
+		last = old.Next()
+	}
+	// 模拟旧的进程使用了超前的时间戳
+	old.millisecond += 50
+
+	var data, err = old.ExportState()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s, _ = New(WithTimeOffset(testEpoch), WithDataCenter(1), WithMachine(2))
+	if err = s.ImportState(data); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		var id = s.Next()
+		if id <= last {
+			t.Fatalf("Next() = %d after importing, want greater than %d", id, last)
+		}
+		last = id
+	}
+	if s.Decode(last).Timestamp+s.timeOffset < old.millisecond {
+		t.Fatalf("Next() used a timestamp before the imported one")
+	}
+
+	// 导入更旧的状态不会生效
+	var millisecond = s.millisecond
+	if err = s.ImportState(data); err != nil || s.millisecond != millisecond {
+		t.Fatalf("ImportState() error = %v, millisecond = %d, want %d", err, s.millisecond, millisecond)
+	}
+}
+
+func TestSnowFlake_ImportStateMismatch(t *testing.T) {
+	var old, _ = New(WithTimeOffset(testEpoch), WithDataCenter(1), WithMachine(2))
+	old.Next()
+	var data, _ = old.ExportState()
+
+	var others = [][]Option{
+		{WithTimeOffset(testEpoch), WithDataCenter(1), WithMachine(3)},
+		{WithTimeOffset(testEpoch.Add(time.Hour)), WithDataCenter(1), WithMachine(2)},
+		{WithTimeOffset(testEpoch), WithLayout(Layout{DataCenter: 4, Machine: 6, Sequence: 12}), WithDataCenter(1), WithMachine(2)},
+	}
+	for i, opts := range others {
+		var s, _ = New(opts...)
+		if err := s.ImportState(data); err != ErrStateMismatch {
+			t.Fatalf("%d: ImportState() error = %v, want %v", i, err, ErrStateMismatch)
+		}
+	}
+
+	var s, _ = New()
+	if err := s.ImportState([]byte("{")); err == nil {
+		t.Fatal("ImportState() should fail on invalid json")
+	}
+}
@@ -0,0 +1,131 @@
+package snowflake
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrStreamBehind = errors.New("snowflake: generator is behind the last id of the stream")
+)
+
+const (
+	kMaxStreamWait = time.Second // 生成器落后于流最后的 id 时最多等待的时长
+)
+
+// StreamID 流中的 id，Seq 为流内从 1 开始连续递增的序号
+type StreamID struct {
+	ID  int64 `json:"id"`
+	Seq int64 `json:"seq"`
+}
+
+// Streams 按照 key 维护多个单调递增的流，如事件溯源中每个聚合的事件流，同一个 key 的 id 严格递增，Seq 连续没有空缺。
+//
+// 同一个 key 的调用是串行的，不同 key 之间互不影响。Do 在持有 key 的锁时执行写入，写入失败时回退 Seq，
+// 可以保证写入的顺序与 id 的顺序一致并且 Seq 没有空缺。进程重启之后需要通过 Restore 从存储中恢复每个流最后的 id 和序号。
+//
+// id 的大小需要与生成的时间一致，不能使用 WithShardBits 和 WithTimeEncryption。
+type Streams struct {
+	generator *SnowFlake
+	mu        sync.Mutex
+	streams   map[string]*stream
+}
+
+type stream struct {
+	mu   sync.Mutex
+	last StreamID
+}
+
+// NewStreams 创建 Streams，使用 generator 生成 id
+func NewStreams(generator *SnowFlake) *Streams {
+	var s = &Streams{}
+	s.generator = generator
+	s.streams = make(map[string]*stream)
+	return s
+}
+
+// NextFor 为 key 生成新的 id，id 大于该 key 之前生成或者恢复的 id，Seq 为上一个序号加 1
+func (this *Streams) NextFor(key string) (StreamID, error) {
+	var id StreamID
+	var err = this.Do(key, func(next StreamID) error {
+		id = next
+		return nil
+	})
+	return id, err
+}
+
+// Do 为 key 生成新的 id 并在持有 key 的锁时调用 fn，同一个 key 的 fn 依次执行，fn 返回错误时放弃生成的 id，Seq 不会增加
+func (this *Streams) Do(key string, fn func(StreamID) error) error {
+	var s = this.stream(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var id, err = this.nextAfter(s.last.ID)
+	if err != nil {
+		return err
+	}
+	var next = StreamID{ID: id, Seq: s.last.Seq + 1}
+	if err = fn(next); err != nil {
+		return err
+	}
+	s.last = next
+	return nil
+}
+
+// Last 获取 key 最后的 id，没有生成或者恢复过时返回零值
+func (this *Streams) Last(key string) StreamID {
+	var s = this.stream(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+// Restore 恢复 key 最后的 id 和序号，如进程重启之后从事件存储中读取每个聚合最后的事件
+func (this *Streams) Restore(key string, last StreamID) {
+	var s = this.stream(key)
+	s.mu.Lock()
+	s.last = last
+	s.mu.Unlock()
+}
+
+// Forget 移除 key 的流，不再使用的 key 需要移除，避免占用的内存一直增长
+func (this *Streams) Forget(key string) {
+	this.mu.Lock()
+	delete(this.streams, key)
+	this.mu.Unlock()
+}
+
+func (this *Streams) stream(key string) *stream {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	var s = this.streams[key]
+	if s == nil {
+		s = &stream{}
+		this.streams[key] = s
+	}
+	return s
+}
+
+// nextAfter 生成大于 last 的 id，last 由其它工作节点生成或者时钟比当前节点快时需要等待，最多等待 1 秒
+func (this *Streams) nextAfter(last int64) (int64, error) {
+	var deadline = time.Now().Add(kMaxStreamWait)
+	for {
+		var id, err = this.generator.NextID()
+		if err != nil {
+			return 0, err
+		}
+		if id > last {
+			return id, nil
+		}
+		var behind = this.generator.TimeOf(last).Sub(this.generator.TimeOf(id))
+		if behind <= 0 {
+			// 同一毫秒内 last 的其它部分更大，使用下一毫秒
+			behind = time.Millisecond
+		}
+		if time.Now().Add(behind).After(deadline) {
+			return 0, ErrStreamBehind
+		}
+		time.Sleep(behind)
+	}
+}
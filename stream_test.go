@@ -0,0 +1,86 @@
+package snowflake
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStreams(t *testing.T) {
+	var s, _ = New()
+	var streams = NewStreams(s)
+
+	var mu sync.Mutex
+	var appended = map[string][]StreamID{}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var key = []string{"order-1", "order-2"}[i%2]
+			for j := 0; j < 100; j++ {
+				var err = streams.Do(key, func(id StreamID) error {
+					// 模拟写入事件存储，同一个 key 的写入依次执行
+					mu.Lock()
+					appended[key] = append(appended[key], id)
+					mu.Unlock()
+					return nil
+				})
+				if err != nil {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	for key, ids := range appended {
+		if len(ids) != 400 {
+			t.Fatalf("%s: %d ids", key, len(ids))
+		}
+		for i, id := range ids {
+			if id.Seq != int64(i+1) || i > 0 && id.ID <= ids[i-1].ID {
+				t.Fatalf("%s: %d: %+v after %+v", key, i, id, ids[i-1])
+			}
+		}
+		if last := streams.Last(key); last != ids[len(ids)-1] {
+			t.Fatalf("%s: Last() = %+v", key, last)
+		}
+	}
+
+	// 写入失败时序号不增加
+	var failed = errors.New("append failed")
+	if err := streams.Do("order-1", func(StreamID) error { return failed }); err != failed {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if id, _ := streams.NextFor("order-1"); id.Seq != 401 {
+		t.Fatalf("NextFor() after a failed append = %+v", id)
+	}
+}
+
+func TestStreams_Restore(t *testing.T) {
+	var s, _ = New()
+	var streams = NewStreams(s)
+
+	// 其它节点的时钟快 20ms，需要等待生成器追上
+	var ahead = s.compose(s.getMillisecond()+20, 0, 0)
+	streams.Restore("order-1", StreamID{ID: ahead, Seq: 7})
+	var id, err = streams.NextFor("order-1")
+	if err != nil || id.ID <= ahead || id.Seq != 8 {
+		t.Fatalf("NextFor() = %+v, %v", id, err)
+	}
+
+	streams.Restore("order-2", StreamID{ID: s.compose(s.getMillisecond()+int64(time.Hour/time.Millisecond), 0, 0), Seq: 1})
+	if _, err = streams.NextFor("order-2"); err != ErrStreamBehind {
+		t.Fatalf("NextFor() error = %v, want %v", err, ErrStreamBehind)
+	}
+
+	streams.Forget("order-1")
+	if id, _ = streams.NextFor("order-1"); id.Seq != 1 {
+		t.Fatalf("NextFor() after Forget = %+v", id)
+	}
+	if len(streams.streams) != 2 {
+		t.Fatalf("streams = %d, want 2", len(streams.streams))
+	}
+}
@@ -0,0 +1,105 @@
+package snowflake
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	ErrNoTicketServer = errors.New("snowflake: no ticket server available")
+)
+
+// TicketServer 基于数据库自增实现的 Flickr 风格的 ticket server。
+//
+// 使用 MySQL 时表结构如下，每次生成 id 时执行 REPLACE INTO 并获取 LAST_INSERT_ID()：
+//
+//	CREATE TABLE tickets64 (
+//		id   BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+//		stub CHAR(1)         NOT NULL DEFAULT '',
+//		PRIMARY KEY (id),
+//		UNIQUE KEY stub (stub)
+//	) ENGINE=InnoDB;
+//
+// 部署两台 ticket server 时，将其中一台配置为 auto_increment_increment=2、auto_increment_offset=1，
+// 另一台配置为 auto_increment_increment=2、auto_increment_offset=2，两台分别生成奇数和偶数 id，再通过 TicketServers 组合使用。
+//
+// 使用 PostgreSQL 时可以直接使用序列，如：CREATE SEQUENCE tickets64 START 1 INCREMENT 2;
+type TicketServer struct {
+	db       *sql.DB
+	query    string
+	args     []interface{}
+	sequence bool
+}
+
+// NewMySQLTicketServer 创建基于 MySQL REPLACE INTO 的 TicketServer，stub 为表中 stub 字段的值
+func NewMySQLTicketServer(db *sql.DB, table, stub string) *TicketServer {
+	var t = &TicketServer{}
+	t.db = db
+	t.query = fmt.Sprintf("REPLACE INTO %s (stub) VALUES (?)", table)
+	t.args = []interface{}{stub}
+	return t
+}
+
+// NewPostgresTicketServer 创建基于 PostgreSQL 序列的 TicketServer
+func NewPostgresTicketServer(db *sql.DB, sequence string) *TicketServer {
+	var t = &TicketServer{}
+	t.db = db
+	t.query = "SELECT nextval($1)"
+	t.args = []interface{}{sequence}
+	t.sequence = true
+	return t
+}
+
+// NextID 生成新的 id
+func (this *TicketServer) NextID() (int64, error) {
+	if this.sequence {
+		var id int64
+		if err := this.db.QueryRow(this.query, this.args...).Scan(&id); err != nil {
+			return 0, err
+		}
+		return id, nil
+	}
+
+	var result, err = this.db.Exec(this.query, this.args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// TicketServers 轮流使用多个 ticket server 生成 id，某个 ticket server 出错时会尝试下一个
+type TicketServers struct {
+	mu      sync.Mutex
+	servers []Generator
+	next    int
+}
+
+// NewTicketServers 创建 TicketServers，通常传入分别生成奇数和偶数 id 的两个 ticket server
+func NewTicketServers(servers ...Generator) *TicketServers {
+	var t = &TicketServers{}
+	t.servers = servers
+	return t
+}
+
+// NextID 生成新的 id，所有 ticket server 都出错时返回最后一个错误
+func (this *TicketServers) NextID() (int64, error) {
+	if len(this.servers) == 0 {
+		return 0, ErrNoTicketServer
+	}
+
+	this.mu.Lock()
+	var start = this.next
+	this.next = (this.next + 1) % len(this.servers)
+	this.mu.Unlock()
+
+	var err error
+	for i := 0; i < len(this.servers); i++ {
+		var id int64
+		if id, err = this.servers[(start+i)%len(this.servers)].NextID(); err == nil {
+			return id, nil
+		}
+	}
+	return 0, err
+}
@@ -0,0 +1,126 @@
+package snowflake
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type sequenceGenerator struct {
+	next int64
+	step int64
+	err  error
+}
+
+func (this *sequenceGenerator) NextID() (int64, error) {
+	if this.err != nil {
+		return 0, this.err
+	}
+	var id = this.next
+	this.next += this.step
+	return id, nil
+}
+
+func TestTicketServers(t *testing.T) {
+	var odd = &sequenceGenerator{next: 1, step: 2}
+	var even = &sequenceGenerator{next: 2, step: 2}
+	var servers = NewTicketServers(odd, even)
+
+	for want := int64(1); want <= 6; want++ {
+		if id, err := servers.NextID(); err != nil || id != want {
+			t.Fatalf("NextID() = %d, %v, want %d", id, err, want)
+		}
+	}
+
+	even.err = errors.New("down")
+	for i := 0; i < 4; i++ {
+		if id, err := servers.NextID(); err != nil || id%2 != 1 {
+			t.Fatalf("NextID() = %d, %v, want an odd id", id, err)
+		}
+	}
+
+	odd.err = even.err
+	if _, err := servers.NextID(); err != even.err {
+		t.Fatalf("NextID() error = %v, want %v", err, even.err)
+	}
+	if _, err := NewTicketServers().NextID(); err != ErrNoTicketServer {
+		t.Fatalf("NextID() error = %v, want %v", err, ErrNoTicketServer)
+	}
+}
+
+// recordDriver 记录执行的 SQL 和参数的 database/sql 驱动，Exec 返回的 LastInsertId 和 Query 返回的值都是 42
+type recordDriver struct {
+	query string
+	args  []driver.Value
+}
+
+func (this *recordDriver) Open(name string) (driver.Conn, error) { return this, nil }
+func (this *recordDriver) Prepare(query string) (driver.Stmt, error) {
+	this.query = query
+	return this, nil
+}
+func (this *recordDriver) Close() error              { return nil }
+func (this *recordDriver) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+func (this *recordDriver) NumInput() int             { return -1 }
+func (this *recordDriver) Exec(args []driver.Value) (driver.Result, error) {
+	this.args = args
+	return driver.Result(recordResult{}), nil
+}
+func (this *recordDriver) Query(args []driver.Value) (driver.Rows, error) {
+	this.args = args
+	return &recordRows{}, nil
+}
+
+type recordResult struct{}
+
+func (recordResult) LastInsertId() (int64, error) { return 42, nil }
+func (recordResult) RowsAffected() (int64, error) { return 1, nil }
+
+type recordRows struct {
+	done bool
+}
+
+func (this *recordRows) Columns() []string { return []string{"id"} }
+func (this *recordRows) Close() error      { return nil }
+func (this *recordRows) Next(dest []driver.Value) error {
+	if this.done {
+		return io.EOF
+	}
+	this.done = true
+	dest[0] = int64(42)
+	return nil
+}
+
+func TestTicketServer_Args(t *testing.T) {
+	var d = &recordDriver{}
+	sql.Register("snowflake-ticket-test", d)
+	var db, err = sql.Open("snowflake-ticket-test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var tests = []struct {
+		server *TicketServer
+		query  string
+		arg    string
+	}{
+		{NewMySQLTicketServer(db, "tickets64", "a'b"), "REPLACE INTO tickets64 (stub) VALUES (?)", "a'b"},
+		{NewPostgresTicketServer(db, "tickets64"), "SELECT nextval($1)", "tickets64"},
+	}
+	for _, test := range tests {
+		var id, err = test.server.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != 42 {
+			t.Fatalf("NextID() = %d, want 42", id)
+		}
+		if d.query != test.query || !reflect.DeepEqual(d.args, []driver.Value{test.arg}) {
+			t.Fatalf("executed %q %v, want %q [%s]", d.query, d.args, test.query, test.arg)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package snowflake
+
+import (
+	"time"
+)
+
+// FormatTime 按照 layout 格式化 id 在 loc 时区的生成时间，loc 为 nil 时使用 UTC
+func (this *SnowFlake) FormatTime(s int64, loc *time.Location, layout string) string {
+	return inLocation(this.TimeOf(s), loc).Format(layout)
+}
+
+// DayOf 获取 id 在 loc 时区所属的日期，即生成时间当天 0 点，loc 为 nil 时使用 UTC。
+//
+// 如 loc 为 Asia/Shanghai 时，UTC 时间 2024-06-10T16:30:00Z 生成的 id 属于 2024-06-11。
+func (this *SnowFlake) DayOf(s int64, loc *time.Location) time.Time {
+	var t = inLocation(this.TimeOf(s), loc)
+	var year, month, day = t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc)
+}
+
+// FormatTime 按照 layout 格式化 id 在 loc 时区的生成时间，使用默认生成器的时间偏移量
+func FormatTime(s int64, loc *time.Location, layout string) string {
+	return getDecoder().FormatTime(s, loc, layout)
+}
+
+// DayOf 获取 id 在 loc 时区所属的日期，使用默认生成器的时间偏移量
+func DayOf(s int64, loc *time.Location) time.Time {
+	return getDecoder().DayOf(s, loc)
+}
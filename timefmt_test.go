@@ -0,0 +1,28 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnowFlake_DayOf(t *testing.T) {
+	var shanghai = time.FixedZone("Asia/Shanghai", 8*3600)
+	var s, _ = New(WithTimeOffset(testEpoch))
+	var at = time.Date(2024, 6, 10, 16, 30, 0, 0, time.UTC)
+	var id = s.bits.time.put(at.UnixNano()/1e6 - s.timeOffset)
+
+	var day = s.DayOf(id, shanghai)
+	if !day.Equal(time.Date(2024, 6, 11, 0, 0, 0, 0, shanghai)) || day.Location() != shanghai {
+		t.Fatalf("DayOf() = %v", day)
+	}
+	if day = s.DayOf(id, nil); !day.Equal(time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("DayOf(nil) = %v", day)
+	}
+
+	if got := s.FormatTime(id, shanghai, "2006-01-02 15:04:05"); got != "2024-06-11 00:30:00" {
+		t.Fatalf("FormatTime() = %q", got)
+	}
+	if got := s.FormatTime(id, nil, time.RFC3339); got != "2024-06-10T16:30:00Z" {
+		t.Fatalf("FormatTime(nil) = %q", got)
+	}
+}
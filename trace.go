@@ -0,0 +1,106 @@
+package snowflake
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	kTraceVersion = "00"
+	kTraceSampled = 0x01
+)
+
+var (
+	ErrInvalidTraceparent = errors.New("snowflake: invalid traceparent")
+)
+
+// TraceContext W3C Trace Context 中 traceparent 头的内容，用于将 id 与分布式追踪关联起来。
+//
+// 通过 NewTraceContext 创建时，id 会保存在 trace-id 的前 8 个字节中，后 8 个字节是随机数，span-id 与 id 相同，
+// 这样使用 id 记录的日志可以直接与追踪系统中的 trace 对应，不需要额外的映射表。
+type TraceContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Flags   byte
+}
+
+// NewTraceContext 创建包含 id 的 TraceContext，默认标记为已采样
+func NewTraceContext(id int64) (TraceContext, error) {
+	var t = TraceContext{Flags: kTraceSampled}
+	binary.BigEndian.PutUint64(t.TraceID[:8], uint64(id))
+	if _, err := crand.Read(t.TraceID[8:]); err != nil {
+		return t, err
+	}
+	binary.BigEndian.PutUint64(t.SpanID[:], uint64(id))
+	return t, nil
+}
+
+// ParseTraceparent 解析 traceparent 头，如：00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01
+func ParseTraceparent(s string) (TraceContext, error) {
+	var t TraceContext
+	var parts = strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == kTraceVersion && len(parts) != 4) {
+		return t, ErrInvalidTraceparent
+	}
+	if !decodeTraceHex(t.TraceID[:], parts[1]) || !decodeTraceHex(t.SpanID[:], parts[2]) {
+		return t, ErrInvalidTraceparent
+	}
+	var flags [1]byte
+	if !decodeTraceHex(flags[:], parts[3]) {
+		return t, ErrInvalidTraceparent
+	}
+	t.Flags = flags[0]
+	if t.TraceID == ([16]byte{}) || t.SpanID == ([8]byte{}) {
+		return t, ErrInvalidTraceparent
+	}
+	return t, nil
+}
+
+// decodeTraceHex 解码小写的十六进制字符串，长度需要与 dst 一致
+func decodeTraceHex(dst []byte, s string) bool {
+	if len(s) != len(dst)*2 || strings.ToLower(s) != s {
+		return false
+	}
+	var _, err = hex.Decode(dst, []byte(s))
+	return err == nil
+}
+
+// String 返回 traceparent 头的值
+func (this TraceContext) String() string {
+	var b = make([]byte, 0, 55)
+	b = append(b, kTraceVersion...)
+	b = append(b, '-')
+	b = append(b, hex.EncodeToString(this.TraceID[:])...)
+	b = append(b, '-')
+	b = append(b, hex.EncodeToString(this.SpanID[:])...)
+	b = append(b, '-')
+	b = append(b, hex.EncodeToString([]byte{this.Flags})...)
+	return string(b)
+}
+
+// ID 获取 trace-id 中保存的 id，trace-id 的前 8 个字节不能表示一个非负的 id 时（如由其它系统生成的 trace-id）返回 false
+func (this TraceContext) ID() (int64, bool) {
+	return traceBytesID(this.TraceID[:8])
+}
+
+// SpanIDValue 获取 span-id 表示的 id，span-id 不能表示一个非负的 id 时返回 false
+func (this TraceContext) SpanIDValue() (int64, bool) {
+	return traceBytesID(this.SpanID[:])
+}
+
+// WithSpan 返回使用 id 作为 span-id 的 TraceContext，用于在同一个 trace 中为下游的调用创建新的 span
+func (this TraceContext) WithSpan(id int64) TraceContext {
+	binary.BigEndian.PutUint64(this.SpanID[:], uint64(id))
+	return this
+}
+
+func traceBytesID(b []byte) (int64, bool) {
+	var id = int64(binary.BigEndian.Uint64(b))
+	if id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,69 @@
+package snowflake
+
+import (
+	"testing"
+)
+
+func TestTraceContext(t *testing.T) {
+	var s, _ = New()
+	var id = s.Next()
+
+	var tc, err = NewTraceContext(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseTraceparent(tc.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != tc {
+		t.Fatalf("ParseTraceparent(%q) = %v, want %v", tc.String(), parsed, tc)
+	}
+	if v, ok := parsed.ID(); !ok || v != id {
+		t.Fatalf("ID() = %d, %v, want %d", v, ok, id)
+	}
+	if v, ok := parsed.SpanIDValue(); !ok || v != id {
+		t.Fatalf("SpanIDValue() = %d, %v, want %d", v, ok, id)
+	}
+
+	var next = s.Next()
+	var child = parsed.WithSpan(next)
+	if v, _ := child.SpanIDValue(); v != next || child.TraceID != tc.TraceID {
+		t.Fatalf("WithSpan(%d) = %v", next, child)
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	var tc, err = ParseTraceparent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tc.Flags != 1 || tc.String() != "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01" {
+		t.Fatalf("ParseTraceparent() = %v", tc)
+	}
+	if id, ok := tc.ID(); !ok || id != 0x0af7651916cd43dd {
+		t.Fatalf("ID() = %x, %v", id, ok)
+	}
+	if _, ok := tc.SpanIDValue(); ok {
+		t.Fatal("SpanIDValue() should not accept a span id with the high bit set")
+	}
+
+	var invalid = []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+		"00-0AF7651916CD43DD8448EB211C80319C-b7ad6b7169203331-01",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra",
+	}
+	for _, s := range invalid {
+		if _, err := ParseTraceparent(s); err != ErrInvalidTraceparent {
+			t.Fatalf("ParseTraceparent(%q) error = %v, want %v", s, err, ErrInvalidTraceparent)
+		}
+	}
+	// 更高的版本可以包含额外的字段
+	if _, err := ParseTraceparent("01-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra"); err != nil {
+		t.Fatalf("ParseTraceparent() error = %v", err)
+	}
+}
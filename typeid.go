@@ -0,0 +1,217 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"strings"
+)
+
+const (
+	kTypeIDAlphabet     = "0123456789abcdefghjkmnpqrstvwxyz"
+	kTypeIDSuffixLength = 26
+	kTypeIDMaxPrefix    = 63
+	kTypeIDVersionBits  = 12 // UUIDv7 中 rand_a 的位数，用于保存 id 的布局版本
+)
+
+var (
+	ErrInvalidTypeID       = errors.New("snowflake: invalid typeid")
+	ErrInvalidTypeIDPrefix = errors.New("snowflake: typeid prefix must be at most 63 lowercase letters or underscores, and can't start or end with an underscore")
+	ErrTypeIDNotSnowFlake  = errors.New("snowflake: typeid was not generated from a snowflake id")
+	ErrTypeIDVersionBits   = errors.New("snowflake: typeid can't hold more than 12 version bits")
+)
+
+var typeIDIndex = func() (m [256]int8) {
+	for i := range m {
+		m[i] = -1
+	}
+	for i := 0; i < len(kTypeIDAlphabet); i++ {
+		m[kTypeIDAlphabet[i]] = int8(i)
+	}
+	return m
+}()
+
+// TypeID 实现了 TypeID 规范（https://github.com/jetify-com/typeid），由类型前缀和 UUIDv7 组成，如：user_01h455vb4pex5vsknk084sn02q
+type TypeID struct {
+	prefix string
+	uuid   [16]byte
+}
+
+// NewTypeID 使用类型前缀和 UUID 创建 TypeID，prefix 可以为空
+func NewTypeID(prefix string, uuid [16]byte) (TypeID, error) {
+	if !validTypeIDPrefix(prefix) {
+		return TypeID{}, ErrInvalidTypeIDPrefix
+	}
+	return TypeID{prefix: prefix, uuid: uuid}, nil
+}
+
+// ParseTypeID 解析 TypeID 字符串
+func ParseTypeID(s string) (TypeID, error) {
+	var prefix, suffix = "", s
+	if idx := strings.LastIndexByte(s, '_'); idx >= 0 {
+		prefix, suffix = s[:idx], s[idx+1:]
+		if len(prefix) == 0 {
+			return TypeID{}, ErrInvalidTypeIDPrefix
+		}
+	}
+	if !validTypeIDPrefix(prefix) {
+		return TypeID{}, ErrInvalidTypeIDPrefix
+	}
+
+	if len(suffix) != kTypeIDSuffixLength || suffix[0] > '7' {
+		return TypeID{}, ErrInvalidTypeID
+	}
+
+	// 后缀是 130 位的 base32 编码，最高的 2 位固定为 0
+	var hi, lo uint64
+	for i := 0; i < len(suffix); i++ {
+		var v = typeIDIndex[suffix[i]]
+		if v < 0 {
+			return TypeID{}, ErrInvalidTypeID
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+
+	var t = TypeID{prefix: prefix}
+	binary.BigEndian.PutUint64(t.uuid[0:8], hi)
+	binary.BigEndian.PutUint64(t.uuid[8:16], lo)
+	return t, nil
+}
+
+// Prefix 获取类型前缀
+func (t TypeID) Prefix() string {
+	return t.prefix
+}
+
+// UUID 获取 TypeID 的 UUID 部分
+func (t TypeID) UUID() [16]byte {
+	return t.uuid
+}
+
+// String 获取 TypeID 的字符串形式
+func (t TypeID) String() string {
+	var hi = binary.BigEndian.Uint64(t.uuid[0:8])
+	var lo = binary.BigEndian.Uint64(t.uuid[8:16])
+
+	var b = make([]byte, 0, len(t.prefix)+1+kTypeIDSuffixLength)
+	if len(t.prefix) > 0 {
+		b = append(b, t.prefix...)
+		b = append(b, '_')
+	}
+	for i := kTypeIDSuffixLength - 1; i >= 0; i-- {
+		var shift = uint(i * 5)
+		var v uint64
+		switch {
+		case shift >= 64:
+			v = hi >> (shift - 64)
+		case shift > 59:
+			v = lo>>shift | hi<<(64-shift)
+		default:
+			v = lo >> shift
+		}
+		b = append(b, kTypeIDAlphabet[v&0x1f])
+	}
+	return string(b)
+}
+
+// TypeID 将 id 转换为 TypeID。
+//
+// UUIDv7 的时间戳部分为 id 的生成时间，rand_a 保存 id 的布局版本，rand_b 的低位依次保存 id 中时间戳和版本以外的部分，
+// 包括分片以及通过 Layout.Order 放在时间戳之前的部分，所以同一个 id 总是得到相同的 TypeID，并且可以通过 FromTypeID 转换回原始 id。
+func (this *SnowFlake) TypeID(prefix string, s int64) (TypeID, error) {
+	if !validTypeIDPrefix(prefix) {
+		return TypeID{}, ErrInvalidTypeIDPrefix
+	}
+	if this.layout.Version > kTypeIDVersionBits {
+		return TypeID{}, ErrTypeIDVersionBits
+	}
+	var mill = this.bits.time.get(s) + this.timeOffset
+	var version = uint64(this.bits.version.get(s))
+	var rest = compactBits(uint64(s), this.typeIDMask())
+
+	var t = TypeID{prefix: prefix}
+	binary.BigEndian.PutUint64(t.uuid[0:8], uint64(mill)<<16|0x7<<12|version)
+	binary.BigEndian.PutUint64(t.uuid[8:16], 0x2<<62|rest)
+	return t, nil
+}
+
+// NextTypeID 生成新的 id 并转换为 TypeID
+func (this *SnowFlake) NextTypeID(prefix string) (TypeID, error) {
+	if !validTypeIDPrefix(prefix) {
+		return TypeID{}, ErrInvalidTypeIDPrefix
+	}
+	if this.layout.Version > kTypeIDVersionBits {
+		return TypeID{}, ErrTypeIDVersionBits
+	}
+	var s, err = this.next(0)
+	if err != nil {
+		return TypeID{}, err
+	}
+	return this.TypeID(prefix, s)
+}
+
+// FromTypeID 将由 TypeID 方法生成的 TypeID 转换回 id
+func (this *SnowFlake) FromTypeID(t TypeID) (int64, error) {
+	var hi = binary.BigEndian.Uint64(t.uuid[0:8])
+	var lo = binary.BigEndian.Uint64(t.uuid[8:16])
+
+	var mask = this.typeIDMask()
+	var restBits = 63 - uint(bits.OnesCount64(mask))
+	if hi&0xf000 != 0x7<<12 || lo>>restBits != 0x2<<(62-restBits) {
+		return 0, ErrTypeIDNotSnowFlake
+	}
+	var version = int64(hi & 0xfff)
+	var mill = int64(hi>>16) - this.timeOffset
+	if !this.bits.version.allow(version) || !this.bits.time.allow(mill) {
+		return 0, ErrTypeIDNotSnowFlake
+	}
+	var rest = expandBits(lo&(1<<restBits-1), mask)
+	return this.bits.version.put(version) | this.bits.time.put(mill) | int64(rest), nil
+}
+
+// typeIDMask 获取 id 中分别保存在 UUIDv7 的时间戳和 rand_a 中的时间戳和版本的位置，其余的位保存在 rand_b 中
+func (this *SnowFlake) typeIDMask() uint64 {
+	return uint64(this.bits.time.max)<<this.bits.time.shift | uint64(this.bits.version.max)<<this.bits.version.shift
+}
+
+// compactBits 按照从低到高的顺序取出 v 中 skip 以外的位，id 只使用低 63 位
+func compactBits(v, skip uint64) uint64 {
+	var r uint64
+	var n uint
+	for i := uint(0); i < 63; i++ {
+		if skip>>i&1 == 0 {
+			r |= (v >> i & 1) << n
+			n++
+		}
+	}
+	return r
+}
+
+// expandBits 是 compactBits 的逆运算，将 v 的低位依次放回 skip 以外的位置
+func expandBits(v, skip uint64) uint64 {
+	var r uint64
+	var n uint
+	for i := uint(0); i < 63; i++ {
+		if skip>>i&1 == 0 {
+			r |= (v >> n & 1) << i
+			n++
+		}
+	}
+	return r
+}
+
+func validTypeIDPrefix(prefix string) bool {
+	if len(prefix) > kTypeIDMaxPrefix {
+		return false
+	}
+	if len(prefix) > 0 && (prefix[0] == '_' || prefix[len(prefix)-1] == '_') {
+		return false
+	}
+	for i := 0; i < len(prefix); i++ {
+		if (prefix[i] < 'a' || prefix[i] > 'z') && prefix[i] != '_' {
+			return false
+		}
+	}
+	return true
+}
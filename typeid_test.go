@@ -0,0 +1,123 @@
+package snowflake
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestParseTypeID(t *testing.T) {
+	// 来自 TypeID 规范的示例
+	var tests = []struct {
+		typeID string
+		prefix string
+		uuid   string
+	}{
+		{"00000000000000000000000000", "", "00000000-0000-0000-0000-000000000000"},
+		{"0000000000000000000000000g", "", "00000000-0000-0000-0000-000000000010"},
+		{"7zzzzzzzzzzzzzzzzzzzzzzzzz", "", "ffffffff-ffff-ffff-ffff-ffffffffffff"},
+		{"prefix_01h455vb4pex5vsknk084sn02q", "prefix", "01890a5d-ac96-774b-bcce-b302099a8057"},
+	}
+	for _, test := range tests {
+		var tid, err = ParseTypeID(test.typeID)
+		if err != nil {
+			t.Fatalf("ParseTypeID(%q) error = %v", test.typeID, err)
+		}
+		var uuid = tid.UUID()
+		if tid.Prefix() != test.prefix || hex.EncodeToString(uuid[:]) != strings.Replace(test.uuid, "-", "", -1) {
+			t.Fatalf("ParseTypeID(%q) = %s %x, want %s %s", test.typeID, tid.Prefix(), uuid, test.prefix, test.uuid)
+		}
+		if tid.String() != test.typeID {
+			t.Fatalf("String() = %q, want %q", tid.String(), test.typeID)
+		}
+	}
+
+	var bad = []string{"", "prefix_", "_01h455vb4pex5vsknk084sn02q", "PREFIX_01h455vb4pex5vsknk084sn02q", "8zzzzzzzzzzzzzzzzzzzzzzzzz", "01h455vb4pex5vsknk084sn02u"}
+	for _, s := range bad {
+		if _, err := ParseTypeID(s); err == nil {
+			t.Fatalf("ParseTypeID(%q) should fail", s)
+		}
+	}
+}
+
+func TestSnowFlake_TypeID(t *testing.T) {
+	var s, _ = New(WithDataCenter(1), WithMachine(2))
+	var tid, err = s.NextTypeID("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var parsed, _ = ParseTypeID(tid.String())
+	var id, _ = s.FromTypeID(parsed)
+	var again, _ = s.TypeID("user", id)
+	if again != tid {
+		t.Fatalf("TypeID(%d) = %s, want %s", id, again, tid)
+	}
+	if DataCenter(id) != 1 || Machine(id) != 2 {
+		t.Fatalf("FromTypeID(%s) = %s", tid, s.Explain(id))
+	}
+
+	var foreign, _ = ParseTypeID("prefix_01h455vb4pex5vsknk084sn02q")
+	if _, err := s.FromTypeID(foreign); err != ErrTypeIDNotSnowFlake {
+		t.Fatalf("FromTypeID(%s) error = %v, want %v", foreign, err, ErrTypeIDNotSnowFlake)
+	}
+}
+
+func TestSnowFlake_TypeIDVersion(t *testing.T) {
+	var s, err = New(WithTimeOffset(testEpoch), WithVersionBits(2), WithVersion(2), WithMachine(7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var id = s.Next()
+	var tid, _ = s.TypeID("order", id)
+	var parsed, _ = ParseTypeID(tid.String())
+	if got, err := s.FromTypeID(parsed); err != nil || got != id {
+		t.Fatalf("FromTypeID(TypeID(%d)) = %d, %v", id, got, err)
+	}
+
+	var other, _ = New(WithTimeOffset(testEpoch), WithVersionBits(2), WithVersion(1))
+	if _, err := other.FromTypeID(tid); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := other.FromTypeID(tid); other.Decode(got).Version != 2 {
+		t.Fatalf("FromTypeID(%s).Version = %d, want 2", tid, other.Decode(got).Version)
+	}
+
+	var wide, werr = New(WithTimeOffset(testEpoch), WithLayout(Layout{Version: 13, Sequence: 8}))
+	if werr != nil {
+		t.Fatal(werr)
+	}
+	if _, err := wide.TypeID("order", wide.Next()); err != ErrTypeIDVersionBits {
+		t.Fatalf("TypeID() error = %v, want %v", err, ErrTypeIDVersionBits)
+	}
+}
+
+func TestSnowFlake_TypeIDShard(t *testing.T) {
+	var s, err = New(WithEpochString("2024-01-01T00:00:00Z"), WithShardBits(3), WithMachine(5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 64; i++ {
+		var id = s.Next()
+		var tid, _ = s.TypeID("order", id)
+		var parsed, _ = ParseTypeID(tid.String())
+		if got, err := s.FromTypeID(parsed); err != nil || got != id {
+			t.Fatalf("FromTypeID(TypeID(%d)) = %d, %v", id, got, err)
+		}
+	}
+
+	// 通过 Layout.Order 放在时间戳之前的部分
+	var ordered, oerr = New(WithTimeOffset(testEpoch), WithLayout(Layout{
+		Version: 2, Region: 3, DataCenter: 3, Machine: 3, Sequence: 10,
+		Order: []Part{PartRegion, PartVersion, PartTime, PartDataCenter, PartMachine, PartSequence},
+	}), WithRegion(6), WithVersion(1))
+	if oerr != nil {
+		t.Fatal(oerr)
+	}
+	var id = ordered.Next()
+	var tid, _ = ordered.TypeID("order", id)
+	if got, err := ordered.FromTypeID(tid); err != nil || got != id {
+		t.Fatalf("FromTypeID(TypeID(%d)) = %d, %v", id, got, err)
+	}
+}
@@ -0,0 +1,112 @@
+package snowflake
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"time"
+)
+
+var (
+	ErrWaitTimeout = errors.New("snowflake: timed out waiting for the clock to move forward")
+)
+
+const (
+	kWaitSleepInterval = 100 * time.Microsecond // WaitSleep 每次休眠的时长
+)
+
+// WaitStrategy 当前毫秒的序列号用完，或者在容忍范围内的时钟回拨期间需要等待时钟前进时的等待方式。
+//
+// 需要等待超过 1 毫秒时，不论使用哪种方式，都会先休眠到最后 1 毫秒，等待方式只影响最后 1 毫秒的等待。
+type WaitStrategy int
+
+const (
+	WaitYield WaitStrategy = iota // 循环检查时钟，每次检查之后调用 runtime.Gosched 让出处理器，默认的等待方式
+	WaitSpin                      // 循环检查时钟，不让出处理器，延迟最低，但是会占满一个核，适用于对延迟敏感的服务
+	WaitSleep                     // 每次休眠 100 微秒之后检查时钟，适用于批处理等对延迟不敏感的服务
+	WaitTimer                     // 按照时钟计算到下一毫秒的时长，休眠一次，休眠的精度取决于操作系统的定时器
+)
+
+// WithWaitStrategy 设置等待时钟前进的方式，默认为 WaitYield
+func WithWaitStrategy(strategy WaitStrategy) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		s.waitStrategy = strategy
+		return nil
+	})
+}
+
+// WithMaxWait 设置一次生成 id 最多等待时钟前进的时长，包括等待下一毫秒、容忍范围内的时钟回拨以及 PolicyWait 的等待，
+// 超过之后返回 ErrWaitTimeout，Next 返回 -1，避免请求一直阻塞，默认为 0，不限制等待的时长
+func WithMaxWait(d time.Duration) Option {
+	return optionFunc(func(s *SnowFlake) error {
+		if d < 0 {
+			d = 0
+		}
+		s.maxWait = d
+		return nil
+	})
+}
+
+// waiter 一次生成 id 等待时钟前进使用的配置，需要在持有 mu 时通过 newWaiter 获取，避免等待期间与 Reload 同时修改配置
+type waiter struct {
+	ready    bool
+	strategy WaitStrategy
+	maxWait  time.Duration
+	deadline time.Time
+}
+
+// newWaiter 获取等待使用的配置，调用方需要持有 mu
+func (this *SnowFlake) newWaiter() waiter {
+	return waiter{ready: true, strategy: this.waitStrategy, maxWait: this.maxWait}
+}
+
+// waitAfter 等待时钟超过 millisecond，同一个 waiter 多次等待的总时长超过 maxWait 时返回 ErrWaitTimeout
+func (this *SnowFlake) waitAfter(millisecond int64, w *waiter) error {
+	if w.maxWait > 0 && w.deadline.IsZero() {
+		w.deadline = time.Now().Add(w.maxWait)
+	}
+	for {
+		// 时钟超过 millisecond 还需要经过的时长
+		var remaining = time.Duration(millisecond+1-this.anchorMill)*time.Millisecond - this.clock().Sub(this.anchor)
+		if remaining <= 0 {
+			return nil
+		}
+		var left time.Duration = -1
+		if !w.deadline.IsZero() {
+			if left = time.Until(w.deadline); left <= 0 {
+				return ErrWaitTimeout
+			}
+		}
+		if remaining > time.Millisecond {
+			var d = remaining - time.Millisecond
+			if left >= 0 && d > left {
+				d = left
+			}
+			time.Sleep(d)
+			continue
+		}
+		switch w.strategy {
+		case WaitSpin:
+		case WaitSleep:
+			time.Sleep(kWaitSleepInterval)
+		case WaitTimer:
+			time.Sleep(remaining)
+		default:
+			runtime.Gosched()
+		}
+	}
+}
+
+func parseWaitStrategy(s string) (WaitStrategy, error) {
+	switch strings.ToLower(s) {
+	case "yield":
+		return WaitYield, nil
+	case "spin":
+		return WaitSpin, nil
+	case "sleep":
+		return WaitSleep, nil
+	case "timer":
+		return WaitTimer, nil
+	}
+	return WaitYield, errors.New("snowflake: wait strategy should be yield, spin, sleep or timer")
+}
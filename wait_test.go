@@ -0,0 +1,94 @@
+package snowflake
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithWaitStrategy(t *testing.T) {
+	for _, strategy := range []WaitStrategy{WaitYield, WaitSpin, WaitSleep, WaitTimer} {
+		var s, _ = New(WithWaitStrategy(strategy))
+		// 用完序列号之前时钟停止，之后每次读取时钟前进 100µs，与机器的速度无关
+		var now = s.anchor.Add(time.Millisecond)
+		var ticks int64 = -1
+		s.clock = func() time.Time {
+			if atomic.LoadInt64(&ticks) < 0 {
+				return now
+			}
+			return now.Add(time.Duration(atomic.AddInt64(&ticks, 1)-1) * 100 * time.Microsecond)
+		}
+
+		var last int64
+		for i := 0; i < 2*int(kMaxSequence+1); i++ {
+			if i == int(kMaxSequence+1) {
+				atomic.StoreInt64(&ticks, 0)
+			}
+			var id = s.Next()
+			if id <= last {
+				t.Fatalf("strategy %d: id %d after %d", strategy, id, last)
+			}
+			last = id
+		}
+		if s.Stats().SequenceWaits == 0 {
+			t.Fatalf("strategy %d: sequence never exhausted", strategy)
+		}
+	}
+}
+
+func TestSnowFlake_waitAfter(t *testing.T) {
+	// 需要等待较长时间时先休眠，不会一直占用处理器
+	var s, _ = New(WithWaitStrategy(WaitSpin))
+	var start = time.Now()
+	var w = s.newWaiter()
+	if err := s.waitAfter(s.getMillisecond()+20, &w); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Fatalf("waitAfter returned after %v", elapsed)
+	}
+
+	var cfg = Config{WaitStrategy: "timer"}
+	if s, _ = NewWithConfig(cfg); s.waitStrategy != WaitTimer {
+		t.Fatalf("wait strategy = %d", s.waitStrategy)
+	}
+}
+
+func TestWithMaxWait(t *testing.T) {
+	// 时钟停止时用完序列号之后等待不到下一毫秒
+	var s, _ = New(WithMaxWait(20 * time.Millisecond))
+	var now = s.anchor
+	s.clock = func() time.Time { return now }
+	for i := int64(0); i <= kMaxSequence; i++ {
+		s.Next()
+	}
+	var start = time.Now()
+	if _, err := s.NextID(); !errors.Is(err, ErrWaitTimeout) {
+		t.Fatalf("NextID() error = %v, want %v", err, ErrWaitTimeout)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond || elapsed > time.Second {
+		t.Fatalf("NextID() returned after %v", elapsed)
+	}
+	if s.Next() != -1 {
+		t.Fatal("Next() should return -1 after the max wait")
+	}
+	if _, err := s.NextBlock(10); !errors.Is(err, ErrWaitTimeout) {
+		t.Fatalf("NextBlock() error = %v, want %v", err, ErrWaitTimeout)
+	}
+
+	// PolicyWait 等待时钟追上的时长同样受到限制
+	s, _ = New(WithBackwardsTolerance(0), WithRollbackPolicy(PolicyWait), WithMaxWait(10*time.Millisecond))
+	s.Next()
+	s.mu.Lock()
+	s.millisecond += 5000
+	s.mu.Unlock()
+	if _, err := s.NextID(); !errors.Is(err, ErrWaitTimeout) {
+		t.Fatalf("NextID() with PolicyWait error = %v, want %v", err, ErrWaitTimeout)
+	}
+
+	var cfg = Config{MaxWait: "50ms"}
+	if s, _ = NewWithConfig(cfg); s.maxWait != 50*time.Millisecond {
+		t.Fatalf("max wait = %v", s.maxWait)
+	}
+}